@@ -0,0 +1,107 @@
+package nvml
+
+// DeviceGetAccountingMode queries the state of per process accounting mode.
+// For Kepler or newer fully supported devices.
+// See DeviceGetAccountingStats for more details. See DeviceSetAccountingMode.
+func (a API) DeviceGetAccountingMode(device Device) (enabled bool, err error) {
+	var state int32
+	err = a.call(a.nvmlDeviceGetAccountingMode, uintptr(device), &state)
+	enabled = state != 0
+	return
+}
+
+// accountingStats mirrors the nvmlAccountingStats_t layout.
+type accountingStats struct {
+	GPUUtilization    uint32
+	MemoryUtilization uint32
+	MaxMemoryUsage    uint64
+	Time              uint64
+	StartTime         uint64
+	IsRunning         uint32
+	Reserved          [5]uint32
+}
+
+// DeviceGetAccountingStats queries process's accounting stats.
+// For Kepler or newer fully supported devices.
+// Accounting stats capture GPU utilization and other statistics across the lifetime of a process.
+// Accounting stats can be queried during life time of the process and after its termination.
+// The time field in AccountingStats is reported as 0 during the lifetime of the process and updated to actual running
+// time after its termination. Accounting stats are kept in a circular buffer, newly created processes overwrite
+// information about old processes.
+// Accounting Mode needs to be on. See DeviceGetAccountingMode.
+// Only compute and graphics applications stats can be queried. Monitoring applications stats can't be queried since
+// they don't contribute to GPU utilization.
+// In case of pid collision stats of only the latest process (that terminated last) will be reported.
+// On Kepler devices per process statistics are accurate only if there's one process running on a GPU.
+func (a API) DeviceGetAccountingStats(device Device, pid uint32) (stats AccountingStats, err error) {
+	var raw accountingStats
+	err = a.call(a.nvmlDeviceGetAccountingStats, uintptr(device), uintptr(pid), &raw)
+	if err != nil {
+		return
+	}
+
+	stats = AccountingStats{
+		GPUUtilization:    raw.GPUUtilization,
+		MemoryUtilization: raw.MemoryUtilization,
+		MaxMemoryUsage:    raw.MaxMemoryUsage,
+		Time:              raw.Time,
+		StartTime:         raw.StartTime,
+		IsRunning:         raw.IsRunning != 0,
+	}
+
+	return
+}
+
+// DeviceGetAccountingPids queries list of processes that can be queried for accounting stats.
+// The list of processes returned can be in running or terminated state.
+// For Kepler or newer fully supported devices.
+// In case of PID collision some processes might not be accessible before the circular buffer is full.
+func (a API) DeviceGetAccountingPids(device Device) ([]uint32, error) {
+	// Get array size
+	var count uint32
+	err := a.call(a.nvmlDeviceGetAccountingPids, uintptr(device), &count, 0)
+	if err != nil && err != ErrInsufficientSize {
+		return nil, err
+	}
+
+	if count == 0 {
+		return []uint32{}, nil
+	}
+
+	pids := make([]uint32, count)
+	if err := a.call(a.nvmlDeviceGetAccountingPids, uintptr(device), &count, pids); err != nil {
+		return nil, err
+	}
+
+	return pids[:count], nil
+}
+
+// DeviceGetAccountingBufferSize returns the number of processes that the circular buffer with accounting pids can hold.
+// For Kepler or newer fully supported devices.
+// This is the maximum number of processes that accounting information will be stored for before information about
+// oldest processes will get overwritten by information about new processes.
+func (a API) DeviceGetAccountingBufferSize(device Device) (bufferSize uint32, err error) {
+	err = a.call(a.nvmlDeviceGetAccountingBufferSize, uintptr(device), &bufferSize)
+	return
+}
+
+// DeviceSetAccountingMode enables or disables per process accounting.
+// For Kepler or newer fully supported devices. Requires root/admin permissions.
+// This setting is not persistent and will default to disabled after driver unloads. Enable persistence mode to be
+// sure the setting doesn't switch off to disabled.
+// Enabling accounting mode has no negative impact on the GPU performance.
+// Disabling accounting clears all accounting pids information.
+func (a API) DeviceSetAccountingMode(device Device, mode bool) error {
+	var modeInt int32
+	if mode {
+		modeInt = 1
+	}
+
+	return a.call(a.nvmlDeviceSetAccountingMode, uintptr(device), uintptr(modeInt))
+}
+
+// DeviceClearAccountingPids clears accounting information about all processes that have already terminated.
+// For Kepler or newer fully supported devices. Requires root/admin permissions.
+func (a API) DeviceClearAccountingPids(device Device) error {
+	return a.call(a.nvmlDeviceClearAccountingPids, uintptr(device))
+}
@@ -0,0 +1,67 @@
+// +build linux,cgo
+
+package nvml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The accounting entry points of the stub library, see newStubLibrary.
+func init() {
+	addStubFunctions(`
+typedef struct {
+	unsigned int gpuUtilization, memoryUtilization;
+	unsigned long long maxMemoryUsage, time, startTime;
+	unsigned int isRunning;
+	unsigned int reserved[5];
+} accountingStats;
+`, map[string]string{
+		"nvmlDeviceGetAccountingMode": `int nvmlDeviceGetAccountingMode(void *d, int *mode) { *mode = 1; return 0; }`,
+		"nvmlDeviceGetAccountingPids": `int nvmlDeviceGetAccountingPids(void *d, unsigned int *count, unsigned int *pids) {
+			if (*count < 3) { *count = 3; return 7; }
+			for (unsigned int i = 0; i < 3; i++) pids[i] = 1000 + i;
+			*count = 3;
+			return 0;
+		}`,
+		"nvmlDeviceGetAccountingStats": `int nvmlDeviceGetAccountingStats(void *d, unsigned int pid, accountingStats *stats) {
+			if (pid < 1000) return 6;
+			stats->gpuUtilization = 75;
+			stats->memoryUtilization = 20;
+			stats->maxMemoryUsage = 1ULL << 32;
+			stats->time = 0;
+			stats->startTime = 1600000000000000ULL;
+			stats->isRunning = pid == 1002;
+			return 0;
+		}`,
+	})
+}
+
+func TestAccountingStub(t *testing.T) {
+	w := newStubAPI(t)
+
+	enabled, err := w.DeviceGetAccountingMode(Device(0x1000))
+	require.NoError(t, err)
+	require.True(t, enabled)
+
+	pids, err := w.DeviceGetAccountingPids(Device(0x1000))
+	require.NoError(t, err)
+	require.Equal(t, []uint32{1000, 1001, 1002}, pids)
+
+	stats, err := w.DeviceGetAccountingStats(Device(0x1000), 1002)
+	require.NoError(t, err)
+	require.Equal(t, AccountingStats{
+		GPUUtilization:    75,
+		MemoryUtilization: 20,
+		MaxMemoryUsage:    1 << 32,
+		StartTime:         1600000000000000,
+		IsRunning:         true,
+	}, stats)
+
+	_, err = w.DeviceGetAccountingStats(Device(0x1000), 1)
+	require.Equal(t, ErrNotFound, err)
+
+	_, err = w.DeviceGetAccountingBufferSize(Device(0x1000))
+	require.Equal(t, ErrNotSupported, err)
+}
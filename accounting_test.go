@@ -0,0 +1,39 @@
+package nvml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceGetAccountingMode(t *testing.T) {
+	w, device := create(t)
+	defer w.Shutdown()
+
+	_, err := w.DeviceGetAccountingMode(device)
+	require.NoError(t, err)
+}
+
+func TestDeviceGetAccountingPids(t *testing.T) {
+	w, device := create(t)
+	defer w.Shutdown()
+
+	enabled, err := w.DeviceGetAccountingMode(device)
+	require.NoError(t, err)
+
+	if !enabled {
+		t.Skip("accounting mode is disabled")
+	}
+
+	size, err := w.DeviceGetAccountingBufferSize(device)
+	require.NoError(t, err)
+	require.NotZero(t, size)
+
+	pids, err := w.DeviceGetAccountingPids(device)
+	require.NoError(t, err)
+
+	for _, pid := range pids {
+		_, err := w.DeviceGetAccountingStats(device, pid)
+		require.NoError(t, err)
+	}
+}
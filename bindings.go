@@ -2,8 +2,8 @@ package nvml
 
 import (
 	"C"
-	"os"
-	"syscall"
+	"fmt"
+	"sort"
 	"unsafe"
 
 	"github.com/pkg/errors"
@@ -11,8 +11,75 @@ import (
 
 var ErrNotImplemented = errors.New("Not implemented")
 
+// proc represents a single entry point exported by the NVML library.
+type proc interface {
+	Call(args ...uintptr) (r1, r2 uintptr, lastErr error)
+}
+
+// library represents a loaded NVML shared library (nvml.dll on Windows, libnvidia-ml.so.1 on Linux).
+type library interface {
+	FindProc(name string) (proc, error)
+	Release() error
+}
+
+// missingProc stands in for an entry point the loaded library doesn't export.
+// Calling it fails with ErrFunctionNotFound, just like NVML's own NVML_ERROR_FUNCTION_NOT_FOUND.
+type missingProc string
+
+func (p missingProc) Call(args ...uintptr) (uintptr, uintptr, error) {
+	return 13, 0, ErrFunctionNotFound // NVML_ERROR_FUNCTION_NOT_FOUND
+}
+
+// resolver looks up entry points in a library and keeps track of which ones are available.
+type resolver struct {
+	lib      library
+	symbols  map[string]bool
+	versions map[string]int
+	names    map[proc]string
+}
+
+func (r *resolver) find(name string) proc {
+	p, err := r.lib.FindProc(name)
+	if err != nil {
+		r.symbols[name] = false
+		r.names[missingProc(name)] = name
+		return missingProc(name)
+	}
+
+	r.symbols[name] = true
+	r.names[p] = name
+	return p
+}
+
+// findVersion looks up the newest of the given versions of an entry point (e.g. nvmlInit_v2 for version 2),
+// falling back to the unversioned one. Versions must be listed from newest to oldest.
+// The version found is recorded so wrappers can pick the matching struct layout.
+func (r *resolver) findVersion(name string, versions ...int) proc {
+	for _, version := range versions {
+		versioned := fmt.Sprintf("%s_v%d", name, version)
+		if p, err := r.lib.FindProc(versioned); err == nil {
+			r.symbols[name] = true
+			r.symbols[versioned] = true
+			r.versions[name] = version
+			r.names[p] = versioned
+			return p
+		}
+	}
+
+	r.versions[name] = 1
+	return r.find(name)
+}
+
 type API struct {
-	dll *syscall.DLL
+	lib library
+	// Entry points resolved by New, mapped to whether the loaded library exports them
+	symbols map[string]bool
+	// Versions of the entry points resolved with findVersion (1 is the unversioned one)
+	versions map[string]int
+	// Names of the resolved entry points, as exported by the library
+	names map[proc]string
+	// Intercepts calls when recording or replaying, see Record and Replay
+	transport transport
 	// Initialization and cleanup
 	nvmlInit,
 	nvmlShutdown,
@@ -56,6 +123,9 @@ type API struct {
 	nvmlDeviceGetEncoderUtilization,
 	nvmlDeviceGetEnforcedPowerLimit,
 	nvmlDeviceGetFanSpeed,
+	nvmlDeviceGetFieldValues,
+	nvmlDeviceGetFBCSessions,
+	nvmlDeviceGetFBCStats,
 	nvmlDeviceGetGpuOperationMode,
 	nvmlDeviceGetGraphicsRunningProcesses,
 	nvmlDeviceGetHandleByIndex,
@@ -87,8 +157,12 @@ type API struct {
 	nvmlDeviceGetPowerManagementMode,
 	nvmlDeviceGetPowerState,
 	nvmlDeviceGetPowerUsage,
+	nvmlDeviceGetProcessUtilization,
+	nvmlDeviceGetRemappedRows,
 	nvmlDeviceGetRetiredPages,
+	nvmlDeviceGetRetiredPagesV2,
 	nvmlDeviceGetRetiredPagesPendingStatus,
+	nvmlDeviceGetRowRemapperHistogram,
 	nvmlDeviceGetSamples,
 	nvmlDeviceGetSerial,
 	nvmlDeviceGetSupportedClocksThrottleReasons,
@@ -113,6 +187,12 @@ type API struct {
 	nvmlSystemGetTopologyGpuSet,
 	// Device commands
 	nvmlDeviceClearEccErrorCounts,
+	nvmlDeviceClearFieldValues,
+	nvmlDeviceDiscoverGpus,
+	nvmlDeviceModifyDrainState,
+	nvmlDeviceQueryDrainState,
+	nvmlDeviceRemoveGpu,
+	nvmlDeviceResetGpuLockedClocks,
 	nvmlDeviceSetAPIRestriction,
 	nvmlDeviceSetApplicationsClocks,
 	nvmlDeviceSetComputeMode,
@@ -120,16 +200,84 @@ type API struct {
 	nvmlDeviceSetEccMode,
 	nvmlDeviceSetGpuOperationMode,
 	nvmlDeviceSetPersistenceMode,
-	nvmlDeviceSetPowerManagementLimit *syscall.Proc
+	nvmlDeviceSetPowerManagementLimit,
+	// Unit Queries
+	nvmlUnitGetCount,
+	nvmlUnitGetDevices,
+	nvmlUnitGetFanSpeedInfo,
+	nvmlUnitGetHandleByIndex,
+	nvmlUnitGetLedState,
+	nvmlUnitGetPsuInfo,
+	nvmlUnitGetTemperature,
+	nvmlUnitGetUnitInfo,
+	// Unit Commands
+	nvmlUnitSetLedState,
+	// Event handling
+	nvmlDeviceGetSupportedEventTypes,
+	nvmlDeviceRegisterEvents,
+	nvmlEventSetCreate,
+	nvmlEventSetFree,
+	nvmlEventSetWait,
+	// NvLink Methods
+	nvmlDeviceFreezeNvLinkUtilizationCounter,
+	nvmlDeviceGetNvLinkCapability,
+	nvmlDeviceGetNvLinkErrorCounter,
+	nvmlDeviceGetNvLinkRemotePciInfo,
+	nvmlDeviceGetNvLinkState,
+	nvmlDeviceGetNvLinkUtilizationControl,
+	nvmlDeviceGetNvLinkUtilizationCounter,
+	nvmlDeviceGetNvLinkVersion,
+	nvmlDeviceResetNvLinkErrorCounters,
+	nvmlDeviceResetNvLinkUtilizationCounter,
+	nvmlDeviceSetNvLinkUtilizationControl,
+	// Multi Instance GPU Management
+	nvmlComputeInstanceDestroy,
+	nvmlComputeInstanceGetInfo,
+	nvmlDeviceCreateGpuInstance,
+	nvmlDeviceCreateGpuInstanceWithPlacement,
+	nvmlDeviceGetComputeInstanceId,
+	nvmlDeviceGetDeviceHandleFromMigDeviceHandle,
+	nvmlDeviceGetGpuInstanceById,
+	nvmlDeviceGetGpuInstanceId,
+	nvmlDeviceGetGpuInstancePossiblePlacements,
+	nvmlDeviceGetGpuInstanceProfileInfo,
+	nvmlDeviceGetGpuInstanceRemainingCapacity,
+	nvmlDeviceGetGpuInstances,
+	nvmlDeviceGetMaxMigDeviceCount,
+	nvmlDeviceGetMigDeviceHandleByIndex,
+	nvmlDeviceGetMigMode,
+	nvmlDeviceIsMigDeviceHandle,
+	nvmlDeviceSetMigMode,
+	nvmlGpuInstanceCreateComputeInstance,
+	nvmlGpuInstanceDestroy,
+	nvmlGpuInstanceGetComputeInstanceById,
+	nvmlGpuInstanceGetComputeInstanceProfileInfo,
+	nvmlGpuInstanceGetComputeInstanceRemainingCapacity,
+	nvmlGpuInstanceGetComputeInstances,
+	nvmlGpuInstanceGetInfo,
+	// Accounting Statistics
+	nvmlDeviceClearAccountingPids,
+	nvmlDeviceGetAccountingBufferSize,
+	nvmlDeviceGetAccountingMode,
+	nvmlDeviceGetAccountingPids,
+	nvmlDeviceGetAccountingStats,
+	nvmlDeviceSetAccountingMode proc
 }
 
-func (a API) call(p *syscall.Proc, args ...uintptr) error {
-	ret, _, _ := p.Call(args...)
-	if ret != 0 {
-		return returnValueToError(int(ret))
+// call invokes p and converts its nvmlReturn_t into an error.
+// Scalar arguments are passed as uintptr. Memory NVML reads or writes is passed as a pointer (to a value, a struct or
+// an array), a slice or a *C.char, so the call can be recorded (see argument).
+func (a API) call(p proc, args ...interface{}) error {
+	list := make([]argument, len(args))
+	for i, arg := range args {
+		list[i] = newArgument(arg)
 	}
 
-	return nil
+	if a.transport != nil {
+		return a.transport.invoke(a.names[p], p, list)
+	}
+
+	return returnValueToError(int(invoke(p, list)))
 }
 
 // Init initializes NVML, but don't initialize any GPUs yet.
@@ -138,141 +286,253 @@ func (a API) Init() error {
 }
 
 // Shutdown shut downs NVML by releasing all GPU resources previously allocated with Init() and
-// unloads nvml.dll via UnloadLibrary call.
+// unloads the NVML library (UnloadLibrary on Windows, dlclose on Linux).
 func (a API) Shutdown() error {
 	err := a.call(a.nvmlShutdown)
 	a.ReleaseDLL()
 	return err
 }
 
+// ReleaseDLL unloads the NVML library without shutting down NVML.
 func (a API) ReleaseDLL() error {
-	return a.dll.Release()
+	return a.lib.Release()
+}
+
+// Supports reports whether the loaded library exports the given NVML entry point (e.g. "nvmlDeviceGetTotalEnergyConsumption").
+// Versioned entry points can be queried either by their base name or by the exact versioned name (e.g. "nvmlInit_v2").
+// Wrappers of unsupported entry points return ErrFunctionNotFound.
+func (a API) Supports(name string) bool {
+	return a.symbols[name]
+}
+
+// SymbolVersion returns the version of a versioned entry point picked by New (e.g. 3 for nvmlDeviceGetPciInfo_v3).
+// Unversioned entry points report version 1, unknown ones report 0.
+func (a API) SymbolVersion(name string) int {
+	return a.versions[name]
+}
+
+// MissingSymbols returns the names of NVML entry points that couldn't be resolved in the loaded library.
+// This is typically the case for functions introduced in drivers newer than the installed one.
+func (a API) MissingSymbols() []string {
+	list := []string{}
+	for name, ok := range a.symbols {
+		if !ok {
+			list = append(list, name)
+		}
+	}
+
+	sort.Strings(list)
+	return list
 }
 
 // ErrorString returns a string representation of the error.
 func (a API) ErrorString(result uintptr) string {
+	if _, ok := a.nvmlErrorString.(missingProc); ok {
+		if err := returnValueToError(int(result)); err != nil {
+			return err.Error()
+		}
+
+		return "Success"
+	}
+
 	ret, _, _ := a.nvmlErrorString.Call(uintptr(result))
-	buf := (*C.char)(unsafe.Pointer(ret))
+	// ret is a pointer to a static string owned by NVML, reinterpret it without uintptr -> unsafe.Pointer conversion
+	buf := *(**C.char)(unsafe.Pointer(&ret))
 	return C.GoString(buf)
 }
 
-// New creates nvml.dll wrapper
+// New loads the NVML library and resolves its entry points.
+// If path is empty, the library is looked up in the platform's default locations
+// (see loadLibrary in loader_windows.go and loader_linux.go).
 func New(path string) (*API, error) {
-	if path == "" {
-		path = os.ExpandEnv("$ProgramW6432\\NVIDIA Corporation\\NVSMI\\nvml.dll")
-	}
-
-	dll, err := syscall.LoadDLL(path)
+	lib, err := loadLibrary(path)
 	if err != nil {
 		return nil, err
 	}
 
+	return bind(lib), nil
+}
+
+// bind resolves the NVML entry points exported by lib.
+func bind(lib library) *API {
+	r := &resolver{lib: lib, symbols: map[string]bool{}, versions: map[string]int{}, names: map[proc]string{}}
 	bindings := &API{
-		dll:                                          dll,
-		nvmlInit:                                     dll.MustFindProc("nvmlInit"),
-		nvmlShutdown:                                 dll.MustFindProc("nvmlShutdown"),
-		nvmlErrorString:                              dll.MustFindProc("nvmlErrorString"),
-		nvmlSystemGetCudaDriverVersion:               dll.MustFindProc("nvmlSystemGetCudaDriverVersion"),
-		nvmlSystemGetDriverVersion:                   dll.MustFindProc("nvmlSystemGetDriverVersion"),
-		nvmlSystemGetNVMLVersion:                     dll.MustFindProc("nvmlSystemGetNVMLVersion"),
-		nvmlSystemGetProcessName:                     dll.MustFindProc("nvmlSystemGetProcessName"),
-		nvmlDeviceClearCpuAffinity:                   dll.MustFindProc("nvmlDeviceClearCpuAffinity"),
-		nvmlDeviceGetAPIRestriction:                  dll.MustFindProc("nvmlDeviceGetAPIRestriction"),
-		nvmlDeviceGetApplicationsClock:               dll.MustFindProc("nvmlDeviceGetApplicationsClock"),
-		nvmlDeviceGetAutoBoostedClocksEnabled:        dll.MustFindProc("nvmlDeviceGetAutoBoostedClocksEnabled"),
-		nvmlDeviceGetBAR1MemoryInfo:                  dll.MustFindProc("nvmlDeviceGetBAR1MemoryInfo"),
-		nvmlDeviceGetBoardId:                         dll.MustFindProc("nvmlDeviceGetBoardId"),
-		nvmlDeviceGetBoardPartNumber:                 dll.MustFindProc("nvmlDeviceGetBoardPartNumber"),
-		nvmlDeviceGetBrand:                           dll.MustFindProc("nvmlDeviceGetBrand"),
-		nvmlDeviceGetBridgeChipInfo:                  dll.MustFindProc("nvmlDeviceGetBridgeChipInfo"),
-		nvmlDeviceGetClock:                           dll.MustFindProc("nvmlDeviceGetClock"),
-		nvmlDeviceGetClockInfo:                       dll.MustFindProc("nvmlDeviceGetClockInfo"),
-		nvmlDeviceGetComputeMode:                     dll.MustFindProc("nvmlDeviceGetComputeMode"),
-		nvmlDeviceGetComputeRunningProcesses:         dll.MustFindProc("nvmlDeviceGetComputeRunningProcesses"),
-		nvmlDeviceGetCount:                           dll.MustFindProc("nvmlDeviceGetCount"),
-		nvmlDeviceGetCpuAffinity:                     dll.MustFindProc("nvmlDeviceGetCpuAffinity"),
-		nvmlDeviceGetCudaComputeCapability:           dll.MustFindProc("nvmlDeviceGetCudaComputeCapability"),
-		nvmlDeviceGetCurrPcieLinkGeneration:          dll.MustFindProc("nvmlDeviceGetCurrPcieLinkGeneration"),
-		nvmlDeviceGetCurrPcieLinkWidth:               dll.MustFindProc("nvmlDeviceGetCurrPcieLinkWidth"),
-		nvmlDeviceGetCurrentClocksThrottleReasons:    dll.MustFindProc("nvmlDeviceGetCurrentClocksThrottleReasons"),
-		nvmlDeviceGetDecoderUtilization:              dll.MustFindProc("nvmlDeviceGetDecoderUtilization"),
-		nvmlDeviceGetDefaultApplicationsClock:        dll.MustFindProc("nvmlDeviceGetDefaultApplicationsClock"),
-		nvmlDeviceGetDetailedEccErrors:               dll.MustFindProc("nvmlDeviceGetDetailedEccErrors"),
-		nvmlDeviceGetDisplayActive:                   dll.MustFindProc("nvmlDeviceGetDisplayActive"),
-		nvmlDeviceGetDisplayMode:                     dll.MustFindProc("nvmlDeviceGetDisplayMode"),
-		nvmlDeviceGetDriverModel:                     dll.MustFindProc("nvmlDeviceGetDriverModel"),
-		nvmlDeviceGetEccMode:                         dll.MustFindProc("nvmlDeviceGetEccMode"),
-		nvmlDeviceGetEncoderCapacity:                 dll.MustFindProc("nvmlDeviceGetEncoderCapacity"),
-		nvmlDeviceGetEncoderSessions:                 dll.MustFindProc("nvmlDeviceGetEncoderSessions"),
-		nvmlDeviceGetEncoderStats:                    dll.MustFindProc("nvmlDeviceGetEncoderStats"),
-		nvmlDeviceGetEncoderUtilization:              dll.MustFindProc("nvmlDeviceGetEncoderUtilization"),
-		nvmlDeviceGetEnforcedPowerLimit:              dll.MustFindProc("nvmlDeviceGetEnforcedPowerLimit"),
-		nvmlDeviceGetFanSpeed:                        dll.MustFindProc("nvmlDeviceGetFanSpeed"),
-		nvmlDeviceGetGpuOperationMode:                dll.MustFindProc("nvmlDeviceGetGpuOperationMode"),
-		nvmlDeviceGetGraphicsRunningProcesses:        dll.MustFindProc("nvmlDeviceGetGraphicsRunningProcesses"),
-		nvmlDeviceGetHandleByIndex:                   dll.MustFindProc("nvmlDeviceGetHandleByIndex"),
-		nvmlDeviceGetHandleByPciBusId:                dll.MustFindProc("nvmlDeviceGetHandleByPciBusId"),
-		nvmlDeviceGetHandleBySerial:                  dll.MustFindProc("nvmlDeviceGetHandleBySerial"),
-		nvmlDeviceGetHandleByUUID:                    dll.MustFindProc("nvmlDeviceGetHandleByUUID"),
-		nvmlDeviceGetIndex:                           dll.MustFindProc("nvmlDeviceGetIndex"),
-		nvmlDeviceGetInforomConfigurationChecksum:    dll.MustFindProc("nvmlDeviceGetInforomConfigurationChecksum"),
-		nvmlDeviceGetInforomImageVersion:             dll.MustFindProc("nvmlDeviceGetInforomImageVersion"),
-		nvmlDeviceGetInforomVersion:                  dll.MustFindProc("nvmlDeviceGetInforomVersion"),
-		nvmlDeviceGetMaxClockInfo:                    dll.MustFindProc("nvmlDeviceGetMaxClockInfo"),
-		nvmlDeviceGetMaxCustomerBoostClock:           dll.MustFindProc("nvmlDeviceGetMaxCustomerBoostClock"),
-		nvmlDeviceGetMaxPcieLinkGeneration:           dll.MustFindProc("nvmlDeviceGetMaxPcieLinkGeneration"),
-		nvmlDeviceGetMaxPcieLinkWidth:                dll.MustFindProc("nvmlDeviceGetMaxPcieLinkWidth"),
-		nvmlDeviceGetMemoryErrorCounter:              dll.MustFindProc("nvmlDeviceGetMemoryErrorCounter"),
-		nvmlDeviceGetMemoryInfo:                      dll.MustFindProc("nvmlDeviceGetMemoryInfo"),
-		nvmlDeviceGetMinorNumber:                     dll.MustFindProc("nvmlDeviceGetMinorNumber"),
-		nvmlDeviceGetMultiGpuBoard:                   dll.MustFindProc("nvmlDeviceGetMultiGpuBoard"),
-		nvmlDeviceGetName:                            dll.MustFindProc("nvmlDeviceGetName"),
-		nvmlDeviceGetP2PStatus:                       dll.MustFindProc("nvmlDeviceGetP2PStatus"),
-		nvmlDeviceGetPciInfo:                         dll.MustFindProc("nvmlDeviceGetPciInfo"),
-		nvmlDeviceGetPcieReplayCounter:               dll.MustFindProc("nvmlDeviceGetPcieReplayCounter"),
-		nvmlDeviceGetPcieThroughput:                  dll.MustFindProc("nvmlDeviceGetPcieThroughput"),
-		nvmlDeviceGetPerformanceState:                dll.MustFindProc("nvmlDeviceGetPerformanceState"),
-		nvmlDeviceGetPersistenceMode:                 dll.MustFindProc("nvmlDeviceGetPersistenceMode"),
-		nvmlDeviceGetPowerManagementDefaultLimit:     dll.MustFindProc("nvmlDeviceGetPowerManagementDefaultLimit"),
-		nvmlDeviceGetPowerManagementLimit:            dll.MustFindProc("nvmlDeviceGetPowerManagementLimit"),
-		nvmlDeviceGetPowerManagementLimitConstraints: dll.MustFindProc("nvmlDeviceGetPowerManagementLimitConstraints"),
-		nvmlDeviceGetPowerManagementMode:             dll.MustFindProc("nvmlDeviceGetPowerManagementMode"),
-		nvmlDeviceGetPowerState:                      dll.MustFindProc("nvmlDeviceGetPowerState"),
-		nvmlDeviceGetPowerUsage:                      dll.MustFindProc("nvmlDeviceGetPowerUsage"),
-		nvmlDeviceGetRetiredPages:                    dll.MustFindProc("nvmlDeviceGetRetiredPages"),
-		nvmlDeviceGetRetiredPagesPendingStatus:       dll.MustFindProc("nvmlDeviceGetRetiredPagesPendingStatus"),
-		nvmlDeviceGetSamples:                         dll.MustFindProc("nvmlDeviceGetSamples"),
-		nvmlDeviceGetSerial:                          dll.MustFindProc("nvmlDeviceGetSerial"),
-		nvmlDeviceGetSupportedClocksThrottleReasons:  dll.MustFindProc("nvmlDeviceGetSupportedClocksThrottleReasons"),
-		nvmlDeviceGetSupportedGraphicsClocks:         dll.MustFindProc("nvmlDeviceGetSupportedGraphicsClocks"),
-		nvmlDeviceGetSupportedMemoryClocks:           dll.MustFindProc("nvmlDeviceGetSupportedMemoryClocks"),
-		nvmlDeviceGetTemperature:                     dll.MustFindProc("nvmlDeviceGetTemperature"),
-		nvmlDeviceGetTemperatureThreshold:            dll.MustFindProc("nvmlDeviceGetTemperatureThreshold"),
-		nvmlDeviceGetTopologyCommonAncestor:          dll.MustFindProc("nvmlDeviceGetTopologyCommonAncestor"),
-		nvmlDeviceGetTopologyNearestGpus:             dll.MustFindProc("nvmlDeviceGetTopologyNearestGpus"),
-		nvmlDeviceGetTotalEccErrors:                  dll.MustFindProc("nvmlDeviceGetTotalEccErrors"),
-		nvmlDeviceGetTotalEnergyConsumption:          dll.MustFindProc("nvmlDeviceGetTotalEnergyConsumption"),
-		nvmlDeviceGetUUID:                            dll.MustFindProc("nvmlDeviceGetUUID"),
-		nvmlDeviceGetUtilizationRates:                dll.MustFindProc("nvmlDeviceGetUtilizationRates"),
-		nvmlDeviceGetVbiosVersion:                    dll.MustFindProc("nvmlDeviceGetVbiosVersion"),
-		nvmlDeviceGetViolationStatus:                 dll.MustFindProc("nvmlDeviceGetViolationStatus"),
-		nvmlDeviceOnSameBoard:                        dll.MustFindProc("nvmlDeviceOnSameBoard"),
-		nvmlDeviceResetApplicationsClocks:            dll.MustFindProc("nvmlDeviceResetApplicationsClocks"),
-		nvmlDeviceSetAutoBoostedClocksEnabled:        dll.MustFindProc("nvmlDeviceSetAutoBoostedClocksEnabled"),
-		nvmlDeviceSetCpuAffinity:                     dll.MustFindProc("nvmlDeviceSetCpuAffinity"),
-		nvmlDeviceSetDefaultAutoBoostedClocksEnabled: dll.MustFindProc("nvmlDeviceSetDefaultAutoBoostedClocksEnabled"),
-		nvmlDeviceValidateInforom:                    dll.MustFindProc("nvmlDeviceValidateInforom"),
-		nvmlSystemGetTopologyGpuSet:                  dll.MustFindProc("nvmlSystemGetTopologyGpuSet"),
-		nvmlDeviceClearEccErrorCounts:                dll.MustFindProc("nvmlDeviceClearEccErrorCounts"),
-		nvmlDeviceSetAPIRestriction:                  dll.MustFindProc("nvmlDeviceSetAPIRestriction"),
-		nvmlDeviceSetApplicationsClocks:              dll.MustFindProc("nvmlDeviceSetApplicationsClocks"),
-		nvmlDeviceSetComputeMode:                     dll.MustFindProc("nvmlDeviceSetComputeMode"),
-		nvmlDeviceSetDriverModel:                     dll.MustFindProc("nvmlDeviceSetDriverModel"),
-		nvmlDeviceSetEccMode:                         dll.MustFindProc("nvmlDeviceSetEccMode"),
-		nvmlDeviceSetGpuOperationMode:                dll.MustFindProc("nvmlDeviceSetGpuOperationMode"),
-		nvmlDeviceSetPersistenceMode:                 dll.MustFindProc("nvmlDeviceSetPersistenceMode"),
-		nvmlDeviceSetPowerManagementLimit:            dll.MustFindProc("nvmlDeviceSetPowerManagementLimit"),
+		lib:                                                lib,
+		nvmlInit:                                           r.findVersion("nvmlInit", 2),
+		nvmlShutdown:                                       r.find("nvmlShutdown"),
+		nvmlErrorString:                                    r.find("nvmlErrorString"),
+		nvmlSystemGetCudaDriverVersion:                     r.find("nvmlSystemGetCudaDriverVersion"),
+		nvmlSystemGetDriverVersion:                         r.find("nvmlSystemGetDriverVersion"),
+		nvmlSystemGetNVMLVersion:                           r.find("nvmlSystemGetNVMLVersion"),
+		nvmlSystemGetProcessName:                           r.find("nvmlSystemGetProcessName"),
+		nvmlDeviceClearCpuAffinity:                         r.find("nvmlDeviceClearCpuAffinity"),
+		nvmlDeviceGetAPIRestriction:                        r.find("nvmlDeviceGetAPIRestriction"),
+		nvmlDeviceGetApplicationsClock:                     r.find("nvmlDeviceGetApplicationsClock"),
+		nvmlDeviceGetAutoBoostedClocksEnabled:              r.find("nvmlDeviceGetAutoBoostedClocksEnabled"),
+		nvmlDeviceGetBAR1MemoryInfo:                        r.find("nvmlDeviceGetBAR1MemoryInfo"),
+		nvmlDeviceGetBoardId:                               r.find("nvmlDeviceGetBoardId"),
+		nvmlDeviceGetBoardPartNumber:                       r.find("nvmlDeviceGetBoardPartNumber"),
+		nvmlDeviceGetBrand:                                 r.find("nvmlDeviceGetBrand"),
+		nvmlDeviceGetBridgeChipInfo:                        r.find("nvmlDeviceGetBridgeChipInfo"),
+		nvmlDeviceGetClock:                                 r.find("nvmlDeviceGetClock"),
+		nvmlDeviceGetClockInfo:                             r.find("nvmlDeviceGetClockInfo"),
+		nvmlDeviceGetComputeMode:                           r.find("nvmlDeviceGetComputeMode"),
+		nvmlDeviceGetComputeRunningProcesses:               r.findVersion("nvmlDeviceGetComputeRunningProcesses", 3, 2),
+		nvmlDeviceGetCount:                                 r.findVersion("nvmlDeviceGetCount", 2),
+		nvmlDeviceGetCpuAffinity:                           r.find("nvmlDeviceGetCpuAffinity"),
+		nvmlDeviceGetCudaComputeCapability:                 r.find("nvmlDeviceGetCudaComputeCapability"),
+		nvmlDeviceGetCurrPcieLinkGeneration:                r.find("nvmlDeviceGetCurrPcieLinkGeneration"),
+		nvmlDeviceGetCurrPcieLinkWidth:                     r.find("nvmlDeviceGetCurrPcieLinkWidth"),
+		nvmlDeviceGetCurrentClocksThrottleReasons:          r.find("nvmlDeviceGetCurrentClocksThrottleReasons"),
+		nvmlDeviceGetDecoderUtilization:                    r.find("nvmlDeviceGetDecoderUtilization"),
+		nvmlDeviceGetDefaultApplicationsClock:              r.find("nvmlDeviceGetDefaultApplicationsClock"),
+		nvmlDeviceGetDetailedEccErrors:                     r.find("nvmlDeviceGetDetailedEccErrors"),
+		nvmlDeviceGetDisplayActive:                         r.find("nvmlDeviceGetDisplayActive"),
+		nvmlDeviceGetDisplayMode:                           r.find("nvmlDeviceGetDisplayMode"),
+		nvmlDeviceGetDriverModel:                           r.find("nvmlDeviceGetDriverModel"),
+		nvmlDeviceGetEccMode:                               r.find("nvmlDeviceGetEccMode"),
+		nvmlDeviceGetEncoderCapacity:                       r.find("nvmlDeviceGetEncoderCapacity"),
+		nvmlDeviceGetEncoderSessions:                       r.find("nvmlDeviceGetEncoderSessions"),
+		nvmlDeviceGetEncoderStats:                          r.find("nvmlDeviceGetEncoderStats"),
+		nvmlDeviceGetEncoderUtilization:                    r.find("nvmlDeviceGetEncoderUtilization"),
+		nvmlDeviceGetEnforcedPowerLimit:                    r.find("nvmlDeviceGetEnforcedPowerLimit"),
+		nvmlDeviceGetFanSpeed:                              r.find("nvmlDeviceGetFanSpeed"),
+		nvmlDeviceGetFieldValues:                           r.find("nvmlDeviceGetFieldValues"),
+		nvmlDeviceGetFBCSessions:                           r.find("nvmlDeviceGetFBCSessions"),
+		nvmlDeviceGetFBCStats:                              r.find("nvmlDeviceGetFBCStats"),
+		nvmlDeviceGetGpuOperationMode:                      r.find("nvmlDeviceGetGpuOperationMode"),
+		nvmlDeviceGetGraphicsRunningProcesses:              r.findVersion("nvmlDeviceGetGraphicsRunningProcesses", 3, 2),
+		nvmlDeviceGetHandleByIndex:                         r.findVersion("nvmlDeviceGetHandleByIndex", 2),
+		nvmlDeviceGetHandleByPciBusId:                      r.findVersion("nvmlDeviceGetHandleByPciBusId", 2),
+		nvmlDeviceGetHandleBySerial:                        r.find("nvmlDeviceGetHandleBySerial"),
+		nvmlDeviceGetHandleByUUID:                          r.find("nvmlDeviceGetHandleByUUID"),
+		nvmlDeviceGetIndex:                                 r.find("nvmlDeviceGetIndex"),
+		nvmlDeviceGetInforomConfigurationChecksum:          r.find("nvmlDeviceGetInforomConfigurationChecksum"),
+		nvmlDeviceGetInforomImageVersion:                   r.find("nvmlDeviceGetInforomImageVersion"),
+		nvmlDeviceGetInforomVersion:                        r.find("nvmlDeviceGetInforomVersion"),
+		nvmlDeviceGetMaxClockInfo:                          r.find("nvmlDeviceGetMaxClockInfo"),
+		nvmlDeviceGetMaxCustomerBoostClock:                 r.find("nvmlDeviceGetMaxCustomerBoostClock"),
+		nvmlDeviceGetMaxPcieLinkGeneration:                 r.find("nvmlDeviceGetMaxPcieLinkGeneration"),
+		nvmlDeviceGetMaxPcieLinkWidth:                      r.find("nvmlDeviceGetMaxPcieLinkWidth"),
+		nvmlDeviceGetMemoryErrorCounter:                    r.find("nvmlDeviceGetMemoryErrorCounter"),
+		nvmlDeviceGetMemoryInfo:                            r.find("nvmlDeviceGetMemoryInfo"),
+		nvmlDeviceGetMinorNumber:                           r.find("nvmlDeviceGetMinorNumber"),
+		nvmlDeviceGetMultiGpuBoard:                         r.find("nvmlDeviceGetMultiGpuBoard"),
+		nvmlDeviceGetName:                                  r.find("nvmlDeviceGetName"),
+		nvmlDeviceGetP2PStatus:                             r.find("nvmlDeviceGetP2PStatus"),
+		nvmlDeviceGetPciInfo:                               r.findVersion("nvmlDeviceGetPciInfo", 3, 2),
+		nvmlDeviceGetPcieReplayCounter:                     r.find("nvmlDeviceGetPcieReplayCounter"),
+		nvmlDeviceGetPcieThroughput:                        r.find("nvmlDeviceGetPcieThroughput"),
+		nvmlDeviceGetPerformanceState:                      r.find("nvmlDeviceGetPerformanceState"),
+		nvmlDeviceGetPersistenceMode:                       r.find("nvmlDeviceGetPersistenceMode"),
+		nvmlDeviceGetPowerManagementDefaultLimit:           r.find("nvmlDeviceGetPowerManagementDefaultLimit"),
+		nvmlDeviceGetPowerManagementLimit:                  r.find("nvmlDeviceGetPowerManagementLimit"),
+		nvmlDeviceGetPowerManagementLimitConstraints:       r.find("nvmlDeviceGetPowerManagementLimitConstraints"),
+		nvmlDeviceGetPowerManagementMode:                   r.find("nvmlDeviceGetPowerManagementMode"),
+		nvmlDeviceGetPowerState:                            r.find("nvmlDeviceGetPowerState"),
+		nvmlDeviceGetPowerUsage:                            r.find("nvmlDeviceGetPowerUsage"),
+		nvmlDeviceGetProcessUtilization:                    r.find("nvmlDeviceGetProcessUtilization"),
+		nvmlDeviceGetRemappedRows:                          r.find("nvmlDeviceGetRemappedRows"),
+		nvmlDeviceGetRetiredPages:                          r.find("nvmlDeviceGetRetiredPages"),
+		nvmlDeviceGetRetiredPagesV2:                        r.findVersion("nvmlDeviceGetRetiredPages", 2),
+		nvmlDeviceGetRetiredPagesPendingStatus:             r.find("nvmlDeviceGetRetiredPagesPendingStatus"),
+		nvmlDeviceGetRowRemapperHistogram:                  r.find("nvmlDeviceGetRowRemapperHistogram"),
+		nvmlDeviceGetSamples:                               r.find("nvmlDeviceGetSamples"),
+		nvmlDeviceGetSerial:                                r.find("nvmlDeviceGetSerial"),
+		nvmlDeviceGetSupportedClocksThrottleReasons:        r.find("nvmlDeviceGetSupportedClocksThrottleReasons"),
+		nvmlDeviceGetSupportedGraphicsClocks:               r.find("nvmlDeviceGetSupportedGraphicsClocks"),
+		nvmlDeviceGetSupportedMemoryClocks:                 r.find("nvmlDeviceGetSupportedMemoryClocks"),
+		nvmlDeviceGetTemperature:                           r.find("nvmlDeviceGetTemperature"),
+		nvmlDeviceGetTemperatureThreshold:                  r.find("nvmlDeviceGetTemperatureThreshold"),
+		nvmlDeviceGetTopologyCommonAncestor:                r.find("nvmlDeviceGetTopologyCommonAncestor"),
+		nvmlDeviceGetTopologyNearestGpus:                   r.find("nvmlDeviceGetTopologyNearestGpus"),
+		nvmlDeviceGetTotalEccErrors:                        r.find("nvmlDeviceGetTotalEccErrors"),
+		nvmlDeviceGetTotalEnergyConsumption:                r.find("nvmlDeviceGetTotalEnergyConsumption"),
+		nvmlDeviceGetUUID:                                  r.find("nvmlDeviceGetUUID"),
+		nvmlDeviceGetUtilizationRates:                      r.find("nvmlDeviceGetUtilizationRates"),
+		nvmlDeviceGetVbiosVersion:                          r.find("nvmlDeviceGetVbiosVersion"),
+		nvmlDeviceGetViolationStatus:                       r.find("nvmlDeviceGetViolationStatus"),
+		nvmlDeviceOnSameBoard:                              r.find("nvmlDeviceOnSameBoard"),
+		nvmlDeviceResetApplicationsClocks:                  r.find("nvmlDeviceResetApplicationsClocks"),
+		nvmlDeviceSetAutoBoostedClocksEnabled:              r.find("nvmlDeviceSetAutoBoostedClocksEnabled"),
+		nvmlDeviceSetCpuAffinity:                           r.find("nvmlDeviceSetCpuAffinity"),
+		nvmlDeviceSetDefaultAutoBoostedClocksEnabled:       r.find("nvmlDeviceSetDefaultAutoBoostedClocksEnabled"),
+		nvmlDeviceValidateInforom:                          r.find("nvmlDeviceValidateInforom"),
+		nvmlSystemGetTopologyGpuSet:                        r.find("nvmlSystemGetTopologyGpuSet"),
+		nvmlDeviceClearEccErrorCounts:                      r.find("nvmlDeviceClearEccErrorCounts"),
+		nvmlDeviceClearFieldValues:                         r.find("nvmlDeviceClearFieldValues"),
+		nvmlDeviceDiscoverGpus:                             r.find("nvmlDeviceDiscoverGpus"),
+		nvmlDeviceModifyDrainState:                         r.find("nvmlDeviceModifyDrainState"),
+		nvmlDeviceQueryDrainState:                          r.find("nvmlDeviceQueryDrainState"),
+		nvmlDeviceRemoveGpu:                                r.findVersion("nvmlDeviceRemoveGpu", 2),
+		nvmlDeviceResetGpuLockedClocks:                     r.find("nvmlDeviceResetGpuLockedClocks"),
+		nvmlDeviceSetAPIRestriction:                        r.find("nvmlDeviceSetAPIRestriction"),
+		nvmlDeviceSetApplicationsClocks:                    r.find("nvmlDeviceSetApplicationsClocks"),
+		nvmlDeviceSetComputeMode:                           r.find("nvmlDeviceSetComputeMode"),
+		nvmlDeviceSetDriverModel:                           r.find("nvmlDeviceSetDriverModel"),
+		nvmlDeviceSetEccMode:                               r.find("nvmlDeviceSetEccMode"),
+		nvmlDeviceSetGpuOperationMode:                      r.find("nvmlDeviceSetGpuOperationMode"),
+		nvmlDeviceSetPersistenceMode:                       r.find("nvmlDeviceSetPersistenceMode"),
+		nvmlDeviceSetPowerManagementLimit:                  r.find("nvmlDeviceSetPowerManagementLimit"),
+		nvmlUnitGetCount:                                   r.find("nvmlUnitGetCount"),
+		nvmlUnitGetDevices:                                 r.find("nvmlUnitGetDevices"),
+		nvmlUnitGetFanSpeedInfo:                            r.find("nvmlUnitGetFanSpeedInfo"),
+		nvmlUnitGetHandleByIndex:                           r.find("nvmlUnitGetHandleByIndex"),
+		nvmlUnitGetLedState:                                r.find("nvmlUnitGetLedState"),
+		nvmlUnitGetPsuInfo:                                 r.find("nvmlUnitGetPsuInfo"),
+		nvmlUnitGetTemperature:                             r.find("nvmlUnitGetTemperature"),
+		nvmlUnitGetUnitInfo:                                r.find("nvmlUnitGetUnitInfo"),
+		nvmlUnitSetLedState:                                r.find("nvmlUnitSetLedState"),
+		nvmlDeviceGetSupportedEventTypes:                   r.find("nvmlDeviceGetSupportedEventTypes"),
+		nvmlDeviceRegisterEvents:                           r.find("nvmlDeviceRegisterEvents"),
+		nvmlEventSetCreate:                                 r.find("nvmlEventSetCreate"),
+		nvmlEventSetFree:                                   r.find("nvmlEventSetFree"),
+		nvmlEventSetWait:                                   r.findVersion("nvmlEventSetWait", 2),
+		nvmlDeviceFreezeNvLinkUtilizationCounter:           r.find("nvmlDeviceFreezeNvLinkUtilizationCounter"),
+		nvmlDeviceGetNvLinkCapability:                      r.find("nvmlDeviceGetNvLinkCapability"),
+		nvmlDeviceGetNvLinkErrorCounter:                    r.find("nvmlDeviceGetNvLinkErrorCounter"),
+		nvmlDeviceGetNvLinkRemotePciInfo:                   r.findVersion("nvmlDeviceGetNvLinkRemotePciInfo", 2),
+		nvmlDeviceGetNvLinkState:                           r.find("nvmlDeviceGetNvLinkState"),
+		nvmlDeviceGetNvLinkUtilizationControl:              r.find("nvmlDeviceGetNvLinkUtilizationControl"),
+		nvmlDeviceGetNvLinkUtilizationCounter:              r.find("nvmlDeviceGetNvLinkUtilizationCounter"),
+		nvmlDeviceGetNvLinkVersion:                         r.find("nvmlDeviceGetNvLinkVersion"),
+		nvmlDeviceResetNvLinkErrorCounters:                 r.find("nvmlDeviceResetNvLinkErrorCounters"),
+		nvmlDeviceResetNvLinkUtilizationCounter:            r.find("nvmlDeviceResetNvLinkUtilizationCounter"),
+		nvmlDeviceSetNvLinkUtilizationControl:              r.find("nvmlDeviceSetNvLinkUtilizationControl"),
+		nvmlComputeInstanceDestroy:                         r.find("nvmlComputeInstanceDestroy"),
+		nvmlComputeInstanceGetInfo:                         r.findVersion("nvmlComputeInstanceGetInfo", 2),
+		nvmlDeviceCreateGpuInstance:                        r.find("nvmlDeviceCreateGpuInstance"),
+		nvmlDeviceCreateGpuInstanceWithPlacement:           r.find("nvmlDeviceCreateGpuInstanceWithPlacement"),
+		nvmlDeviceGetComputeInstanceId:                     r.find("nvmlDeviceGetComputeInstanceId"),
+		nvmlDeviceGetDeviceHandleFromMigDeviceHandle:       r.find("nvmlDeviceGetDeviceHandleFromMigDeviceHandle"),
+		nvmlDeviceGetGpuInstanceById:                       r.find("nvmlDeviceGetGpuInstanceById"),
+		nvmlDeviceGetGpuInstanceId:                         r.find("nvmlDeviceGetGpuInstanceId"),
+		nvmlDeviceGetGpuInstancePossiblePlacements:         r.findVersion("nvmlDeviceGetGpuInstancePossiblePlacements", 2),
+		nvmlDeviceGetGpuInstanceProfileInfo:                r.find("nvmlDeviceGetGpuInstanceProfileInfo"),
+		nvmlDeviceGetGpuInstanceRemainingCapacity:          r.find("nvmlDeviceGetGpuInstanceRemainingCapacity"),
+		nvmlDeviceGetGpuInstances:                          r.find("nvmlDeviceGetGpuInstances"),
+		nvmlDeviceGetMaxMigDeviceCount:                     r.find("nvmlDeviceGetMaxMigDeviceCount"),
+		nvmlDeviceGetMigDeviceHandleByIndex:                r.find("nvmlDeviceGetMigDeviceHandleByIndex"),
+		nvmlDeviceGetMigMode:                               r.find("nvmlDeviceGetMigMode"),
+		nvmlDeviceIsMigDeviceHandle:                        r.find("nvmlDeviceIsMigDeviceHandle"),
+		nvmlDeviceSetMigMode:                               r.find("nvmlDeviceSetMigMode"),
+		nvmlGpuInstanceCreateComputeInstance:               r.find("nvmlGpuInstanceCreateComputeInstance"),
+		nvmlGpuInstanceDestroy:                             r.find("nvmlGpuInstanceDestroy"),
+		nvmlGpuInstanceGetComputeInstanceById:              r.find("nvmlGpuInstanceGetComputeInstanceById"),
+		nvmlGpuInstanceGetComputeInstanceProfileInfo:       r.find("nvmlGpuInstanceGetComputeInstanceProfileInfo"),
+		nvmlGpuInstanceGetComputeInstanceRemainingCapacity: r.find("nvmlGpuInstanceGetComputeInstanceRemainingCapacity"),
+		nvmlGpuInstanceGetComputeInstances:                 r.find("nvmlGpuInstanceGetComputeInstances"),
+		nvmlGpuInstanceGetInfo:                             r.find("nvmlGpuInstanceGetInfo"),
+		nvmlDeviceClearAccountingPids:                      r.find("nvmlDeviceClearAccountingPids"),
+		nvmlDeviceGetAccountingBufferSize:                  r.find("nvmlDeviceGetAccountingBufferSize"),
+		nvmlDeviceGetAccountingMode:                        r.find("nvmlDeviceGetAccountingMode"),
+		nvmlDeviceGetAccountingPids:                        r.find("nvmlDeviceGetAccountingPids"),
+		nvmlDeviceGetAccountingStats:                       r.find("nvmlDeviceGetAccountingStats"),
+		nvmlDeviceSetAccountingMode:                        r.find("nvmlDeviceSetAccountingMode"),
 	}
 
-	return bindings, nil
+	bindings.symbols = r.symbols
+	bindings.versions = r.versions
+	bindings.names = r.names
+	return bindings
 }
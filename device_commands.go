@@ -10,6 +10,25 @@ func (a API) DeviceClearECCErrorCounts(device Device, counterType ECCCounterType
 	return a.call(a.nvmlDeviceClearEccErrorCounts, uintptr(device), uintptr(counterType))
 }
 
+// DeviceClearFieldValues clears values for a list of fields for a device.
+// This API allows multiple fields to be cleared at once.
+func (a API) DeviceClearFieldValues(device Device, requests []FieldRequest) error {
+	if len(requests) == 0 {
+		return nil
+	}
+
+	raw := newFieldValues(requests)
+	return a.call(a.nvmlDeviceClearFieldValues, uintptr(device), uintptr(len(raw)), raw)
+}
+
+// DeviceResetGPULockedClocks resets the GPU clock to the default value.
+// This is the GPU clock that will be used after system reboot or driver reload.
+// Default values are idle clocks, but the current values can be changed by DeviceSetApplicationsClocks.
+// For Volta or newer fully supported devices.
+func (a API) DeviceResetGPULockedClocks(device Device) error {
+	return a.call(a.nvmlDeviceResetGpuLockedClocks, uintptr(device))
+}
+
 // DeviceSetAPIRestriction changes the root/admin restructions on certain APIs.
 // See nvmlRestrictedAPI_t for the list of supported APIs.
 // This method can be used by a root/admin user to give non-root/admin access to certain otherwise-restricted APIs.
@@ -0,0 +1,81 @@
+// +build linux,cgo
+
+package nvml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The device command entry points of the stub library, see newStubLibrary.
+func init() {
+	addStubFunctions(`
+static int drainState;
+`, map[string]string{
+		"nvmlDeviceModifyDrainState": `int nvmlDeviceModifyDrainState(pciInfo *pci, int state) {
+			if (pci->bus != 1 || strcmp(pci->busIdLegacy, "0000:01:00.0")) return 6;
+			drainState = state;
+			return 0;
+		}`,
+		"nvmlDeviceQueryDrainState": `int nvmlDeviceQueryDrainState(pciInfo *pci, int *state) {
+			if (pci->bus != 1) return 6;
+			*state = drainState;
+			return 0;
+		}`,
+		"nvmlDeviceRemoveGpu": `int nvmlDeviceRemoveGpu(pciInfo *pci) {
+			if (pci->bus != 1) return 6;
+			return drainState ? 0 : 19;
+		}`,
+		"nvmlDeviceRemoveGpu_v2": `int nvmlDeviceRemoveGpu_v2(pciInfo *pci, int gpuState, int linkState) {
+			if (pci->bus != 1) return 6;
+			if (gpuState != 0 || linkState != 0) return 2;
+			return drainState ? 0 : 19;
+		}`,
+		"nvmlDeviceDiscoverGpus": `int nvmlDeviceDiscoverGpus(pciInfo *pci) {
+			drainState = 0;
+			return pci->bus <= 1 ? 0 : 6;
+		}`,
+		"nvmlDeviceResetGpuLockedClocks": "int nvmlDeviceResetGpuLockedClocks(void *d) { return d == (void *)0x1000 ? 0 : 2; }",
+	})
+}
+
+func TestDeviceResetGPULockedClocksStub(t *testing.T) {
+	w := newStubAPI(t)
+
+	require.NoError(t, w.DeviceResetGPULockedClocks(Device(0x1000)))
+	require.Equal(t, ErrInvalidArgument, w.DeviceResetGPULockedClocks(Device(0x2000)))
+}
+
+func TestDrainRemoveDiscoverStub(t *testing.T) {
+	for _, exports := range [][]string{nil, {"nvmlDeviceRemoveGpu_v2"}} {
+		w := newStubAPI(t, exports...)
+
+		pci, err := w.DeviceGetPCIInfo(Device(0x1000))
+		require.NoError(t, err)
+		require.Equal(t, uint32(1), pci.Bus)
+
+		err = w.DeviceRemoveGPU(pci)
+		require.Equal(t, ErrInUse, err)
+
+		err = w.DeviceModifyDrainState(pci, true)
+		require.NoError(t, err)
+
+		drained, err := w.DeviceQueryDrainState(pci)
+		require.NoError(t, err)
+		require.True(t, drained)
+
+		err = w.DeviceRemoveGPU(pci)
+		require.NoError(t, err)
+
+		err = w.DeviceDiscoverGPUs(&PCIInfo{})
+		require.NoError(t, err)
+
+		drained, err = w.DeviceQueryDrainState(pci)
+		require.NoError(t, err)
+		require.False(t, drained)
+
+		err = w.DeviceModifyDrainState(&PCIInfo{Bus: 2}, true)
+		require.Equal(t, ErrNotFound, err)
+	}
+}
@@ -14,6 +14,14 @@ func TestDeviceClearECCErrorCounts(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestDeviceResetGPULockedClocks(t *testing.T) {
+	w, device := create(t)
+	defer w.Shutdown()
+
+	err := w.DeviceResetGPULockedClocks(device)
+	require.NoError(t, err)
+}
+
 func TestDeviceSetAPIRestriction(t *testing.T) {
 	w, device := create(t)
 	defer w.Shutdown()
@@ -2,14 +2,16 @@
 
 package nvml
 
-import "unsafe"
+// DeviceGetCPUAffinity retrieves an array of unsigned longs (sized to cpuSetSize) of bitmasks with the ideal CPU
+// affinity for the device. Unsigned longs are 64-bit wide on Linux, so each word covers 64 CPUs. For example,
+// if processors 0, 1, 64, and 65 are ideal for the device and cpuSetSize == 2, result[0] = 0x3, result[1] = 0x3
+func (a API) DeviceGetCPUAffinity(device Device, cpuSetSize uint32) ([]uint64, error) {
+	cpuSet := make([]uint64, cpuSetSize)
+	if err := a.call(a.nvmlDeviceGetCpuAffinity, uintptr(device), uintptr(cpuSetSize), cpuSet); err != nil {
+		return nil, err
+	}
 
-// DeviceGetCPUAffinity retrieves an array of unsigned ints (sized to cpuSetSize) of bitmasks with the ideal CPU
-// affinity for the device. For example, if processors 0, 1, 32, and 33 are ideal for the device and
-// cpuSetSize == 2, result[0] = 0x3, result[1] = 0x3
-func (a API) DeviceGetCPUAffinity(device Device, cpuSetSize uint32) (cpuSet uint32, err error) {
-	err = a.call(a.nvmlDeviceGetCpuAffinity, uintptr(device), uintptr(cpuSetSize), uintptr(unsafe.Pointer(&cpuSet)))
-	return
+	return cpuSet, nil
 }
 
 // DeviceSetCpuAffinity sets the ideal affinity for the calling thread and device using the guidelines given in
@@ -31,7 +33,7 @@ func (a API) DeviceClearCpuAffinity(device Device) (err error) {
 // By default this feature is disabled.
 func (a API) DeviceGetPersistenceMode(device Device) (enabled bool, err error) {
 	var state int32
-	err = a.call(a.nvmlDeviceGetPersistenceMode, uintptr(device), uintptr(unsafe.Pointer(&state)))
+	err = a.call(a.nvmlDeviceGetPersistenceMode, uintptr(device), &state)
 	if err != nil {
 		return
 	}
@@ -58,3 +60,60 @@ func (a API) DeviceSetPersistenceMode(device Device, mode bool) error {
 
 	return a.call(a.nvmlDeviceSetPersistenceMode, uintptr(device), uintptr(modeInt))
 }
+
+// DeviceModifyDrainState modifies the drain state of a GPU. This method forces a GPU to no longer accept new incoming
+// requests. Any new NVML process will no longer see this GPU. Persistence mode for this GPU must be turned off before
+// this call is made. Must be called as administrator.
+// For Linux only. For Pascal or newer fully supported devices. Some Kepler devices supported.
+func (a API) DeviceModifyDrainState(pci *PCIInfo, drain bool) error {
+	var state int32 = 0
+	if drain {
+		state = 1
+	}
+
+	return a.call(a.nvmlDeviceModifyDrainState, newPciInfo(pci), uintptr(state))
+}
+
+// DeviceQueryDrainState queries the drain state of a GPU. This method is used to check if a GPU is in a currently
+// draining state.
+// For Linux only. For Pascal or newer fully supported devices. Some Kepler devices supported.
+func (a API) DeviceQueryDrainState(pci *PCIInfo) (drained bool, err error) {
+	var state int32
+	err = a.call(a.nvmlDeviceQueryDrainState, newPciInfo(pci), &state)
+	if err != nil {
+		return
+	}
+
+	drained = state > 0
+	return
+}
+
+// DeviceRemoveGPU removes the specified GPU from the view of both NVML and the NVIDIA kernel driver as long as
+// no other processes are attached. If other processes are attached, this call will return ErrInUse and the GPU
+// will be returned to its original "draining" state. Note: the only situation where a process can still be attached
+// after DeviceModifyDrainState is called to initiate the draining state is if that process was using, and is still
+// using, a GPU before the call was made. Also note, persistence mode counts as an attachment to the GPU thus it must
+// be disabled prior to this call. For long-running NVML processes please note that this will change the enumeration
+// of current GPUs. The GPU is kept powered and linked, it can be brought back with DeviceDiscoverGPUs.
+// Must be called as administrator.
+// For Linux only. For Pascal or newer fully supported devices. Some Kepler devices supported.
+func (a API) DeviceRemoveGPU(pci *PCIInfo) error {
+	if a.versions["nvmlDeviceRemoveGpu"] >= 2 {
+		// NVML_DETACH_GPU_KEEP and NVML_PCIE_LINK_KEEP, the behavior of the first version
+		return a.call(a.nvmlDeviceRemoveGpu, newPciInfo(pci), uintptr(0), uintptr(0))
+	}
+
+	return a.call(a.nvmlDeviceRemoveGpu, newPciInfo(pci))
+}
+
+// DeviceDiscoverGPUs requests the OS and the NVIDIA kernel driver to rediscover a portion of the PCI subsystem
+// looking for GPUs that were previously removed. The portion of the PCI tree can be narrowed by specifying a domain,
+// bus, and device. If all are zeroes then the entire PCI tree will be searched. Please note that for long-running
+// NVML processes the enumeration will change based on how many GPUs are discovered and where they are inserted
+// in bus order. In addition, all newly discovered GPUs will be initialized and their ECC scrubbed which may take
+// several seconds per GPU. Also, all device handles are no longer guaranteed to be valid post discovery.
+// Must be run as administrator.
+// For Linux only. For Pascal or newer fully supported devices. Some Kepler devices supported.
+func (a API) DeviceDiscoverGPUs(pci *PCIInfo) error {
+	return a.call(a.nvmlDeviceDiscoverGpus, newPciInfo(pci))
+}
@@ -47,3 +47,15 @@ func TestDeviceSetCpuAffinity(t *testing.T) {
 	err := w.DeviceSetCpuAffinity(device)
 	require.NoError(t, err)
 }
+
+func TestDeviceQueryDrainState(t *testing.T) {
+	w, device := create(t)
+	defer w.Shutdown()
+
+	pci, err := w.DeviceGetPCIInfo(device)
+	require.NoError(t, err)
+
+	drained, err := w.DeviceQueryDrainState(pci)
+	require.NoError(t, err)
+	require.False(t, drained)
+}
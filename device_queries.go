@@ -1,8 +1,11 @@
 package nvml
 
-// #define NVML_DEVICE_PCI_BUS_ID_BUFFER_SIZE 16
-// typedef struct nvmlPciInfo_st {
-//     char busId[NVML_DEVICE_PCI_BUS_ID_BUFFER_SIZE]; //!< The tuple domain:bus:device.function PCI identifier
+// #define NVML_DEVICE_PCI_BUS_ID_BUFFER_SIZE 32
+// #define NVML_DEVICE_PCI_BUS_ID_BUFFER_V2_SIZE 16
+//
+// // Layout filled by nvmlDeviceGetPciInfo and nvmlDeviceGetPciInfo_v2
+// typedef struct nvmlPciInfoLegacy_st {
+//     char busId[NVML_DEVICE_PCI_BUS_ID_BUFFER_V2_SIZE]; //!< The tuple domain:bus:device.function PCI identifier
 //     unsigned int domain; //!< The PCI domain on which the device's bus resides, 0 to 0xffff
 //     unsigned int bus; //!< The bus on which the device resides, 0 to 0xff
 //     unsigned int device; //!< The device's id on the bus, 0 to 31
@@ -16,11 +19,24 @@ package nvml
 //     unsigned int reserved1;
 //     unsigned int reserved2;
 //     unsigned int reserved3;
+// } nvmlPciInfoLegacy_t;
+//
+// // Layout filled by nvmlDeviceGetPciInfo_v3
+// typedef struct nvmlPciInfo_st {
+//     char busIdLegacy[NVML_DEVICE_PCI_BUS_ID_BUFFER_V2_SIZE]; //!< The legacy tuple domain:bus:device.function PCI identifier
+//     unsigned int domain; //!< The PCI domain on which the device's bus resides, 0 to 0xffffffff
+//     unsigned int bus; //!< The bus on which the device resides, 0 to 0xff
+//     unsigned int device; //!< The device's id on the bus, 0 to 31
+//     unsigned int pciDeviceId; //!< The combined 16-bit device id and 16-bit vendor id
+//     unsigned int pciSubSystemId; //!< The 32-bit Sub System Device ID
+//     char busId[NVML_DEVICE_PCI_BUS_ID_BUFFER_SIZE]; //!< The tuple domain:bus:device.function PCI identifier
 // } nvmlPciInfo_t;
 // #include <stdlib.h>
 import "C"
 
 import (
+	"math"
+	"sort"
 	"unsafe"
 )
 
@@ -30,7 +46,7 @@ import (
 // See nvmlDeviceSetAPIRestriction to change current permissions.
 func (a API) DeviceGetAPIRestriction(device Device, apiType RestrictedAPI) (bool, error) {
 	var state int32
-	if err := a.call(a.nvmlDeviceGetAPIRestriction, uintptr(device), uintptr(apiType), uintptr(unsafe.Pointer(&state))); err != nil {
+	if err := a.call(a.nvmlDeviceGetAPIRestriction, uintptr(device), uintptr(apiType), &state); err != nil {
 		return false, err
 	}
 
@@ -44,7 +60,7 @@ func (a API) DeviceGetAPIRestriction(device Device, apiType RestrictedAPI) (bool
 // DeviceGetApplicationsClock retrieves the current setting of a clock that applications will use unless an overspec
 // situation occurs. Can be changed using DeviceSetApplicationsClocks.
 func (a API) DeviceGetApplicationsClock(device Device, clockType ClockType) (clockMHz uint32, err error) {
-	err = a.call(a.nvmlDeviceGetApplicationsClock, uintptr(device), uintptr(clockType), uintptr(unsafe.Pointer(&clockMHz)))
+	err = a.call(a.nvmlDeviceGetApplicationsClock, uintptr(device), uintptr(clockType), &clockMHz)
 	return
 }
 
@@ -56,7 +72,7 @@ func (a API) DeviceGetAutoBoostedClocksEnabled(device Device) (isEnabled, defaul
 	var isEnabledInt int32
 	var defaultIsEnabledInt int32
 
-	err = a.call(a.nvmlDeviceGetAutoBoostedClocksEnabled, uintptr(device), uintptr(unsafe.Pointer(&isEnabledInt)), uintptr(unsafe.Pointer(&defaultIsEnabledInt)))
+	err = a.call(a.nvmlDeviceGetAutoBoostedClocksEnabled, uintptr(device), &isEnabledInt, &defaultIsEnabledInt)
 	if err != nil {
 		return
 	}
@@ -80,7 +96,7 @@ func (a API) DeviceGetAutoBoostedClocksEnabled(device Device) (isEnabled, defaul
 // BAR1 is used to map the FB (device memory) so that it can be directly accessed by the CPU or
 // by 3rd party devices (peer-to-peer on the PCIE bus).
 func (a API) DeviceGetBAR1MemoryInfo(device Device) (mem BAR1Memory, err error) {
-	err = a.call(a.nvmlDeviceGetBAR1MemoryInfo, uintptr(device), uintptr(unsafe.Pointer(&mem)))
+	err = a.call(a.nvmlDeviceGetBAR1MemoryInfo, uintptr(device), &mem)
 	return
 }
 
@@ -91,7 +107,7 @@ func (a API) DeviceGetBAR1MemoryInfo(device Device) (mem BAR1Memory, err error)
 // 0x100 and the two GPUs on a Tesla K10 in the same system returns 0x200 it is not guaranteed they will always return
 // those values but they will always be different from each other).
 func (a API) DeviceGetBoardID(device Device) (boardID uint32, err error) {
-	err = a.call(a.nvmlDeviceGetBoardId, uintptr(device), uintptr(unsafe.Pointer(&boardID)))
+	err = a.call(a.nvmlDeviceGetBoardId, uintptr(device), &boardID)
 	return
 }
 
@@ -100,7 +116,7 @@ func (a API) DeviceGetBoardPartNumber(device Device) (string, error) {
 	const bufferSize = 128
 
 	buffer := [bufferSize]C.char{}
-	if err := a.call(a.nvmlDeviceGetBoardPartNumber, uintptr(device), uintptr(unsafe.Pointer(&buffer[0])), bufferSize); err != nil {
+	if err := a.call(a.nvmlDeviceGetBoardPartNumber, uintptr(device), &buffer, bufferSize); err != nil {
 		return "", err
 	}
 
@@ -109,29 +125,52 @@ func (a API) DeviceGetBoardPartNumber(device Device) (string, error) {
 
 // DeviceGetBrand retrieves the brand of this device.
 func (a API) DeviceGetBrand(device Device) (brand BrandType, err error) {
-	err = a.call(a.nvmlDeviceGetBrand, uintptr(device), uintptr(unsafe.Pointer(&brand)))
+	err = a.call(a.nvmlDeviceGetBrand, uintptr(device), &brand)
 	return
 }
 
-func (a API) DeviceGetBridgeChipInfo() {
+// maxPhysicalBridges is the maximum number of bridge chips reported by DeviceGetBridgeChipInfo.
+const maxPhysicalBridges = 128
+
+// bridgeChipHierarchy mirrors nvmlBridgeChipHierarchy_t.
+type bridgeChipHierarchy struct {
+	BridgeCount    uint8
+	BridgeChipInfo [maxPhysicalBridges]BridgeChipInfo
+}
+
+// DeviceGetBridgeChipInfo gets the device's bridge chip firmware versions.
+// For all fully supported products.
+func (a API) DeviceGetBridgeChipInfo(device Device) (*BridgeChipHierarchy, error) {
+	var hierarchy bridgeChipHierarchy
+	if err := a.call(a.nvmlDeviceGetBridgeChipInfo, uintptr(device), &hierarchy); err != nil {
+		return nil, err
+	}
+
+	count := int(hierarchy.BridgeCount)
+	if count > maxPhysicalBridges {
+		count = maxPhysicalBridges
+	}
 
+	return &BridgeChipHierarchy{
+		BridgeChips: append([]BridgeChipInfo{}, hierarchy.BridgeChipInfo[:count]...),
+	}, nil
 }
 
 // DeviceGetClock retrieves the clock speed for the clock specified by the clock type and clock ID.
 func (a API) DeviceGetClock(device Device, clockType ClockType, clockID ClockID) (clockMHz uint32, err error) {
-	err = a.call(a.nvmlDeviceGetClock, uintptr(device), uintptr(clockType), uintptr(clockID), uintptr(unsafe.Pointer(&clockMHz)))
+	err = a.call(a.nvmlDeviceGetClock, uintptr(device), uintptr(clockType), uintptr(clockID), &clockMHz)
 	return
 }
 
 // DeviceGetClockInfo retrieves the current clock speeds for the device.
 func (a API) DeviceGetClockInfo(device Device, clockType ClockType) (clock uint32, err error) {
-	err = a.call(a.nvmlDeviceGetClockInfo, uintptr(device), uintptr(clockType), uintptr(unsafe.Pointer(&clock)))
+	err = a.call(a.nvmlDeviceGetClockInfo, uintptr(device), uintptr(clockType), &clock)
 	return
 }
 
 // DeviceGetComputeMode retrieves the current compute mode for the device.
 func (a API) DeviceGetComputeMode(device Device) (mode ComputeMode, err error) {
-	err = a.call(a.nvmlDeviceGetComputeMode, uintptr(device), uintptr(unsafe.Pointer(&mode)))
+	err = a.call(a.nvmlDeviceGetComputeMode, uintptr(device), &mode)
 	return
 }
 
@@ -141,10 +180,30 @@ func (a API) DeviceGetComputeMode(device Device) (mode ComputeMode, err error) {
 // Keep in mind that information returned by this call is dynamic and the number of elements might change in time.
 // Allocate more space for infos table in case new compute processes are spawned.
 func (a API) DeviceGetComputeRunningProcesses(device Device) ([]ProcessInfo, error) {
+	return a.runningProcesses(a.nvmlDeviceGetComputeRunningProcesses, a.versions["nvmlDeviceGetComputeRunningProcesses"], device)
+}
+
+// processInfoV1 mirrors nvmlProcessInfo_v1_t, filled by the unversioned running processes entry points.
+type processInfoV1 struct {
+	PID           uint32
+	UsedGPUMemory uint64
+}
+
+// processInfoV2 mirrors nvmlProcessInfo_v2_t, filled by the _v2 and _v3 running processes entry points.
+type processInfoV2 struct {
+	PID               uint32
+	UsedGPUMemory     uint64
+	GPUInstanceID     uint32
+	ComputeInstanceID uint32
+}
+
+// runningProcesses queries a list of processes with p, which is one of the compute or graphics running processes
+// entry points, decoding the struct layout of the given entry point version.
+func (a API) runningProcesses(p proc, version int, device Device) ([]ProcessInfo, error) {
 	var infoCount uint32
 
-	// Query the current number of running compute processes
-	err := a.call(a.nvmlDeviceGetComputeRunningProcesses, uintptr(device), uintptr(unsafe.Pointer(&infoCount)), 0)
+	// Query the current number of running processes
+	err := a.call(p, uintptr(device), &infoCount, 0)
 
 	// None are running
 	if err == nil || infoCount == 0 {
@@ -155,18 +214,43 @@ func (a API) DeviceGetComputeRunningProcesses(device Device) ([]ProcessInfo, err
 		return nil, err
 	}
 
-	list := make([]ProcessInfo, infoCount)
-	err = a.call(a.nvmlDeviceGetComputeRunningProcesses, uintptr(device), uintptr(unsafe.Pointer(&infoCount)), uintptr(unsafe.Pointer(&list[0])))
+	if version < 2 {
+		raw := make([]processInfoV1, infoCount)
+		err = a.call(p, uintptr(device), &infoCount, raw)
+		if err != nil {
+			return nil, err
+		}
+
+		list := make([]ProcessInfo, infoCount)
+		for i, info := range raw[:infoCount] {
+			list[i] = ProcessInfo{
+				PID:               info.PID,
+				UsedGPUMemory:     info.UsedGPUMemory,
+				GPUInstanceID:     math.MaxUint32,
+				ComputeInstanceID: math.MaxUint32,
+			}
+		}
+
+		return list, nil
+	}
+
+	raw := make([]processInfoV2, infoCount)
+	err = a.call(p, uintptr(device), &infoCount, raw)
 	if err != nil {
 		return nil, err
 	}
 
-	return list[:infoCount], nil
+	list := make([]ProcessInfo, infoCount)
+	for i, info := range raw[:infoCount] {
+		list[i] = ProcessInfo(info)
+	}
+
+	return list, nil
 }
 
 // DeviceGetCount retrieves the number of compute devices in the system. A compute device is a single GPU.
 func (a API) DeviceGetCount() (count uint32, err error) {
-	err = a.call(a.nvmlDeviceGetCount, uintptr(unsafe.Pointer(&count)))
+	err = a.call(a.nvmlDeviceGetCount, &count)
 	return
 }
 
@@ -175,39 +259,39 @@ func (a API) DeviceGetCount() (count uint32, err error) {
 // The major and minor versions are equivalent to the CU_DEVICE_ATTRIBUTE_COMPUTE_CAPABILITY_MINOR and
 // CU_DEVICE_ATTRIBUTE_COMPUTE_CAPABILITY_MAJOR attributes that would be returned by CUDA's cuDeviceGetAttribute().
 func (a API) DeviceGetCudaComputeCapability(device Device) (major, minor int32, err error) {
-	err = a.call(a.nvmlDeviceGetCudaComputeCapability, uintptr(device), uintptr(unsafe.Pointer(&major)), uintptr(unsafe.Pointer(&minor)))
+	err = a.call(a.nvmlDeviceGetCudaComputeCapability, uintptr(device), &major, &minor)
 	return
 }
 
 // DeviceGetCurrPcieLinkGeneration retrieves the current PCIe link generation.
 func (a API) DeviceGetCurrPcieLinkGeneration(device Device) (currLinkGen uint32, err error) {
-	err = a.call(a.nvmlDeviceGetCurrPcieLinkGeneration, uintptr(device), uintptr(unsafe.Pointer(&currLinkGen)))
+	err = a.call(a.nvmlDeviceGetCurrPcieLinkGeneration, uintptr(device), &currLinkGen)
 	return
 }
 
 // DeviceGetCurrPcieLinkWidth retrieves the current PCIe link width.
 func (a API) DeviceGetCurrPcieLinkWidth(device Device) (currLinkWidth uint32, err error) {
-	err = a.call(a.nvmlDeviceGetCurrPcieLinkWidth, uintptr(device), uintptr(unsafe.Pointer(&currLinkWidth)))
+	err = a.call(a.nvmlDeviceGetCurrPcieLinkWidth, uintptr(device), &currLinkWidth)
 	return
 }
 
 // DeviceGetCurrentClocksThrottleReasons retrieves current clocks throttling reasons.
 // More than one bit can be enabled at the same time. Multiple reasons can be affecting clocks at once.
 func (a API) DeviceGetCurrentClocksThrottleReasons(device Device) (clocksThrottleReasons ClocksThrottleReason, err error) {
-	err = a.call(a.nvmlDeviceGetCurrentClocksThrottleReasons, uintptr(device), uintptr(unsafe.Pointer(&clocksThrottleReasons)))
+	err = a.call(a.nvmlDeviceGetCurrentClocksThrottleReasons, uintptr(device), &clocksThrottleReasons)
 	return
 }
 
 // DeviceGetDecoderUtilization retrieves the current utilization and sampling size in microseconds for the Decoder.
 func (a API) DeviceGetDecoderUtilization(device Device) (utilization, samplingPeriodUs uint32, err error) {
-	err = a.call(a.nvmlDeviceGetDecoderUtilization, uintptr(device), uintptr(unsafe.Pointer(&utilization)), uintptr(unsafe.Pointer(&samplingPeriodUs)))
+	err = a.call(a.nvmlDeviceGetDecoderUtilization, uintptr(device), &utilization, &samplingPeriodUs)
 	return
 }
 
 // DeviceGetDefaultApplicationsClock retrieves the default applications clock that GPU boots with or
 // defaults to after DeviceResetApplicationsClocks call.
 func (a API) DeviceGetDefaultApplicationsClock(device Device, clockType ClockType) (clockMHz uint32, err error) {
-	err = a.call(a.nvmlDeviceGetDefaultApplicationsClock, uintptr(device), uintptr(clockType), uintptr(unsafe.Pointer(&clockMHz)))
+	err = a.call(a.nvmlDeviceGetDefaultApplicationsClock, uintptr(device), uintptr(clockType), &clockMHz)
 	return
 }
 
@@ -221,7 +305,7 @@ func (a API) DeviceGetDefaultApplicationsClock(device Device, clockType ClockTyp
 // On different GPU architectures different locations are supported, see DeviceGetMemoryErrorCounter
 func (a API) DeviceGetDetailedECCErrors(device Device, errorType MemoryErrorType, counterType ECCCounterType) (*ECCErrorCounts, error) {
 	counts := &ECCErrorCounts{}
-	if err := a.call(a.nvmlDeviceGetDetailedEccErrors, uintptr(device), uintptr(errorType), uintptr(counterType), uintptr(unsafe.Pointer(counts))); err != nil {
+	if err := a.call(a.nvmlDeviceGetDetailedEccErrors, uintptr(device), uintptr(errorType), uintptr(counterType), counts); err != nil {
 		return nil, err
 	}
 
@@ -234,7 +318,7 @@ func (a API) DeviceGetDetailedECCErrors(device Device, errorType MemoryErrorType
 // Display can be active even when no monitor is physically attached.
 func (a API) DeviceGetDisplayActive(device Device) (bool, error) {
 	var state int32
-	if err := a.call(a.nvmlDeviceGetDisplayActive, uintptr(device), uintptr(unsafe.Pointer(&state))); err != nil {
+	if err := a.call(a.nvmlDeviceGetDisplayActive, uintptr(device), &state); err != nil {
 		return false, err
 	}
 
@@ -249,7 +333,7 @@ func (a API) DeviceGetDisplayActive(device Device) (bool, error) {
 // (e.g. monitor) is currently connected to any of the device's connectors.
 func (a API) DeviceGetDisplayMode(device Device) (bool, error) {
 	var state int32
-	if err := a.call(a.nvmlDeviceGetDisplayMode, uintptr(device), uintptr(unsafe.Pointer(&state))); err != nil {
+	if err := a.call(a.nvmlDeviceGetDisplayMode, uintptr(device), &state); err != nil {
 		return false, err
 	}
 
@@ -264,7 +348,7 @@ func (a API) DeviceGetDisplayMode(device Device) (bool, error) {
 // On Windows platforms the device driver can run in either WDDM or WDM (TCC) mode.
 // If a display is attached to the device it must run in WDDM mode. TCC mode is preferred if a display is not attached.
 func (a API) DeviceGetDriverModel(device Device) (current, pending DriverModel, err error) {
-	err = a.call(a.nvmlDeviceGetDriverModel, uintptr(device), uintptr(unsafe.Pointer(&current)), uintptr(unsafe.Pointer(&pending)))
+	err = a.call(a.nvmlDeviceGetDriverModel, uintptr(device), &current, &pending)
 	return
 }
 
@@ -275,7 +359,7 @@ func (a API) DeviceGetECCMode(device Device) (current, pending bool, err error)
 	var currentInt int32
 	var pendingInt int32
 
-	err = a.call(a.nvmlDeviceGetEccMode, uintptr(device), uintptr(unsafe.Pointer(&currentInt)), uintptr(unsafe.Pointer(&pendingInt)))
+	err = a.call(a.nvmlDeviceGetEccMode, uintptr(device), &currentInt, &pendingInt)
 	if err != nil {
 		return
 	}
@@ -297,12 +381,30 @@ func (a API) DeviceGetECCMode(device Device) (current, pending bool, err error)
 
 // DeviceGetEncoderCapacity retrieves the current capacity of the device's encoder, in macroblocks per second.
 func (a API) DeviceGetEncoderCapacity(device Device, encoderQueryType EncoderType) (encoderCapacity uint32, err error) {
-	err = a.call(a.nvmlDeviceGetEncoderCapacity, uintptr(device), uintptr(encoderQueryType), uintptr(unsafe.Pointer(&encoderCapacity)))
+	err = a.call(a.nvmlDeviceGetEncoderCapacity, uintptr(device), uintptr(encoderQueryType), &encoderCapacity)
 	return
 }
 
-func (a API) DeviceGetEncoderSessions() error {
-	return ErrNotImplemented
+// DeviceGetEncoderSessions retrieves information about active encoder sessions on a target device.
+// The session info entries hold the session ID, owning process and vGPU instance, codec, resolution, average FPS
+// and latency of each session.
+func (a API) DeviceGetEncoderSessions(device Device) ([]EncoderSessionInfo, error) {
+	// Get the number of active sessions
+	var sessionCount uint32
+	if err := a.call(a.nvmlDeviceGetEncoderSessions, uintptr(device), &sessionCount, 0); err != nil {
+		return nil, err
+	}
+
+	if sessionCount == 0 {
+		return []EncoderSessionInfo{}, nil
+	}
+
+	list := make([]EncoderSessionInfo, sessionCount)
+	if err := a.call(a.nvmlDeviceGetEncoderSessions, uintptr(device), &sessionCount, list); err != nil {
+		return nil, err
+	}
+
+	return list[:sessionCount], nil
 }
 
 // DeviceGetEncoderStats retrieves the current encoder statistics for a given device.
@@ -310,15 +412,43 @@ func (a API) DeviceGetEncoderStats(device Device) (sessionCount, averageFPS, ave
 	err = a.call(
 		a.nvmlDeviceGetEncoderStats,
 		uintptr(device),
-		uintptr(unsafe.Pointer(&sessionCount)),
-		uintptr(unsafe.Pointer(&averageFPS)),
-		uintptr(unsafe.Pointer(&averageLatency)))
+		&sessionCount,
+		&averageFPS,
+		&averageLatency)
 	return
 }
 
 // DeviceGetEncoderUtilization retrieves the current utilization and sampling size in microseconds for the Encoder
 func (a API) DeviceGetEncoderUtilization(device Device) (utilization, samplingPeriodUs uint32, err error) {
-	err = a.call(a.nvmlDeviceGetEncoderUtilization, uintptr(device), uintptr(unsafe.Pointer(&utilization)), uintptr(unsafe.Pointer(&samplingPeriodUs)))
+	err = a.call(a.nvmlDeviceGetEncoderUtilization, uintptr(device), &utilization, &samplingPeriodUs)
+	return
+}
+
+// DeviceGetFBCSessions retrieves information about active frame buffer capture sessions on a target device.
+// For Maxwell or newer fully supported devices.
+func (a API) DeviceGetFBCSessions(device Device) ([]FBCSessionInfo, error) {
+	// Get the number of active sessions
+	var sessionCount uint32
+	if err := a.call(a.nvmlDeviceGetFBCSessions, uintptr(device), &sessionCount, 0); err != nil {
+		return nil, err
+	}
+
+	if sessionCount == 0 {
+		return []FBCSessionInfo{}, nil
+	}
+
+	list := make([]FBCSessionInfo, sessionCount)
+	if err := a.call(a.nvmlDeviceGetFBCSessions, uintptr(device), &sessionCount, list); err != nil {
+		return nil, err
+	}
+
+	return list[:sessionCount], nil
+}
+
+// DeviceGetFBCStats retrieves the active frame buffer capture sessions statistics for a given device.
+// For Maxwell or newer fully supported devices.
+func (a API) DeviceGetFBCStats(device Device) (fbcStats FBCStats, err error) {
+	err = a.call(a.nvmlDeviceGetFBCStats, uintptr(device), &fbcStats)
 	return
 }
 
@@ -326,7 +456,7 @@ func (a API) DeviceGetEncoderUtilization(device Device) (utilization, samplingPe
 // Note: This can be different from the DeviceGetPowerManagementLimit if other limits are set elsewhere.
 // This includes the out of band power limit interface
 func (a API) DeviceGetEnforcedPowerLimit(device Device) (limit uint32, err error) {
-	err = a.call(a.nvmlDeviceGetEnforcedPowerLimit, uintptr(device), uintptr(unsafe.Pointer(&limit)))
+	err = a.call(a.nvmlDeviceGetEnforcedPowerLimit, uintptr(device), &limit)
 	return
 }
 
@@ -335,16 +465,73 @@ func (a API) DeviceGetEnforcedPowerLimit(device Device) (limit uint32, err error
 // the output will not match the actual fan speed.
 // The fan speed is expressed as a percent of the maximum, i.e. full speed is 100%.
 func (a API) DeviceGetFanSpeed(device Device) (speed uint32, err error) {
-	err = a.call(a.nvmlDeviceGetFanSpeed, uintptr(device), uintptr(unsafe.Pointer(&speed)))
+	err = a.call(a.nvmlDeviceGetFanSpeed, uintptr(device), &speed)
 	return
 }
 
+// fieldValue mirrors the nvmlFieldValue_t layout.
+type fieldValue struct {
+	FieldID     FieldID
+	ScopeID     uint32
+	TimeStamp   int64
+	LatencyUsec int64
+	ValueType   ValueType
+	Return      int32
+	Value       uint64
+}
+
+// newFieldValues returns the nvmlFieldValue_t array to pass NVML for the given requests.
+func newFieldValues(requests []FieldRequest) []fieldValue {
+	raw := make([]fieldValue, len(requests))
+	for i, request := range requests {
+		raw[i].FieldID = request.FieldID
+		raw[i].ScopeID = request.ScopeID
+	}
+
+	return raw
+}
+
+// DeviceGetFieldValues requests values for a list of fields for a device.
+// This API allows multiple fields to be queried at once. If any of the underlying field IDs are populated by the
+// same driver call, the results for those field IDs will be populated from a single call rather than making a driver
+// call for each field ID.
+// Values are returned in the order of requests. Fields NVML fails to query have their Err set, the other fields
+// are still returned.
+func (a API) DeviceGetFieldValues(device Device, requests []FieldRequest) ([]FieldValue, error) {
+	if len(requests) == 0 {
+		return []FieldValue{}, nil
+	}
+
+	raw := newFieldValues(requests)
+	if err := a.call(a.nvmlDeviceGetFieldValues, uintptr(device), uintptr(len(raw)), raw); err != nil {
+		return nil, err
+	}
+
+	values := make([]FieldValue, len(raw))
+	for i, v := range raw {
+		values[i] = FieldValue{
+			FieldID:     v.FieldID,
+			ScopeID:     v.ScopeID,
+			TimeStamp:   v.TimeStamp,
+			LatencyUsec: v.LatencyUsec,
+			ValueType:   v.ValueType,
+			Err:         returnValueToError(int(v.Return)),
+		}
+
+		if values[i].Err == nil {
+			values[i].Value = decodeValue(v.ValueType, v.Value)
+		}
+	}
+
+	return values, nil
+}
+
 // DeviceGetGPUOperationMode retrieves the current GOM and pending GOM (the one that GPU will switch to after reboot).
 // For GK110 M-class and X-class Tesla products from the Kepler family.
 // Modes NVML_GOM_LOW_DP and NVML_GOM_ALL_ON are supported on fully supported GeForce products.
 // Not supported on Quadro and Tesla C-class products.
 func (a API) DeviceGetGPUOperationMode(device Device) (current, pending GPUOperationMode, err error) {
-	err = a.call(a.nvmlDeviceGetGpuOperationMode, uintptr(device), uintptr(unsafe.Pointer(&current)), uintptr(unsafe.Pointer(&pending)))
+	err = a.call(a.nvmlDeviceGetGpuOperationMode, uintptr(device), &current, &pending)
 	return
 }
 
@@ -353,32 +540,12 @@ func (a API) DeviceGetGPUOperationMode(device Device) (current, pending GPUOpera
 // Keep in mind that information returned by this call is dynamic and the number of elements might change in time.
 // Allocate more space for infos table in case new graphics processes are spawned.
 func (a API) DeviceGetGraphicsRunningProcesses(device Device) ([]ProcessInfo, error) {
-	var infoCount uint32
-
-	// Query the current number of running compute processes
-	err := a.call(a.nvmlDeviceGetGraphicsRunningProcesses, uintptr(device), uintptr(unsafe.Pointer(&infoCount)), 0)
-
-	// None are running
-	if err == nil || infoCount == 0 {
-		return []ProcessInfo{}, nil
-	}
-
-	if err != ErrInsufficientSize {
-		return nil, err
-	}
-
-	list := make([]ProcessInfo, infoCount)
-	err = a.call(a.nvmlDeviceGetGraphicsRunningProcesses, uintptr(device), uintptr(unsafe.Pointer(&infoCount)), uintptr(unsafe.Pointer(&list[0])))
-	if err != nil {
-		return nil, err
-	}
-
-	return list[:infoCount], nil
+	return a.runningProcesses(a.nvmlDeviceGetGraphicsRunningProcesses, a.versions["nvmlDeviceGetGraphicsRunningProcesses"], device)
 }
 
 // DeviceGetHandleByIndex acquires the handle for a particular device, based on its index.
 func (a API) DeviceGetHandleByIndex(index uint32) (device Device, err error) {
-	err = a.call(a.nvmlDeviceGetHandleByIndex, uintptr(index), uintptr(unsafe.Pointer(&device)))
+	err = a.call(a.nvmlDeviceGetHandleByIndex, uintptr(index), &device)
 	return
 }
 
@@ -391,7 +558,7 @@ func (a API) DeviceGetHandleByPCIBusID(pciBusID string) (device Device, err erro
 	cstr := C.CString(pciBusID)
 	defer C.free(unsafe.Pointer(cstr))
 
-	err = a.call(a.nvmlDeviceGetHandleByPciBusId, uintptr(unsafe.Pointer(cstr)), uintptr(unsafe.Pointer(&device)))
+	err = a.call(a.nvmlDeviceGetHandleByPciBusId, cstr, &device)
 	return
 }
 
@@ -402,7 +569,7 @@ func (a API) DeviceGetHandleBySerial(serial string) (device Device, err error) {
 	cstr := C.CString(serial)
 	defer C.free(unsafe.Pointer(cstr))
 
-	err = a.call(a.nvmlDeviceGetHandleBySerial, uintptr(unsafe.Pointer(cstr)), uintptr(unsafe.Pointer(&device)))
+	err = a.call(a.nvmlDeviceGetHandleBySerial, cstr, &device)
 	return
 }
 
@@ -412,13 +579,13 @@ func (a API) DeviceGetHandleByUUID(uuid string) (device Device, err error) {
 	cstr := C.CString(uuid)
 	defer C.free(unsafe.Pointer(cstr))
 
-	err = a.call(a.nvmlDeviceGetHandleByUUID, uintptr(unsafe.Pointer(cstr)), uintptr(unsafe.Pointer(&device)))
+	err = a.call(a.nvmlDeviceGetHandleByUUID, cstr, &device)
 	return
 }
 
 // DeviceGetIndex retrieves the NVML index of this device.
 func (a API) DeviceGetIndex(device Device) (index uint32, err error) {
-	err = a.call(a.nvmlDeviceGetIndex, uintptr(device), uintptr(unsafe.Pointer(&index)))
+	err = a.call(a.nvmlDeviceGetIndex, uintptr(device), &index)
 	return
 }
 
@@ -427,7 +594,7 @@ func (a API) DeviceGetIndex(device Device) (index uint32, err error) {
 // Current checksum takes into account configuration stored in PWR and ECC infoROM objects.
 // Checksum can change between driver releases or when user changes configuration (e.g. disable/enable ECC)
 func (a API) DeviceGetInforomConfigurationChecksum(device Device) (checksum uint32, err error) {
-	err = a.call(a.nvmlDeviceGetInforomConfigurationChecksum, uintptr(device), uintptr(unsafe.Pointer(&checksum)))
+	err = a.call(a.nvmlDeviceGetInforomConfigurationChecksum, uintptr(device), &checksum)
 	return
 }
 
@@ -436,7 +603,7 @@ func (a API) DeviceGetInforomConfigurationChecksum(device Device) (checksum uint
 // which is only an indicator of supported features.
 func (a API) DeviceGetInfoROMImageVersion(device Device) (string, error) {
 	buffer := [deviceInfoROMVersionBufferSize]C.char{}
-	if err := a.call(a.nvmlDeviceGetInforomImageVersion, uintptr(device), uintptr(unsafe.Pointer(&buffer[0])), deviceInfoROMVersionBufferSize); err != nil {
+	if err := a.call(a.nvmlDeviceGetInforomImageVersion, uintptr(device), &buffer, deviceInfoROMVersionBufferSize); err != nil {
 		return "", err
 	}
 
@@ -446,7 +613,7 @@ func (a API) DeviceGetInfoROMImageVersion(device Device) (string, error) {
 // DeviceGetInfoROMVersion retrieves the version information for the device's infoROM object.
 func (a API) DeviceGetInfoROMVersion(device Device, object InfoROMObject) (string, error) {
 	buffer := [deviceInfoROMVersionBufferSize]C.char{}
-	if err := a.call(a.nvmlDeviceGetInforomVersion, uintptr(device), uintptr(object), uintptr(unsafe.Pointer(&buffer[0])), deviceInfoROMVersionBufferSize); err != nil {
+	if err := a.call(a.nvmlDeviceGetInforomVersion, uintptr(device), uintptr(object), &buffer, deviceInfoROMVersionBufferSize); err != nil {
 		return "", err
 	}
 
@@ -455,13 +622,13 @@ func (a API) DeviceGetInfoROMVersion(device Device, object InfoROMObject) (strin
 
 // DeviceGetMaxClockInfo retrieves the maximum clock speeds for the device.
 func (a API) DeviceGetMaxClockInfo(device Device, clockType ClockType) (clock uint32, err error) {
-	err = a.call(a.nvmlDeviceGetMaxClockInfo, uintptr(device), uintptr(clockType), uintptr(unsafe.Pointer(&clock)))
+	err = a.call(a.nvmlDeviceGetMaxClockInfo, uintptr(device), uintptr(clockType), &clock)
 	return
 }
 
 // DeviceGetMaxCustomerBoostClock retrieves the customer defined maximum boost clock speed specified by the given clock type.
 func (a API) DeviceGetMaxCustomerBoostClock(device Device, clockType ClockType) (clockMHz uint32, err error) {
-	err = a.call(a.nvmlDeviceGetMaxCustomerBoostClock, uintptr(device), uintptr(clockType), uintptr(unsafe.Pointer(&clockMHz)))
+	err = a.call(a.nvmlDeviceGetMaxCustomerBoostClock, uintptr(device), uintptr(clockType), &clockMHz)
 	return
 }
 
@@ -469,14 +636,14 @@ func (a API) DeviceGetMaxCustomerBoostClock(device Device, clockType ClockType)
 // I.E. for a generation 2 PCIe device attached to a generation 1 PCIe bus the max link generation this function will
 // report is generation 1.
 func (a API) DeviceGetMaxPcieLinkGeneration(device Device) (maxLinkGen uint32, err error) {
-	err = a.call(a.nvmlDeviceGetMaxPcieLinkGeneration, uintptr(device), uintptr(unsafe.Pointer(&maxLinkGen)))
+	err = a.call(a.nvmlDeviceGetMaxPcieLinkGeneration, uintptr(device), &maxLinkGen)
 	return
 }
 
 // DeviceGetMaxPcieLinkWidth retrieves the maximum PCIe link width possible with this device and system
 // I.E. for a device with a 16x PCIe bus width attached to a 8x PCIe system bus this function will report a max link width of 8.
 func (a API) DeviceGetMaxPcieLinkWidth(device Device) (maxLinkWidth uint32, err error) {
-	err = a.call(a.nvmlDeviceGetMaxPcieLinkWidth, uintptr(device), uintptr(unsafe.Pointer(&maxLinkWidth)))
+	err = a.call(a.nvmlDeviceGetMaxPcieLinkWidth, uintptr(device), &maxLinkWidth)
 	return
 }
 
@@ -490,27 +657,28 @@ func (a API) DeviceGetMemoryErrorCounter(device Device, errorType MemoryErrorTyp
 		uintptr(errorType),
 		uintptr(counterType),
 		uintptr(locationType),
-		uintptr(unsafe.Pointer(&count)))
+		&count)
 	return
 }
 
 // DeviceGetMemoryInfo retrieves the amount of used, free and total memory available on the device, in bytes.
+// When given a MIG device handle, the memory of the MIG device's GPU instance is reported.
 func (a API) DeviceGetMemoryInfo(device Device) (mem Memory, err error) {
-	err = a.call(a.nvmlDeviceGetMemoryInfo, uintptr(device), uintptr(unsafe.Pointer(&mem)))
+	err = a.call(a.nvmlDeviceGetMemoryInfo, uintptr(device), &mem)
 	return
 }
 
 // DeviceGetMinorNumber retrieves minor number for the device. The minor number for the device is such that
 // the Nvidia device node file for each GPU will have the form /dev/nvidia[minor number].
 func (a API) DeviceGetMinorNumber(device Device) (minorNumber uint32, err error) {
-	err = a.call(a.nvmlDeviceGetMinorNumber, uintptr(device), uintptr(unsafe.Pointer(&minorNumber)))
+	err = a.call(a.nvmlDeviceGetMinorNumber, uintptr(device), &minorNumber)
 	return
 }
 
 // DeviceGetMultiGpuBoard retrieves whether the device is on a Multi-GPU Board.
 func (a API) DeviceGetMultiGpuBoard(device Device) (multiGpu bool, err error) {
 	var multiGpuBool uint
-	err = a.call(a.nvmlDeviceGetMultiGpuBoard, uintptr(device), uintptr(unsafe.Pointer(&multiGpuBool)))
+	err = a.call(a.nvmlDeviceGetMultiGpuBoard, uintptr(device), &multiGpuBool)
 	if err != nil {
 		return
 	}
@@ -527,25 +695,56 @@ func (a API) DeviceGetMultiGpuBoard(device Device) (multiGpu bool, err error) {
 // DeviceGetName retrieves the name of this device.
 func (a API) DeviceGetName(device Device) (string, error) {
 	buffer := [deviceNameBufferSize]C.char{}
-	if err := a.call(a.nvmlDeviceGetName, uintptr(device), uintptr(unsafe.Pointer(&buffer[0])), deviceNameBufferSize); err != nil {
+	if err := a.call(a.nvmlDeviceGetName, uintptr(device), &buffer, deviceNameBufferSize); err != nil {
 		return "", err
 	}
 
 	return C.GoString(&buffer[0]), nil
 }
 
-func (a API) DeviceGetP2PStatus() error {
-	return ErrNotImplemented
+// DeviceGetP2PStatus retrieves the status for a given p2p capability index between a given pair of GPU.
+func (a API) DeviceGetP2PStatus(device1 Device, device2 Device, p2pIndex P2PCapsIndex) (p2pStatus P2PStatus, err error) {
+	err = a.call(a.nvmlDeviceGetP2PStatus, uintptr(device1), uintptr(device2), uintptr(p2pIndex), &p2pStatus)
+	return
 }
 
+// DeviceGetPCIInfo retrieves the PCI attributes of this device.
+// With drivers exporting nvmlDeviceGetPciInfo_v3, BusID holds the domain:bus:device.function identifier with 32-bit
+// domain and BusIDLegacy the legacy identifier with 16-bit domain. Older drivers only report the legacy identifier,
+// which is returned in both fields.
 func (a API) DeviceGetPCIInfo(device Device) (*PCIInfo, error) {
+	return a.pciInfo(a.nvmlDeviceGetPciInfo, a.versions["nvmlDeviceGetPciInfo"] < 3, uintptr(device))
+}
+
+// pciInfo queries PCI attributes with p, which takes a pointer to a PCI info struct after the given args.
+// legacy tells whether p fills nvmlPciInfoLegacy_t rather than nvmlPciInfo_t.
+func (a API) pciInfo(p proc, legacy bool, args ...interface{}) (*PCIInfo, error) {
+	if legacy {
+		var pci C.nvmlPciInfoLegacy_t
+		if err := a.call(p, append(args, &pci)...); err != nil {
+			return nil, err
+		}
+
+		busID := C.GoString(&pci.busId[0])
+		return &PCIInfo{
+			BusID:          busID,
+			BusIDLegacy:    busID,
+			Domain:         uint32(pci.domain),
+			Bus:            uint32(pci.bus),
+			Device:         uint32(pci.device),
+			PCIDeviceID:    uint32(pci.pciDeviceId),
+			PCISubsystemID: uint32(pci.pciSubSystemId),
+		}, nil
+	}
+
 	var pci C.nvmlPciInfo_t
-	if err := a.call(a.nvmlDeviceGetPciInfo, uintptr(device), uintptr(unsafe.Pointer(&pci))); err != nil {
+	if err := a.call(p, append(args, &pci)...); err != nil {
 		return nil, err
 	}
 
 	return &PCIInfo{
 		BusID:          C.GoString(&pci.busId[0]),
+		BusIDLegacy:    C.GoString(&pci.busIdLegacy[0]),
 		Domain:         uint32(pci.domain),
 		Bus:            uint32(pci.bus),
 		Device:         uint32(pci.device),
@@ -554,9 +753,32 @@ func (a API) DeviceGetPCIInfo(device Device) (*PCIInfo, error) {
 	}, nil
 }
 
+// newPciInfo returns the nvmlPciInfo_t identifying pci, for the calls taking PCI info rather than a device handle.
+// The legacy identifier comes first in nvmlPciInfo_t, where nvmlPciInfoLegacy_t has its only identifier, so drivers
+// predating nvmlDeviceGetPciInfo_v3 read it as well.
+func newPciInfo(pci *PCIInfo) *C.nvmlPciInfo_t {
+	raw := &C.nvmlPciInfo_t{
+		domain:         C.uint(pci.Domain),
+		bus:            C.uint(pci.Bus),
+		device:         C.uint(pci.Device),
+		pciDeviceId:    C.uint(pci.PCIDeviceID),
+		pciSubSystemId: C.uint(pci.PCISubsystemID),
+	}
+
+	for i := 0; i < len(pci.BusIDLegacy) && i < len(raw.busIdLegacy)-1; i++ {
+		raw.busIdLegacy[i] = C.char(pci.BusIDLegacy[i])
+	}
+
+	for i := 0; i < len(pci.BusID) && i < len(raw.busId)-1; i++ {
+		raw.busId[i] = C.char(pci.BusID[i])
+	}
+
+	return raw
+}
+
 // DeviceGetPcieReplayCounter retrieve the PCIe replay counter.
 func (a API) DeviceGetPcieReplayCounter(device Device) (value uint32, err error) {
-	err = a.call(a.nvmlDeviceGetPcieReplayCounter, uintptr(device), uintptr(unsafe.Pointer(&value)))
+	err = a.call(a.nvmlDeviceGetPcieReplayCounter, uintptr(device), &value)
 	return
 }
 
@@ -564,20 +786,20 @@ func (a API) DeviceGetPcieReplayCounter(device Device) (value uint32, err error)
 // This function is querying a byte counter over a 20ms interval and thus is the PCIe throughput over that interval.
 // This method is not supported in virtual machines running virtual GPU (vGPU).
 func (a API) DeviceGetPCIeThroughput(device Device, counter PCIeUtilCounter) (value uint32, err error) {
-	err = a.call(a.nvmlDeviceGetPcieThroughput, uintptr(device), uintptr(counter), uintptr(unsafe.Pointer(&value)))
+	err = a.call(a.nvmlDeviceGetPcieThroughput, uintptr(device), uintptr(counter), &value)
 	return
 }
 
 // DeviceGetPerformanceState retrieves the current performance state for the device.
 func (a API) DeviceGetPerformanceState(device Device) (state PState, err error) {
-	err = a.call(a.nvmlDeviceGetPerformanceState, uintptr(device), uintptr(unsafe.Pointer(&state)))
+	err = a.call(a.nvmlDeviceGetPerformanceState, uintptr(device), &state)
 	return
 }
 
 // DeviceGetPowerManagementDefaultLimit retrieves default power management limit on this device, in milliwatts.
 // Default power management limit is a power management limit that the device boots with.
 func (a API) DeviceGetPowerManagementDefaultLimit(device Device) (defaultLimit uint32, err error) {
-	err = a.call(a.nvmlDeviceGetPowerManagementDefaultLimit, uintptr(device), uintptr(unsafe.Pointer(&defaultLimit)))
+	err = a.call(a.nvmlDeviceGetPowerManagementDefaultLimit, uintptr(device), &defaultLimit)
 	return
 }
 
@@ -586,13 +808,13 @@ func (a API) DeviceGetPowerManagementDefaultLimit(device Device) (defaultLimit u
 // If the card's total power draw reaches this limit the power management algorithm kicks in.
 // This reading is only available if power management mode is supported, see DeviceGetPowerManagementMode.
 func (a API) DeviceGetPowerManagementLimit(device Device) (limit uint32, err error) {
-	err = a.call(a.nvmlDeviceGetPowerManagementLimit, uintptr(device), uintptr(unsafe.Pointer(&limit)))
+	err = a.call(a.nvmlDeviceGetPowerManagementLimit, uintptr(device), &limit)
 	return
 }
 
 // DeviceGetPowerManagementLimitConstraints retrieves information about possible values of power management limits on this device.
 func (a API) DeviceGetPowerManagementLimitConstraints(device Device) (minLimit, maxLimit uint32, err error) {
-	err = a.call(a.nvmlDeviceGetPowerManagementLimitConstraints, uintptr(device), uintptr(unsafe.Pointer(&minLimit)), uintptr(unsafe.Pointer(&maxLimit)))
+	err = a.call(a.nvmlDeviceGetPowerManagementLimitConstraints, uintptr(device), &minLimit, &maxLimit)
 	return
 }
 
@@ -603,7 +825,7 @@ func (a API) DeviceGetPowerManagementLimitConstraints(device Device) (minLimit,
 // do so if the appropriate conditions are met.
 func (a API) DeviceGetPowerManagementMode(device Device) (bool, error) {
 	var state int32
-	if err := a.call(a.nvmlDeviceGetPowerManagementMode, uintptr(device), uintptr(unsafe.Pointer(&state))); err != nil {
+	if err := a.call(a.nvmlDeviceGetPowerManagementMode, uintptr(device), &state); err != nil {
 		return false, nil
 	}
 
@@ -618,13 +840,64 @@ func (a API) DeviceGetPowerManagementMode(device Device) (bool, error) {
 // Deprecated: Use DeviceGetPerformanceState.
 // This function exposes an incorrect generalization.
 func (a API) DeviceGetPowerState(device Device) (state PState, err error) {
-	err = a.call(a.nvmlDeviceGetPowerState, uintptr(device), uintptr(unsafe.Pointer(&state)))
+	err = a.call(a.nvmlDeviceGetPowerState, uintptr(device), &state)
 	return
 }
 
 // DeviceGetPowerUsage retrieves power usage for this GPU in milliwatts and its associated circuitry (e.g. memory)
 func (a API) DeviceGetPowerUsage(device Device) (power uint32, err error) {
-	err = a.call(a.nvmlDeviceGetPowerUsage, uintptr(device), uintptr(unsafe.Pointer(&power)))
+	err = a.call(a.nvmlDeviceGetPowerUsage, uintptr(device), &power)
+	return
+}
+
+// DeviceGetProcessUtilization retrieves the current utilization and process ID.
+// For Maxwell or newer fully supported devices.
+// Reads recent utilization of GPU SM (3D/Compute), framebuffer, video encoder, and video decoder for processes
+// running. Utilization values are returned as an array of utilization sample structures. Each sample has the PID of a
+// process and the timestamp at which it was taken.
+// To get the samples for the specific duration, set lastSeenTimeStamp to the timestamp of the last sample seen,
+// or 0 to fetch all the samples maintained in the buffer. An empty list is returned when no sample is newer than
+// lastSeenTimeStamp.
+func (a API) DeviceGetProcessUtilization(device Device, lastSeenTimeStamp uint64) ([]ProcessUtilizationSample, error) {
+	var count uint32
+
+	// Get the number of samples in the buffer
+	err := a.call(a.nvmlDeviceGetProcessUtilization, uintptr(device), 0, &count, uintptr(lastSeenTimeStamp))
+	if err == ErrNotFound || (err == nil && count == 0) {
+		return []ProcessUtilizationSample{}, nil
+	}
+
+	if err != nil && err != ErrInsufficientSize {
+		return nil, err
+	}
+
+	samples := make([]ProcessUtilizationSample, count)
+	err = a.call(a.nvmlDeviceGetProcessUtilization, uintptr(device), samples, &count, uintptr(lastSeenTimeStamp))
+	if err == ErrNotFound {
+		return []ProcessUtilizationSample{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return samples[:count], nil
+}
+
+// DeviceGetRemappedRows returns the number of remapped rows. The number of rows reported is based on the cause of
+// the remapping. isPending indicates whether or not there are pending remappings. A reset will be required to actually
+// remap the row. failureOccurred will be set if a row remapping ever failed in the past. A pending remapping won't
+// affect future work on the GPU since error-containment and dynamic page blacklisting will take care of that.
+// For Ampere or newer fully supported devices.
+func (a API) DeviceGetRemappedRows(device Device) (correctable, uncorrectable uint32, isPending, failureOccurred bool, err error) {
+	var pending, failure uint32
+	err = a.call(a.nvmlDeviceGetRemappedRows, uintptr(device), &correctable, &uncorrectable, &pending, &failure)
+	if err != nil {
+		return
+	}
+
+	isPending = pending != 0
+	failureOccurred = failure != 0
 	return
 }
 
@@ -634,7 +907,7 @@ func (a API) DeviceGetPowerUsage(device Device) (power uint32, err error) {
 func (a API) DeviceGetRetiredPages(device Device, cause PageRetirementCause) ([]uint64, error) {
 	// Get array size
 	var count uint32
-	err := a.call(a.nvmlDeviceGetRetiredPages, uintptr(device), uintptr(cause), uintptr(unsafe.Pointer(&count)), 0)
+	err := a.call(a.nvmlDeviceGetRetiredPages, uintptr(device), uintptr(cause), &count, 0)
 	if err == nil {
 		return []uint64{}, nil
 	}
@@ -648,8 +921,8 @@ func (a API) DeviceGetRetiredPages(device Device, cause PageRetirementCause) ([]
 	err = a.call(a.nvmlDeviceGetRetiredPages,
 		uintptr(device),
 		uintptr(cause),
-		uintptr(unsafe.Pointer(&count)),
-		uintptr(unsafe.Pointer(&list[0])))
+		&count,
+		list)
 
 	if err != nil {
 		return nil, err
@@ -658,10 +931,68 @@ func (a API) DeviceGetRetiredPages(device Device, cause PageRetirementCause) ([]
 	return list, nil
 }
 
+// DeviceGetAllRetiredPages returns the pages retired for any cause, including pages that are pending retirement,
+// along with the time of their retirement. Pages are sorted by time, the oldest first.
+// The address information provided from this API is the hardware address of the page that was retired.
+// Note that this does not match the virtual address used in CUDA, but will match the address information in XID 63.
+// Drivers without nvmlDeviceGetRetiredPages_v2 don't report the time of retirement, which is left 0.
+func (a API) DeviceGetAllRetiredPages(device Device) ([]RetiredPage, error) {
+	timed := a.versions["nvmlDeviceGetRetiredPages"] >= 2
+
+	list := []RetiredPage{}
+	for _, cause := range []PageRetirementCause{
+		PageRetirementCauseMultipleSingleBitECCErrors,
+		PageRetirementCauseDoubleBitECCError,
+	} {
+		// Get array size
+		var count uint32
+		args := []interface{}{uintptr(device), uintptr(cause), &count, 0}
+		if timed {
+			args = append(args, 0)
+		}
+
+		err := a.call(a.nvmlDeviceGetRetiredPagesV2, args...)
+		if err == nil {
+			continue
+		}
+
+		if err != ErrInsufficientSize {
+			return nil, err
+		}
+
+		// Query data
+		addresses := make([]uint64, count)
+		timestamps := make([]uint64, count)
+		args = []interface{}{uintptr(device), uintptr(cause), &count, addresses}
+		if timed {
+			args = append(args, timestamps)
+		}
+
+		err = a.call(a.nvmlDeviceGetRetiredPagesV2, args...)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := uint32(0); i < count; i++ {
+			list = append(list, RetiredPage{Address: addresses[i], Time: timestamps[i], Cause: cause})
+		}
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Time != list[j].Time {
+			return list[i].Time < list[j].Time
+		}
+
+		return list[i].Address < list[j].Address
+	})
+
+	return list, nil
+}
+
 // DeviceGetRetiredPagesPendingStatus checks if any pages are pending retirement and need a reboot to fully retire.
 func (a API) DeviceGetRetiredPagesPendingStatus(device Device) (isPending bool, err error) {
 	var state int32 = 0
-	err = a.call(a.nvmlDeviceGetRetiredPagesPendingStatus, uintptr(device), uintptr(unsafe.Pointer(&state)))
+	err = a.call(a.nvmlDeviceGetRetiredPagesPendingStatus, uintptr(device), &state)
 	if err != nil {
 		return
 	}
@@ -675,14 +1006,88 @@ func (a API) DeviceGetRetiredPagesPendingStatus(device Device) (isPending bool,
 	return
 }
 
-func (a API) DeviceGetSamples() error {
-	return ErrNotImplemented
+// rawSample mirrors nvmlSample_t, the value is decoded according to the value type reported by NVML.
+type rawSample struct {
+	TimeStamp uint64
+	Value     uint64
+}
+
+// DeviceGetRowRemapperHistogram returns the row remapper histogram, the remap availability for each bank on the GPU.
+// For Ampere or newer fully supported devices.
+func (a API) DeviceGetRowRemapperHistogram(device Device) (histogram RowRemapperHistogram, err error) {
+	err = a.call(a.nvmlDeviceGetRowRemapperHistogram, uintptr(device), &histogram)
+	return
+}
+
+// DeviceGetSamples gets recent samples for the GPU.
+// Based on type, this method can be used to fetch the power, utilization or clock samples maintained in the buffer by
+// the driver. Power, utilization and clock samples are returned as type "unsigned int" for the union nvmlValue_t.
+// To get the samples for the specific duration, set lastSeenTimeStamp to the timestamp of the last sample seen,
+// or 0 to fetch all the samples maintained in the buffer. An empty list is returned when no sample is newer than
+// lastSeenTimeStamp.
+// On Kepler and Maxwell GPUs the samples are refreshed every 50 ms to 100 ms, based on the sampling type.
+func (a API) DeviceGetSamples(device Device, samplingType SamplingType, lastSeenTimeStamp uint64) (ValueType, []Sample, error) {
+	var (
+		valueType ValueType
+		count     uint32
+	)
+
+	// Get the number of samples in the buffer
+	err := a.call(a.nvmlDeviceGetSamples, uintptr(device), uintptr(samplingType), uintptr(lastSeenTimeStamp), &valueType, &count, 0)
+	if err == ErrNotFound || (err == nil && count == 0) {
+		return valueType, []Sample{}, nil
+	}
+
+	if err != nil {
+		return valueType, nil, err
+	}
+
+	raw := make([]rawSample, count)
+	err = a.call(a.nvmlDeviceGetSamples, uintptr(device), uintptr(samplingType), uintptr(lastSeenTimeStamp), &valueType, &count, raw)
+	if err == ErrNotFound {
+		return valueType, []Sample{}, nil
+	}
+
+	if err != nil {
+		return valueType, nil, err
+	}
+
+	list := make([]Sample, count)
+	for i, sample := range raw[:count] {
+		list[i] = Sample{
+			TimeStamp: sample.TimeStamp,
+			Value:     decodeValue(valueType, sample.Value),
+		}
+	}
+
+	return valueType, list, nil
+}
+
+// decodeValue converts the bits of a nvmlValue_t union to the Go type matching valueType.
+func decodeValue(valueType ValueType, bits uint64) interface{} {
+	switch valueType {
+	case ValueTypeDouble:
+		return math.Float64frombits(bits)
+	case ValueTypeUnsignedInt:
+		return uint32(bits)
+	case ValueTypeUnsignedLong:
+		// unsigned long is 32-bit wide on Windows
+		if unsafe.Sizeof(C.ulong(0)) == 4 {
+			return uint64(uint32(bits))
+		}
+
+		return bits
+	case ValueTypeSignedLongLong:
+		return int64(bits)
+	default:
+		return bits
+	}
 }
 
 // DeviceGetSerial retrieves the globally unique board serial number associated with this device's board.
 func (a API) DeviceGetSerial(device Device) (serial string, err error) {
 	buffer := [deviceSerialBufferSize]C.char{}
-	err = a.call(a.nvmlDeviceGetSerial, uintptr(device), uintptr(unsafe.Pointer(&buffer[0])), deviceSerialBufferSize)
+	err = a.call(a.nvmlDeviceGetSerial, uintptr(device), &buffer, deviceSerialBufferSize)
 	return
 }
 
@@ -690,7 +1095,7 @@ func (a API) DeviceGetSerial(device Device) (serial string, err error) {
 // returned by DeviceGetCurrentClocksThrottleReasons. This method is not supported in virtual machines
 // running virtual GPU (vGPU).
 func (a API) DeviceGetSupportedClocksThrottleReasons(device Device) (supportedClocksThrottleReasons ClocksThrottleReason, err error) {
-	err = a.call(a.nvmlDeviceGetSupportedClocksThrottleReasons, uintptr(device), uintptr(unsafe.Pointer(&supportedClocksThrottleReasons)))
+	err = a.call(a.nvmlDeviceGetSupportedClocksThrottleReasons, uintptr(device), &supportedClocksThrottleReasons)
 	return
 }
 
@@ -699,7 +1104,7 @@ func (a API) DeviceGetSupportedClocksThrottleReasons(device Device) (supportedCl
 func (a API) DeviceGetSupportedGraphicsClocks(device Device, memoryClockMHz uint32) ([]uint32, error) {
 	// Get array size
 	var count uint32
-	err := a.call(a.nvmlDeviceGetSupportedGraphicsClocks, uintptr(device), uintptr(memoryClockMHz), uintptr(unsafe.Pointer(&count)), 0)
+	err := a.call(a.nvmlDeviceGetSupportedGraphicsClocks, uintptr(device), uintptr(memoryClockMHz), &count, 0)
 	if err == nil {
 		return []uint32{}, nil
 	}
@@ -710,7 +1115,7 @@ func (a API) DeviceGetSupportedGraphicsClocks(device Device, memoryClockMHz uint
 
 	// Query data
 	list := make([]uint32, count)
-	if err := a.call(a.nvmlDeviceGetSupportedGraphicsClocks, uintptr(device), uintptr(memoryClockMHz), uintptr(unsafe.Pointer(&count)), uintptr(unsafe.Pointer(&list[0]))); err != nil {
+	if err := a.call(a.nvmlDeviceGetSupportedGraphicsClocks, uintptr(device), uintptr(memoryClockMHz), &count, list); err != nil {
 		return nil, err
 	}
 
@@ -723,7 +1128,7 @@ func (a API) DeviceGetSupportedMemoryClocks(device Device) ([]uint32, error) {
 	// Get array size
 	var count uint32
 
-	err := a.call(a.nvmlDeviceGetSupportedMemoryClocks, uintptr(device), uintptr(unsafe.Pointer(&count)), 0)
+	err := a.call(a.nvmlDeviceGetSupportedMemoryClocks, uintptr(device), &count, 0)
 	if err == nil {
 		return []uint32{}, nil
 	}
@@ -734,7 +1139,7 @@ func (a API) DeviceGetSupportedMemoryClocks(device Device) ([]uint32, error) {
 
 	// Query data
 	list := make([]uint32, count)
-	if err := a.call(a.nvmlDeviceGetSupportedMemoryClocks, uintptr(device), uintptr(unsafe.Pointer(&count)), uintptr(unsafe.Pointer(&list[0]))); err != nil {
+	if err := a.call(a.nvmlDeviceGetSupportedMemoryClocks, uintptr(device), &count, list); err != nil {
 		return nil, err
 	}
 
@@ -743,46 +1148,72 @@ func (a API) DeviceGetSupportedMemoryClocks(device Device) ([]uint32, error) {
 
 // DeviceGetTemperature retrieves the current temperature readings for the device, in degrees C.
 func (a API) DeviceGetTemperature(device Device, sensorType TemperatureSensor) (temp uint32, err error) {
-	err = a.call(a.nvmlDeviceGetTemperature, uintptr(device), uintptr(sensorType), uintptr(unsafe.Pointer(&temp)))
+	err = a.call(a.nvmlDeviceGetTemperature, uintptr(device), uintptr(sensorType), &temp)
 	return
 }
 
 // DeviceGetTemperatureThreshold retrieves the temperature threshold for the GPU with the specified threshold type in degrees C.
 func (a API) DeviceGetTemperatureThreshold(device Device, thresholdType TemperatureThreshold) (temp uint32, err error) {
-	err = a.call(a.nvmlDeviceGetTemperatureThreshold, uintptr(device), uintptr(thresholdType), uintptr(unsafe.Pointer(&temp)))
+	err = a.call(a.nvmlDeviceGetTemperatureThreshold, uintptr(device), uintptr(thresholdType), &temp)
 	return
 }
 
 // DeviceGetTopologyCommonAncestor retrieves the common ancestor for two devices. Supported on Linux only.
 func (a API) DeviceGetTopologyCommonAncestor(device1 Device, device2 Device) (pathInfo GPUTopologyLevel, err error) {
-	err = a.call(a.nvmlDeviceGetTopologyCommonAncestor, uintptr(device1), uintptr(device2), uintptr(unsafe.Pointer(&pathInfo)))
+	err = a.call(a.nvmlDeviceGetTopologyCommonAncestor, uintptr(device1), uintptr(device2), &pathInfo)
 	return
 }
 
-func (a API) DeviceGetTopologyNearestGpus() error {
-	return ErrNotImplemented
+// DeviceGetTopologyNearestGpus retrieves the set of GPUs that are nearest to a given device at a specific
+// interconnectivity level. Supported on Linux only.
+func (a API) DeviceGetTopologyNearestGpus(device Device, level GPUTopologyLevel) ([]Device, error) {
+	return a.deviceList(a.nvmlDeviceGetTopologyNearestGpus, uintptr(device), uintptr(level))
+}
+
+// deviceList queries a list of device handles with p, which takes a count and a device array after the given args.
+// The count is queried first, then the list is allocated and filled with a second call.
+func (a API) deviceList(p proc, args ...interface{}) ([]Device, error) {
+	// Get array size
+	var count uint32
+	err := a.call(p, append(args, &count, 0)...)
+	if err != nil && err != ErrInsufficientSize {
+		return nil, err
+	}
+
+	if count == 0 {
+		return []Device{}, nil
+	}
+
+	// Query data
+	list := make([]Device, count)
+	if err := a.call(p, append(args, &count, list)...); err != nil {
+		return nil, err
+	}
+
+	return list[:count], nil
 }
 
 // DeviceGetTotalECCErrors retrieves the total ECC error counts for the device.
 // Only applicable to devices with ECC. Requires NVML_INFOROM_ECC version 1.0 or higher. Requires ECC Mode to be enabled.
 // The total error count is the sum of errors across each of the separate memory systems, i.e. the total set of errors across the entire device.
 func (a API) DeviceGetTotalECCErrors(device Device, errorType MemoryErrorType, counterType ECCCounterType) (eccCount uint64, err error) {
-	err = a.call(a.nvmlDeviceGetTotalEccErrors, uintptr(device), uintptr(errorType), uintptr(counterType), uintptr(unsafe.Pointer(&eccCount)))
+	err = a.call(a.nvmlDeviceGetTotalEccErrors, uintptr(device), uintptr(errorType), uintptr(counterType), &eccCount)
 	return
 }
 
 // DeviceGetTotalEnergyConsumption retrieves total energy consumption for this GPU in millijoules (mJ)
 // since the driver was last reloaded.
 func (a API) DeviceGetTotalEnergyConsumption(device Device) (energy uint64, err error) {
-	err = a.call(a.nvmlDeviceGetTotalEnergyConsumption, uintptr(device), uintptr(unsafe.Pointer(&energy)))
+	err = a.call(a.nvmlDeviceGetTotalEnergyConsumption, uintptr(device), &energy)
 	return
 }
 
 // DeviceGetUUID retrieves the globally unique immutable UUID associated with this device,
 // as a 5 part hexadecimal string, that augments the immutable, board serial identifier.
+// MIG device handles are accepted too, in which case the UUID of the MIG device (prefixed with "MIG-") is returned.
 func (a API) DeviceGetUUID(device Device) (string, error) {
 	buffer := [deviceUUIDBufferSize]C.char{}
-	if err := a.call(a.nvmlDeviceGetUUID, uintptr(device), uintptr(unsafe.Pointer(&buffer[0])), deviceUUIDBufferSize); err != nil {
+	if err := a.call(a.nvmlDeviceGetUUID, uintptr(device), &buffer, deviceUUIDBufferSize); err != nil {
 		return "", err
 	}
 
@@ -793,14 +1224,14 @@ func (a API) DeviceGetUUID(device Device) (string, error) {
 func (a API) DeviceGetUtilizationRates(device Device) (u Utilization, err error) {
 	u.GPU = 0
 	u.Memory = 0
-	err = a.call(a.nvmlDeviceGetUtilizationRates, uintptr(device), uintptr(unsafe.Pointer(&u)))
+	err = a.call(a.nvmlDeviceGetUtilizationRates, uintptr(device), &u)
 	return
 }
 
 // DeviceGetVbiosVersion gets VBIOS version of the device. The VBIOS version may change from time to time.
 func (a API) DeviceGetVbiosVersion(device Device) (string, error) {
 	buffer := [deviceVBIOSVersionBufferSize]C.char{}
-	if err := a.call(a.nvmlDeviceGetVbiosVersion, uintptr(device), uintptr(unsafe.Pointer(&buffer[0])), deviceVBIOSVersionBufferSize); err != nil {
+	if err := a.call(a.nvmlDeviceGetVbiosVersion, uintptr(device), &buffer, deviceVBIOSVersionBufferSize); err != nil {
 		return "", err
 	}
 
@@ -813,7 +1244,7 @@ func (a API) DeviceGetVbiosVersion(device Device) (string, error) {
 // applications. The difference in violation times at two different reference times gives the indication of
 // GPU throttling event.
 func (a API) DeviceGetViolationStatus(device Device, policyType PerfPolicyType) (violTime ViolationTime, err error) {
-	err = a.call(a.nvmlDeviceGetViolationStatus, uintptr(device), uintptr(policyType), uintptr(unsafe.Pointer(&violTime)))
+	err = a.call(a.nvmlDeviceGetViolationStatus, uintptr(device), uintptr(policyType), &violTime)
 	return
 }
 
@@ -821,7 +1252,7 @@ func (a API) DeviceGetViolationStatus(device Device, policyType PerfPolicyType)
 func (a API) DeviceOnSameBoard(device1 Device, device2 Device) (bool, error) {
 	var onSameBoard int32 = 0
 
-	if err := a.call(a.nvmlDeviceOnSameBoard, uintptr(device1), uintptr(device2), uintptr(unsafe.Pointer(&onSameBoard))); err != nil {
+	if err := a.call(a.nvmlDeviceOnSameBoard, uintptr(device1), uintptr(device2), &onSameBoard); err != nil {
 		return false, err
 	}
 
@@ -0,0 +1,369 @@
+// +build linux,cgo
+
+package nvml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The device query entry points of the stub library, see newStubLibrary.
+func init() {
+	addStubFunctions(`
+typedef struct {
+	unsigned char bridgeCount;
+	struct { int type; unsigned int fwVersion; } bridgeChipInfo[128];
+} bridgeChipHierarchy;
+
+typedef struct {
+	unsigned long long timeStamp;
+	union { double dVal; unsigned int uiVal; unsigned long ulVal; unsigned long long ullVal; long long sllVal; } value;
+} sample;
+
+typedef struct {
+	unsigned int pid;
+	unsigned long long timeStamp;
+	unsigned int smUtil, memUtil, encUtil, decUtil;
+} processUtilizationSample;
+
+typedef struct {
+	unsigned int fieldId, scopeId;
+	long long timestamp, latencyUsec;
+	int valueType, nvmlReturn;
+	unsigned long long value;
+} fieldValue;
+`, map[string]string{
+		"nvmlDeviceGetP2PStatus": `int nvmlDeviceGetP2PStatus(void *d1, void *d2, int index, int *status) {
+			if (d1 == d2) return 2;
+			*status = index == 2 ? 5 : 0;
+			return 0;
+		}`,
+		"nvmlDeviceGetTopologyNearestGpus": `int nvmlDeviceGetTopologyNearestGpus(void *d, int level, unsigned int *count, void **devices) {
+			if (*count == 0) { *count = level >= 30 ? 2 : 0; return 0; }
+			devices[0] = (void *)(uintptr_t)0x1001;
+			devices[1] = (void *)(uintptr_t)0x1002;
+			*count = 2;
+			return 0;
+		}`,
+		"nvmlDeviceGetCpuAffinity": `int nvmlDeviceGetCpuAffinity(void *d, unsigned int cpuSetSize, unsigned long *cpuSet) {
+			for (unsigned int i = 0; i < cpuSetSize; i++) cpuSet[i] = i == 1 ? 0xff00000000000003UL : 0;
+			return 0;
+		}`,
+		"nvmlDeviceGetSamples": `int nvmlDeviceGetSamples(void *d, int type, unsigned long long last, int *valueType,
+			unsigned int *count, sample *samples) {
+			if (last >= 300) return 6;
+			*valueType = type == 0 ? 0 : 1;
+			if (samples == NULL) { *count = 3; return 0; }
+			*count = 0;
+			for (unsigned long long ts = 100; ts <= 300; ts += 100) {
+				if (ts <= last) continue;
+				samples[*count].timeStamp = ts;
+				if (type == 0) samples[*count].value.dVal = ts / 2.0; else samples[*count].value.uiVal = ts / 10;
+				(*count)++;
+			}
+			return 0;
+		}`,
+		"nvmlDeviceGetEncoderSessions": `int nvmlDeviceGetEncoderSessions(void *d, unsigned int *count, unsigned int *infos) {
+			if (*count == 0) { *count = 2; return 0; }
+			for (unsigned int i = 0; i < 2; i++) {
+				unsigned int *info = infos + i * 8;
+				info[0] = 10 + i; info[1] = 200 + i; info[2] = 0; info[3] = i;
+				info[4] = 1920; info[5] = 1080; info[6] = 60; info[7] = 1500;
+			}
+			*count = 2;
+			return 0;
+		}`,
+		"nvmlDeviceGetFBCStats": `int nvmlDeviceGetFBCStats(void *d, unsigned int *stats) {
+			stats[0] = 1; stats[1] = 30; stats[2] = 4000;
+			return 0;
+		}`,
+		"nvmlDeviceGetFBCSessions": `int nvmlDeviceGetFBCSessions(void *d, unsigned int *count, unsigned int *infos) {
+			if (*count == 0) { *count = 1; return 0; }
+			for (unsigned int i = 0; i < 12; i++) infos[i] = i + 1;
+			infos[4] = 4;
+			*count = 1;
+			return 0;
+		}`,
+		"nvmlDeviceGetBridgeChipInfo": `int nvmlDeviceGetBridgeChipInfo(void *d, bridgeChipHierarchy *hierarchy) {
+			hierarchy->bridgeCount = 2;
+			hierarchy->bridgeChipInfo[0].type = 0;
+			hierarchy->bridgeChipInfo[0].fwVersion = 0xaa;
+			hierarchy->bridgeChipInfo[1].type = 1;
+			hierarchy->bridgeChipInfo[1].fwVersion = 0xbb;
+			return 0;
+		}`,
+		"nvmlDeviceGetProcessUtilization": `int nvmlDeviceGetProcessUtilization(void *d, processUtilizationSample *samples,
+			unsigned int *count, unsigned long long last) {
+			if (last >= 200) return 6;
+			if (samples == NULL) { *count = 2; return 7; }
+			for (unsigned int i = 0; i < 2; i++) {
+				samples[i].pid = 100 + i;
+				samples[i].timeStamp = 200;
+				samples[i].smUtil = 50 + i;
+				samples[i].memUtil = 10;
+				samples[i].encUtil = 0;
+				samples[i].decUtil = 5;
+			}
+			*count = 2;
+			return 0;
+		}`,
+		"nvmlDeviceGetFieldValues": `int nvmlDeviceGetFieldValues(void *d, int count, fieldValue *values) {
+			for (int i = 0; i < count; i++) {
+				values[i].timestamp = 1600000000000000LL;
+				values[i].latencyUsec = 10;
+				switch (values[i].fieldId) {
+				case 1:
+					values[i].valueType = 1;
+					values[i].value = 1;
+					break;
+				case 83:
+					values[i].valueType = 3;
+					values[i].value = 1ULL << 40;
+					break;
+				case 138:
+					values[i].valueType = 1;
+					values[i].value = values[i].scopeId + 1;
+					break;
+				default:
+					values[i].nvmlReturn = 3;
+				}
+			}
+			return 0;
+		}`,
+		"nvmlDeviceGetRemappedRows": `int nvmlDeviceGetRemappedRows(void *d, unsigned int *corrRows, unsigned int *uncRows,
+			unsigned int *isPending, unsigned int *failureOccurred) {
+			*corrRows = 3;
+			*uncRows = 1;
+			*isPending = 1;
+			*failureOccurred = 0;
+			return 0;
+		}`,
+		"nvmlDeviceGetRetiredPages": `int nvmlDeviceGetRetiredPages(void *d, int cause, unsigned int *count,
+			unsigned long long *addresses) {
+			if (addresses == NULL) { *count = 1; return 7; }
+			addresses[0] = 0x3000 + cause;
+			*count = 1;
+			return 0;
+		}`,
+		"nvmlDeviceGetRetiredPages_v2": `int nvmlDeviceGetRetiredPages_v2(void *d, int cause, unsigned int *count,
+			unsigned long long *addresses, unsigned long long *timestamps) {
+			unsigned int n = cause == 0 ? 2 : 1;
+			if (addresses == NULL) { *count = n; return 7; }
+			for (unsigned int i = 0; i < n; i++) {
+				addresses[i] = 0x1000 * (cause + 1) + i;
+				timestamps[i] = 1600000000 + 100 * (n - i) + cause;
+			}
+			*count = n;
+			return 0;
+		}`,
+		"nvmlDeviceGetRowRemapperHistogram": `int nvmlDeviceGetRowRemapperHistogram(void *d, unsigned int *values) {
+			for (unsigned int i = 0; i < 5; i++) values[i] = 100 * (i + 1);
+			return 0;
+		}`,
+		"nvmlDeviceClearFieldValues": `int nvmlDeviceClearFieldValues(void *d, int count, fieldValue *values) {
+			return count > 0 ? 0 : 2;
+		}`,
+	})
+}
+
+func TestDeviceGetP2PStatusStub(t *testing.T) {
+	w := newStubAPI(t)
+
+	status, err := w.DeviceGetP2PStatus(Device(0x1000), Device(0x1001), P2PCapsIndexRead)
+	require.NoError(t, err)
+	require.Equal(t, P2PStatusOK, status)
+
+	status, err = w.DeviceGetP2PStatus(Device(0x1000), Device(0x1001), P2PCapsIndexNVLink)
+	require.NoError(t, err)
+	require.Equal(t, P2PStatusNotSupported, status)
+
+	_, err = w.DeviceGetP2PStatus(Device(0x1000), Device(0x1000), P2PCapsIndexRead)
+	require.Equal(t, ErrInvalidArgument, err)
+}
+
+func TestDeviceGetTopologyNearestGpusStub(t *testing.T) {
+	w := newStubAPI(t)
+
+	devices, err := w.DeviceGetTopologyNearestGpus(Device(0x1000), TopologySingle)
+	require.NoError(t, err)
+	require.Empty(t, devices)
+
+	devices, err = w.DeviceGetTopologyNearestGpus(Device(0x1000), TopologyHostbridge)
+	require.NoError(t, err)
+	require.Equal(t, []Device{0x1001, 0x1002}, devices)
+}
+
+func TestDeviceGetSamplesStub(t *testing.T) {
+	w := newStubAPI(t)
+
+	valueType, samples, err := w.DeviceGetSamples(Device(0x1000), SamplingTypeGPUUtilization, 100)
+	require.NoError(t, err)
+	require.Equal(t, ValueTypeUnsignedInt, valueType)
+	require.Equal(t, []Sample{{TimeStamp: 200, Value: uint32(20)}, {TimeStamp: 300, Value: uint32(30)}}, samples)
+
+	valueType, samples, err = w.DeviceGetSamples(Device(0x1000), SamplingTypeTotalPower, 0)
+	require.NoError(t, err)
+	require.Equal(t, ValueTypeDouble, valueType)
+	require.Len(t, samples, 3)
+	require.Equal(t, 50.0, samples[0].Value)
+
+	_, samples, err = w.DeviceGetSamples(Device(0x1000), SamplingTypeGPUUtilization, 300)
+	require.NoError(t, err)
+	require.Empty(t, samples)
+}
+
+func TestDeviceGetProcessUtilizationStub(t *testing.T) {
+	w := newStubAPI(t)
+
+	samples, err := w.DeviceGetProcessUtilization(Device(0x1000), 0)
+	require.NoError(t, err)
+	require.Equal(t, []ProcessUtilizationSample{
+		{PID: 100, TimeStamp: 200, SMUtil: 50, MemUtil: 10, DecUtil: 5},
+		{PID: 101, TimeStamp: 200, SMUtil: 51, MemUtil: 10, DecUtil: 5},
+	}, samples)
+
+	samples, err = w.DeviceGetProcessUtilization(Device(0x1000), 200)
+	require.NoError(t, err)
+	require.Empty(t, samples)
+}
+
+func TestEncoderSessionsStub(t *testing.T) {
+	w := newStubAPI(t)
+
+	sessions, err := w.DeviceGetEncoderSessions(Device(0x1000))
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	require.Equal(t, EncoderSessionInfo{
+		SessionID:      11,
+		PID:            201,
+		CodecType:      EncoderTypeQueryHEVC,
+		HResolution:    1920,
+		VResolution:    1080,
+		AverageFPS:     60,
+		AverageLatency: 1500,
+	}, sessions[1])
+
+	stats, err := w.DeviceGetFBCStats(Device(0x1000))
+	require.NoError(t, err)
+	require.Equal(t, FBCStats{SessionsCount: 1, AverageFPS: 30, AverageLatency: 4000}, stats)
+
+	fbc, err := w.DeviceGetFBCSessions(Device(0x1000))
+	require.NoError(t, err)
+	require.Equal(t, []FBCSessionInfo{{
+		SessionID:      1,
+		PID:            2,
+		VGPUInstance:   3,
+		DisplayOrdinal: 4,
+		SessionType:    FBCSessionTypeHWEnc,
+		SessionFlags:   FBCSessionFlags(6),
+		HMaxResolution: 7,
+		VMaxResolution: 8,
+		HResolution:    9,
+		VResolution:    10,
+		AverageFPS:     11,
+		AverageLatency: 12,
+	}}, fbc)
+}
+
+func TestDeviceGetBridgeChipInfoStub(t *testing.T) {
+	w := newStubAPI(t)
+
+	hierarchy, err := w.DeviceGetBridgeChipInfo(Device(0x1000))
+	require.NoError(t, err)
+	require.Equal(t, []BridgeChipInfo{
+		{Type: BridgeChipPLX, FirmwareVersion: 0xaa},
+		{Type: BridgeChipBRO4, FirmwareVersion: 0xbb},
+	}, hierarchy.BridgeChips)
+}
+
+func TestDeviceGetFieldValuesStub(t *testing.T) {
+	w := newStubAPI(t)
+
+	values, err := w.DeviceGetFieldValues(Device(0x1000), []FieldRequest{
+		{FieldID: FieldECCCurrent},
+		{FieldID: FieldTotalEnergyConsumption},
+		{FieldID: FieldNvLinkThroughputDataTX, ScopeID: 2},
+		{FieldID: FieldMemoryTemp},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []FieldValue{
+		{
+			FieldID:     FieldECCCurrent,
+			TimeStamp:   1600000000000000,
+			LatencyUsec: 10,
+			ValueType:   ValueTypeUnsignedInt,
+			Value:       uint32(1),
+		},
+		{
+			FieldID:     FieldTotalEnergyConsumption,
+			TimeStamp:   1600000000000000,
+			LatencyUsec: 10,
+			ValueType:   ValueTypeUnsignedLongLong,
+			Value:       uint64(1) << 40,
+		},
+		{
+			FieldID:     FieldNvLinkThroughputDataTX,
+			ScopeID:     2,
+			TimeStamp:   1600000000000000,
+			LatencyUsec: 10,
+			ValueType:   ValueTypeUnsignedInt,
+			Value:       uint32(3),
+		},
+		{
+			FieldID:     FieldMemoryTemp,
+			TimeStamp:   1600000000000000,
+			LatencyUsec: 10,
+			Err:         ErrNotSupported,
+		},
+	}, values)
+
+	values, err = w.DeviceGetFieldValues(Device(0x1000), nil)
+	require.NoError(t, err)
+	require.Empty(t, values)
+
+	err = w.DeviceClearFieldValues(Device(0x1000), []FieldRequest{{FieldID: FieldNvLinkThroughputDataTX, ScopeID: 2}})
+	require.NoError(t, err)
+}
+
+func TestDeviceGetRemappedRowsStub(t *testing.T) {
+	w := newStubAPI(t)
+
+	correctable, uncorrectable, isPending, failureOccurred, err := w.DeviceGetRemappedRows(Device(0x1000))
+	require.NoError(t, err)
+	require.Equal(t, uint32(3), correctable)
+	require.Equal(t, uint32(1), uncorrectable)
+	require.True(t, isPending)
+	require.False(t, failureOccurred)
+
+	histogram, err := w.DeviceGetRowRemapperHistogram(Device(0x1000))
+	require.NoError(t, err)
+	require.Equal(t, RowRemapperHistogram{Max: 100, High: 200, Partial: 300, Low: 400, None: 500}, histogram)
+}
+
+func TestDeviceGetAllRetiredPagesStub(t *testing.T) {
+	w := newStubAPI(t, "nvmlDeviceGetRetiredPages_v2")
+
+	require.Equal(t, 2, w.SymbolVersion("nvmlDeviceGetRetiredPages"))
+
+	pages, err := w.DeviceGetAllRetiredPages(Device(0x1000))
+	require.NoError(t, err)
+	require.Equal(t, []RetiredPage{
+		{Address: 0x1001, Time: 1600000100, Cause: PageRetirementCauseMultipleSingleBitECCErrors},
+		{Address: 0x2000, Time: 1600000101, Cause: PageRetirementCauseDoubleBitECCError},
+		{Address: 0x1000, Time: 1600000200, Cause: PageRetirementCauseMultipleSingleBitECCErrors},
+	}, pages)
+}
+
+func TestDeviceGetAllRetiredPagesV1Stub(t *testing.T) {
+	w := newStubAPI(t)
+
+	require.Equal(t, 1, w.SymbolVersion("nvmlDeviceGetRetiredPages"))
+
+	pages, err := w.DeviceGetAllRetiredPages(Device(0x1000))
+	require.NoError(t, err)
+	require.Equal(t, []RetiredPage{
+		{Address: 0x3000, Time: 0, Cause: PageRetirementCauseMultipleSingleBitECCErrors},
+		{Address: 0x3001, Time: 0, Cause: PageRetirementCauseDoubleBitECCError},
+	}, pages)
+}
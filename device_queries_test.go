@@ -2,6 +2,7 @@ package nvml
 
 import (
 	"log"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -71,7 +72,12 @@ func TestDeviceGetBrand(t *testing.T) {
 }
 
 func TestDeviceGetBridgeChipInfo(t *testing.T) {
-	t.Skip()
+	w, device := create(t)
+	defer w.Shutdown()
+
+	hierarchy, err := w.DeviceGetBridgeChipInfo(device)
+	require.NoError(t, err)
+	require.NotNil(t, hierarchy)
 }
 
 func TestDeviceGetClock(t *testing.T) {
@@ -228,7 +234,12 @@ func TestDeviceGetEncoderCapacity(t *testing.T) {
 }
 
 func TestDeviceGetEncoderSessions(t *testing.T) {
-	t.Skip()
+	w, device := create(t)
+	defer w.Shutdown()
+
+	sessions, err := w.DeviceGetEncoderSessions(device)
+	require.NoError(t, err)
+	require.NotNil(t, sessions)
 }
 
 func TestDeviceGetEncoderStats(t *testing.T) {
@@ -265,6 +276,23 @@ func TestDeviceGetFanSpeed(t *testing.T) {
 	require.True(t, speed > 0)
 }
 
+func TestDeviceGetFBCSessions(t *testing.T) {
+	w, device := create(t)
+	defer w.Shutdown()
+
+	sessions, err := w.DeviceGetFBCSessions(device)
+	require.NoError(t, err)
+	require.NotNil(t, sessions)
+}
+
+func TestDeviceGetFBCStats(t *testing.T) {
+	w, device := create(t)
+	defer w.Shutdown()
+
+	_, err := w.DeviceGetFBCStats(device)
+	require.NoError(t, err)
+}
+
 func TestDeviceGetGPUOperationMode(t *testing.T) {
 	w, device := create(t)
 	defer w.Shutdown()
@@ -440,7 +468,22 @@ func TestDeviceGetName(t *testing.T) {
 }
 
 func TestDeviceGetP2PStatus(t *testing.T) {
-	t.Skip()
+	w, device := create(t)
+	defer w.Shutdown()
+
+	count, err := w.DeviceGetCount()
+	require.NoError(t, err)
+
+	if count < 2 {
+		t.Skip("at least 2 devices required")
+	}
+
+	peer, err := w.DeviceGetHandleByIndex(1)
+	require.NoError(t, err)
+
+	status, err := w.DeviceGetP2PStatus(device, peer, P2PCapsIndexRead)
+	require.NoError(t, err)
+	require.True(t, status < P2PStatusUnknown)
 }
 
 func TestDeviceGetPciInfo(t *testing.T) {
@@ -539,6 +582,14 @@ func TestDeviceGetRetiredPages(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestDeviceGetAllRetiredPages(t *testing.T) {
+	w, device := create(t)
+	defer w.Shutdown()
+
+	_, err := w.DeviceGetAllRetiredPages(device)
+	require.NoError(t, err)
+}
+
 func TestDeviceGetRetiredPagesPendingStatus(t *testing.T) {
 	w, device := create(t)
 	defer w.Shutdown()
@@ -548,7 +599,23 @@ func TestDeviceGetRetiredPagesPendingStatus(t *testing.T) {
 }
 
 func TestDeviceGetSamples(t *testing.T) {
-	t.Skip()
+	w, device := create(t)
+	defer w.Shutdown()
+
+	valueType, samples, err := w.DeviceGetSamples(device, SamplingTypeGPUUtilization, 0)
+	require.NoError(t, err)
+
+	for _, sample := range samples {
+		require.Equal(t, ValueTypeUnsignedInt, valueType)
+		require.IsType(t, uint32(0), sample.Value)
+	}
+}
+
+func TestDecodeValue(t *testing.T) {
+	require.Equal(t, 1.5, decodeValue(ValueTypeDouble, math.Float64bits(1.5)))
+	require.Equal(t, uint32(42), decodeValue(ValueTypeUnsignedInt, 42))
+	require.Equal(t, uint64(1)<<40, decodeValue(ValueTypeUnsignedLongLong, 1<<40))
+	require.Equal(t, int64(-1), decodeValue(ValueTypeSignedLongLong, math.MaxUint64))
 }
 
 func TestDeviceGetSerial(t *testing.T) {
@@ -621,7 +688,12 @@ func TestDeviceGetTopologyCommonAncestor(t *testing.T) {
 }
 
 func TestDeviceGetTopologyNearestGpus(t *testing.T) {
-	t.Skip()
+	w, device := create(t)
+	defer w.Shutdown()
+
+	devices, err := w.DeviceGetTopologyNearestGpus(device, TopologySystem)
+	require.NoError(t, err)
+	require.NotContains(t, devices, device)
 }
 
 func TestDeviceGetTotalECCErrors(t *testing.T) {
@@ -695,3 +767,35 @@ func TestDeviceSetAutoBoostedClocksEnabled(t *testing.T) {
 	err := w.DeviceSetAutoBoostedClocksEnabled(device, false)
 	require.NoError(t, err)
 }
+
+func TestDeviceGetProcessUtilization(t *testing.T) {
+	w, device := create(t)
+	defer w.Shutdown()
+
+	_, err := w.DeviceGetProcessUtilization(device, 0)
+	require.NoError(t, err)
+}
+
+func TestDeviceGetFieldValues(t *testing.T) {
+	w, device := create(t)
+	defer w.Shutdown()
+
+	values, err := w.DeviceGetFieldValues(device, []FieldRequest{{FieldID: FieldECCCurrent}, {FieldID: FieldPCIeReplayCounter}})
+	require.NoError(t, err)
+	require.Len(t, values, 2)
+}
+
+func TestDeviceGetRemappedRows(t *testing.T) {
+	w, device := create(t)
+	defer w.Shutdown()
+
+	_, _, _, _, err := w.DeviceGetRemappedRows(device)
+	if err == ErrNotSupported {
+		t.Skip("row remapping is not supported")
+	}
+
+	require.NoError(t, err)
+
+	_, err = w.DeviceGetRowRemapperHistogram(device)
+	require.NoError(t, err)
+}
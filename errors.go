@@ -30,6 +30,8 @@ var (
 	ErrNoData               = errors.New("No data")
 	ErrVGPUECCNotSupported  = errors.New("The requested vgpu operation is not available on target device, because ECC is enabled")
 	ErrUnknown              = errors.New("An internal driver error occurred")
+
+	ErrInsufficientResources = errors.New("Ran out of critical resources, other than memory")
 )
 
 var errorCodeMappings = map[int]error{
@@ -56,6 +58,7 @@ var errorCodeMappings = map[int]error{
 	20:  ErrMemory,
 	21:  ErrNoData,
 	22:  ErrVGPUECCNotSupported,
+	23:  ErrInsufficientResources,
 	999: ErrUnknown,
 }
 
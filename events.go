@@ -0,0 +1,89 @@
+package nvml
+
+// #include <stdint.h>
+//
+// // Layout filled by nvmlEventSetWait and nvmlEventSetWait_v2 (gpuInstanceId and computeInstanceId are only set by v2)
+// typedef struct nvmlEventData_st {
+//     uintptr_t device; //!< Specific device where the event occurred
+//     unsigned long long eventType; //!< Information about what specific event occurred
+//     unsigned long long eventData; //!< Stores XID error for the device in the event of nvmlEventTypeXidCriticalError
+//     unsigned int gpuInstanceId; //!< If MIG is enabled and nvmlEventTypeXidCriticalError event is attributable to a GPU instance, stores a valid GPU instance ID
+//     unsigned int computeInstanceId; //!< If MIG is enabled and nvmlEventTypeXidCriticalError event is attributable to a compute instance, stores a valid compute instance ID
+// } nvmlEventData_t;
+import "C"
+
+import (
+	"math"
+	"time"
+)
+
+// EventSet is a set of events devices are registered for. It's created with EventSetCreate.
+type EventSet interface {
+	// Register starts recording of events on a specified device and adds them to the set.
+	// eventTypes is a bit mask of the events to record (see DeviceGetSupportedEventTypes).
+	// Events that happened before the registration are not recorded.
+	Register(device Device, eventTypes EventType) error
+	// Wait waits on events and delivers events. If some events are ready to be delivered at the time of the call,
+	// the function returns immediately. If there are no events ready to be delivered, the function sleeps
+	// until the event arrives but not longer than the specified timeout (ErrTimeout is returned in that case).
+	// This function in certain conditions can return before the specified timeout passes (e.g. when interrupt arrives).
+	// In case of Xid errors, the function returns the most recent Xid error type seen by the system.
+	// If there are multiple Xid errors generated before Wait is invoked then the last seen Xid error type is returned
+	// for all Xid error events.
+	Wait(timeout time.Duration) (EventData, error)
+	// Free releases the set.
+	Free() error
+}
+
+// eventSet is an EventSet backed by a nvmlEventSet_t.
+type eventSet struct {
+	api API
+	set uintptr
+}
+
+// EventSetCreate creates an empty set of events.
+func (a API) EventSetCreate() (EventSet, error) {
+	var set uintptr
+	if err := a.call(a.nvmlEventSetCreate, &set); err != nil {
+		return nil, err
+	}
+
+	return &eventSet{api: a, set: set}, nil
+}
+
+func (s *eventSet) Register(device Device, eventTypes EventType) error {
+	return s.api.call(s.api.nvmlDeviceRegisterEvents, uintptr(device), uintptr(eventTypes), s.set)
+}
+
+func (s *eventSet) Wait(timeout time.Duration) (EventData, error) {
+	data := C.nvmlEventData_t{}
+	if err := s.api.call(s.api.nvmlEventSetWait, s.set, &data, uintptr(timeout/time.Millisecond)); err != nil {
+		return EventData{}, err
+	}
+
+	event := EventData{
+		Device:            Device(data.device),
+		Type:              EventType(data.eventType),
+		XID:               uint64(data.eventData),
+		GPUInstanceID:     math.MaxUint32,
+		ComputeInstanceID: math.MaxUint32,
+	}
+
+	if s.api.versions["nvmlEventSetWait"] >= 2 {
+		event.GPUInstanceID = uint32(data.gpuInstanceId)
+		event.ComputeInstanceID = uint32(data.computeInstanceId)
+	}
+
+	return event, nil
+}
+
+func (s *eventSet) Free() error {
+	return s.api.call(s.api.nvmlEventSetFree, s.set)
+}
+
+// DeviceGetSupportedEventTypes returns information about events supported on device.
+// Events are not supported on Windows. So this function returns an empty mask in eventTypes on Windows.
+func (a API) DeviceGetSupportedEventTypes(device Device) (eventTypes EventType, err error) {
+	err = a.call(a.nvmlDeviceGetSupportedEventTypes, uintptr(device), &eventTypes)
+	return
+}
@@ -0,0 +1,89 @@
+// +build linux,cgo
+
+package nvml
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The event entry points of the stub library, see newStubLibrary.
+func init() {
+	addStubFunctions(`
+typedef struct {
+	void *device;
+	unsigned long long eventType, eventData;
+	unsigned int gpuInstanceId, computeInstanceId;
+} eventData;
+`, map[string]string{
+		"nvmlDeviceGetSupportedEventTypes": `int nvmlDeviceGetSupportedEventTypes(void *d, unsigned long long *types) {
+			*types = 0x8 | 0x2;
+			return 0;
+		}`,
+		"nvmlEventSetCreate":       "int nvmlEventSetCreate(void **set) { *set = (void *)(uintptr_t)0x2000; return 0; }",
+		"nvmlDeviceRegisterEvents": "int nvmlDeviceRegisterEvents(void *d, unsigned long long types, void *set) { return types & 0x1 ? 3 : 0; }",
+		"nvmlEventSetFree":         "int nvmlEventSetFree(void *set) { return 0; }",
+		"nvmlEventSetWait": `int nvmlEventSetWait(void *set, eventData *data, unsigned int timeout) {
+			if (timeout == 0) return 10;
+			data->device = (void *)(uintptr_t)0x1001;
+			data->eventType = 0x8;
+			data->eventData = 79;
+			return 0;
+		}`,
+		"nvmlEventSetWait_v2": `int nvmlEventSetWait_v2(void *set, eventData *data, unsigned int timeout) {
+			int ret = nvmlEventSetWait(set, data, timeout);
+			data->gpuInstanceId = 1;
+			data->computeInstanceId = 0;
+			return ret;
+		}`,
+	})
+}
+
+func TestEventSetStub(t *testing.T) {
+	tests := []struct {
+		name              string
+		exports           []string
+		gpuInstanceID     uint32
+		computeInstanceID uint32
+	}{
+		{name: "unversioned", gpuInstanceID: math.MaxUint32, computeInstanceID: math.MaxUint32},
+		{name: "v2", exports: []string{"nvmlEventSetWait_v2"}, gpuInstanceID: 1, computeInstanceID: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newStubAPI(t, tt.exports...)
+
+			types, err := w.DeviceGetSupportedEventTypes(Device(0x1001))
+			require.NoError(t, err)
+			require.Equal(t, EventTypeXIDCriticalError|EventTypeDoubleBitECCError, types)
+
+			set, err := w.EventSetCreate()
+			require.NoError(t, err)
+
+			err = set.Register(Device(0x1001), EventTypeSingleBitECCError)
+			require.Equal(t, ErrNotSupported, err)
+
+			err = set.Register(Device(0x1001), types)
+			require.NoError(t, err)
+
+			_, err = set.Wait(0)
+			require.Equal(t, ErrTimeout, err)
+
+			event, err := set.Wait(time.Second)
+			require.NoError(t, err)
+			require.Equal(t, EventData{
+				Device:            Device(0x1001),
+				Type:              EventTypeXIDCriticalError,
+				XID:               79,
+				GPUInstanceID:     tt.gpuInstanceID,
+				ComputeInstanceID: tt.computeInstanceID,
+			}, event)
+
+			require.NoError(t, set.Free())
+		})
+	}
+}
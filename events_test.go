@@ -0,0 +1,38 @@
+package nvml
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceGetSupportedEventTypes(t *testing.T) {
+	w, device := create(t)
+	defer w.Shutdown()
+
+	_, err := w.DeviceGetSupportedEventTypes(device)
+	require.NoError(t, err)
+}
+
+func TestEventSet(t *testing.T) {
+	w, device := create(t)
+	defer w.Shutdown()
+
+	types, err := w.DeviceGetSupportedEventTypes(device)
+	require.NoError(t, err)
+
+	set, err := w.EventSetCreate()
+	require.NoError(t, err)
+
+	err = set.Register(device, types)
+	require.NoError(t, err)
+
+	_, err = set.Wait(10 * time.Millisecond)
+	if err != nil {
+		require.Equal(t, ErrTimeout, err)
+	}
+
+	err = set.Free()
+	require.NoError(t, err)
+}
@@ -0,0 +1,122 @@
+package fake
+
+import (
+	"sort"
+
+	nvml "github.com/mxpv/nvml-go"
+)
+
+// lookupAccounting returns the device referred by handle if accounting is enabled, or the error method should report.
+// Must be called with mu held.
+func (f *Fake) lookupAccounting(method string, handle nvml.Device) (*Device, error) {
+	d, err := f.lookup(method, handle)
+	if err != nil {
+		return nil, err
+	}
+
+	if !d.AccountingMode {
+		return nil, nvml.ErrNotSupported
+	}
+
+	return d, nil
+}
+
+// DeviceGetAccountingMode returns AccountingMode.
+func (f *Fake) DeviceGetAccountingMode(device nvml.Device) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetAccountingMode", device)
+	if err != nil {
+		return false, err
+	}
+
+	return d.AccountingMode, nil
+}
+
+// DeviceGetAccountingStats returns the AccountingStats entry of the given PID, nvml.ErrNotFound when there's none.
+func (f *Fake) DeviceGetAccountingStats(device nvml.Device, pid uint32) (nvml.AccountingStats, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookupAccounting("DeviceGetAccountingStats", device)
+	if err != nil {
+		return nvml.AccountingStats{}, err
+	}
+
+	stats, ok := d.AccountingStats[pid]
+	if !ok {
+		return nvml.AccountingStats{}, nvml.ErrNotFound
+	}
+
+	return stats, nil
+}
+
+// DeviceGetAccountingPids returns the PIDs of AccountingStats, in ascending order.
+func (f *Fake) DeviceGetAccountingPids(device nvml.Device) ([]uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookupAccounting("DeviceGetAccountingPids", device)
+	if err != nil {
+		return nil, err
+	}
+
+	pids := make([]uint32, 0, len(d.AccountingStats))
+	for pid := range d.AccountingStats {
+		pids = append(pids, pid)
+	}
+
+	sort.Slice(pids, func(i, j int) bool { return pids[i] < pids[j] })
+	return pids, nil
+}
+
+// DeviceGetAccountingBufferSize returns AccountingBufferSize.
+func (f *Fake) DeviceGetAccountingBufferSize(device nvml.Device) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookupAccounting("DeviceGetAccountingBufferSize", device)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.AccountingBufferSize, nil
+}
+
+// DeviceSetAccountingMode sets AccountingMode. Disabling accounting clears AccountingStats.
+func (f *Fake) DeviceSetAccountingMode(device nvml.Device, mode bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceSetAccountingMode", device)
+	if err != nil {
+		return err
+	}
+
+	d.AccountingMode = mode
+	if !mode {
+		d.AccountingStats = map[uint32]nvml.AccountingStats{}
+	}
+
+	return nil
+}
+
+// DeviceClearAccountingPids removes the AccountingStats of the processes that are not running anymore.
+func (f *Fake) DeviceClearAccountingPids(device nvml.Device) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookupAccounting("DeviceClearAccountingPids", device)
+	if err != nil {
+		return err
+	}
+
+	for pid, stats := range d.AccountingStats {
+		if !stats.IsRunning {
+			delete(d.AccountingStats, pid)
+		}
+	}
+
+	return nil
+}
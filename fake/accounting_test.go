@@ -0,0 +1,53 @@
+package fake
+
+import (
+	"testing"
+
+	nvml "github.com/mxpv/nvml-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccounting(t *testing.T) {
+	f := create(t, 1)
+	defer f.Shutdown()
+
+	enabled, err := f.DeviceGetAccountingMode(Handle(0))
+	require.NoError(t, err)
+	require.False(t, enabled)
+
+	_, err = f.DeviceGetAccountingPids(Handle(0))
+	require.Equal(t, nvml.ErrNotSupported, err)
+
+	require.NoError(t, f.DeviceSetAccountingMode(Handle(0), true))
+
+	size, err := f.DeviceGetAccountingBufferSize(Handle(0))
+	require.NoError(t, err)
+	require.Equal(t, uint32(4000), size)
+
+	f.Devices[0].AccountingStats[300] = nvml.AccountingStats{GPUUtilization: 80, StartTime: 1000, IsRunning: true}
+	f.Devices[0].AccountingStats[200] = nvml.AccountingStats{GPUUtilization: 40, MaxMemoryUsage: 1 << 30, Time: 5000}
+
+	pids, err := f.DeviceGetAccountingPids(Handle(0))
+	require.NoError(t, err)
+	require.Equal(t, []uint32{200, 300}, pids)
+
+	stats, err := f.DeviceGetAccountingStats(Handle(0), 200)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5000), stats.Time)
+
+	_, err = f.DeviceGetAccountingStats(Handle(0), 100)
+	require.Equal(t, nvml.ErrNotFound, err)
+
+	require.NoError(t, f.DeviceClearAccountingPids(Handle(0)))
+
+	pids, err = f.DeviceGetAccountingPids(Handle(0))
+	require.NoError(t, err)
+	require.Equal(t, []uint32{300}, pids)
+
+	require.NoError(t, f.DeviceSetAccountingMode(Handle(0), false))
+	require.NoError(t, f.DeviceSetAccountingMode(Handle(0), true))
+
+	pids, err = f.DeviceGetAccountingPids(Handle(0))
+	require.NoError(t, err)
+	require.Empty(t, pids)
+}
@@ -0,0 +1,192 @@
+package fake
+
+import (
+	nvml "github.com/mxpv/nvml-go"
+)
+
+// DeviceClearECCErrorCounts resets the ECCErrors of the given counter type.
+func (f *Fake) DeviceClearECCErrorCounts(device nvml.Device, counterType nvml.ECCCounterType) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceClearECCErrorCounts", device)
+	if err != nil {
+		return err
+	}
+
+	if !d.ECCMode {
+		return nvml.ErrNotSupported
+	}
+
+	for i := range d.ECCErrors {
+		if d.ECCErrors[i].CounterType == counterType {
+			d.ECCErrors[i].Count = 0
+		}
+	}
+
+	return nil
+}
+
+// DeviceClearFieldValues resets the requested FieldValues entries to zero.
+// Fields missing from FieldValues are ignored.
+func (f *Fake) DeviceClearFieldValues(device nvml.Device, requests []nvml.FieldRequest) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceClearFieldValues", device)
+	if err != nil {
+		return err
+	}
+
+	for _, request := range requests {
+		switch d.FieldValues[request].(type) {
+		case float64:
+			d.FieldValues[request] = float64(0)
+		case uint32:
+			d.FieldValues[request] = uint32(0)
+		case uint64:
+			d.FieldValues[request] = uint64(0)
+		case int64:
+			d.FieldValues[request] = int64(0)
+		}
+	}
+
+	return nil
+}
+
+// DeviceResetGPULockedClocks succeeds unless an error is injected.
+func (f *Fake) DeviceResetGPULockedClocks(device nvml.Device) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, err := f.lookup("DeviceResetGPULockedClocks", device)
+	return err
+}
+
+// DeviceSetAPIRestriction updates APIRestrictions.
+func (f *Fake) DeviceSetAPIRestriction(device nvml.Device, apiType nvml.RestrictedAPI, isRestricted bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceSetAPIRestriction", device)
+	if err != nil {
+		return err
+	}
+
+	if d.APIRestrictions == nil {
+		d.APIRestrictions = map[nvml.RestrictedAPI]bool{}
+	}
+
+	d.APIRestrictions[apiType] = isRestricted
+	return nil
+}
+
+// DeviceSetApplicationsClocks updates ApplicationsClocks.
+// The clocks must be listed in SupportedMemoryClocks and SupportedGraphicsClocks, otherwise nvml.ErrInvalidArgument is returned.
+func (f *Fake) DeviceSetApplicationsClocks(device nvml.Device, memClockMHz, graphicsClockMHz uint32) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceSetApplicationsClocks", device)
+	if err != nil {
+		return err
+	}
+
+	if !contains(d.SupportedMemoryClocks, memClockMHz) || !contains(d.SupportedGraphicsClocks[memClockMHz], graphicsClockMHz) {
+		return nvml.ErrInvalidArgument
+	}
+
+	if d.ApplicationsClocks == nil {
+		d.ApplicationsClocks = map[nvml.ClockType]uint32{}
+	}
+
+	d.ApplicationsClocks[nvml.ClockMem] = memClockMHz
+	d.ApplicationsClocks[nvml.ClockGraphics] = graphicsClockMHz
+	return nil
+}
+
+// DeviceSetComputeMode sets ComputeMode.
+func (f *Fake) DeviceSetComputeMode(device nvml.Device, mode nvml.ComputeMode) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceSetComputeMode", device)
+	if err != nil {
+		return err
+	}
+
+	d.ComputeMode = mode
+	return nil
+}
+
+// DeviceSetDriverModel sets PendingDriverModel, the driver model changes after reboot.
+func (f *Fake) DeviceSetDriverModel(device nvml.Device, model nvml.DriverModel, flags uint32) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceSetDriverModel", device)
+	if err != nil {
+		return err
+	}
+
+	d.PendingDriverModel = model
+	return nil
+}
+
+// DeviceSetECCMode sets PendingECCMode, the ECC mode changes after reboot.
+func (f *Fake) DeviceSetECCMode(device nvml.Device, ecc bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceSetECCMode", device)
+	if err != nil {
+		return err
+	}
+
+	d.PendingECCMode = ecc
+	return nil
+}
+
+// DeviceSetGPUOperationMode sets PendingGPUOperationMode, the GOM changes after reboot.
+func (f *Fake) DeviceSetGPUOperationMode(device nvml.Device, mode nvml.GPUOperationMode) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceSetGPUOperationMode", device)
+	if err != nil {
+		return err
+	}
+
+	d.PendingGPUOperationMode = mode
+	return nil
+}
+
+// DeviceSetPowerManagementLimit sets PowerLimit and EnforcedPowerLimit.
+// The limit must be within MinPowerLimit and MaxPowerLimit, otherwise nvml.ErrInvalidArgument is returned.
+func (f *Fake) DeviceSetPowerManagementLimit(device nvml.Device, limit uint32) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceSetPowerManagementLimit", device)
+	if err != nil {
+		return err
+	}
+
+	if limit < d.MinPowerLimit || limit > d.MaxPowerLimit {
+		return nvml.ErrInvalidArgument
+	}
+
+	d.PowerLimit = limit
+	d.EnforcedPowerLimit = limit
+	return nil
+}
+
+func contains(list []uint32, value uint32) bool {
+	for _, x := range list {
+		if x == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,149 @@
+package fake
+
+import (
+	nvml "github.com/mxpv/nvml-go"
+)
+
+// The calls below are part of nvml.Interface on Linux only.
+// The fake implements them on every platform, so the same tests can run anywhere.
+
+// DeviceGetCPUAffinity returns the first cpuSetSize words of CPUAffinity, padded with zeroes.
+func (f *Fake) DeviceGetCPUAffinity(device nvml.Device, cpuSetSize uint32) ([]uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetCPUAffinity", device)
+	if err != nil {
+		return nil, err
+	}
+
+	cpuSet := make([]uint64, cpuSetSize)
+	copy(cpuSet, d.CPUAffinity)
+	return cpuSet, nil
+}
+
+// DeviceSetCpuAffinity succeeds unless an error is injected.
+func (f *Fake) DeviceSetCpuAffinity(device nvml.Device) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, err := f.lookup("DeviceSetCpuAffinity", device)
+	return err
+}
+
+// DeviceClearCpuAffinity succeeds unless an error is injected.
+func (f *Fake) DeviceClearCpuAffinity(device nvml.Device) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, err := f.lookup("DeviceClearCpuAffinity", device)
+	return err
+}
+
+// DeviceGetPersistenceMode returns PersistenceMode.
+func (f *Fake) DeviceGetPersistenceMode(device nvml.Device) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetPersistenceMode", device)
+	if err != nil {
+		return false, err
+	}
+
+	return d.PersistenceMode, nil
+}
+
+// DeviceSetPersistenceMode sets PersistenceMode.
+func (f *Fake) DeviceSetPersistenceMode(device nvml.Device, mode bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceSetPersistenceMode", device)
+	if err != nil {
+		return err
+	}
+
+	d.PersistenceMode = mode
+	return nil
+}
+
+// DeviceModifyDrainState sets Drained. Devices in persistence mode can't be drained.
+func (f *Fake) DeviceModifyDrainState(pci *nvml.PCIInfo, drain bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.findByPCI("DeviceModifyDrainState", pci)
+	if err != nil {
+		return err
+	}
+
+	if drain && d.PersistenceMode {
+		return nvml.ErrInUse
+	}
+
+	d.Drained = drain
+	return nil
+}
+
+// DeviceQueryDrainState returns Drained.
+func (f *Fake) DeviceQueryDrainState(pci *nvml.PCIInfo) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.findByPCI("DeviceQueryDrainState", pci)
+	if err != nil {
+		return false, err
+	}
+
+	return d.Drained, nil
+}
+
+// DeviceRemoveGPU sets Removed, unless the device runs processes or is in persistence mode.
+// Removed devices aren't found by the DeviceGetHandleBy calls and their handles are invalid.
+func (f *Fake) DeviceRemoveGPU(pci *nvml.PCIInfo) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.findByPCI("DeviceRemoveGPU", pci)
+	if err != nil {
+		return err
+	}
+
+	if d.Removed {
+		return nvml.ErrNotFound
+	}
+
+	if len(d.ComputeProcesses) > 0 || len(d.GraphicsProcesses) > 0 || d.PersistenceMode {
+		return nvml.ErrInUse
+	}
+
+	d.Removed = true
+	return nil
+}
+
+// DeviceDiscoverGPUs clears Removed of the device at the domain, bus and device of pci, or of all the devices when
+// they're all zeroes. Rediscovered devices keep their handles.
+func (f *Fake) DeviceDiscoverGPUs(pci *nvml.PCIInfo) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if pci.Domain == 0 && pci.Bus == 0 && pci.Device == 0 {
+		if err := f.check("DeviceDiscoverGPUs"); err != nil {
+			return err
+		}
+
+		for _, d := range f.Devices {
+			d.Removed = false
+		}
+
+		return nil
+	}
+
+	d, err := f.findByPCI("DeviceDiscoverGPUs", pci)
+	if err != nil {
+		return err
+	}
+
+	d.Removed = false
+	return nil
+}
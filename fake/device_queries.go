@@ -0,0 +1,1414 @@
+package fake
+
+import (
+	"sort"
+
+	nvml "github.com/mxpv/nvml-go"
+)
+
+func clockValue(clocks map[nvml.ClockType]uint32, clockType nvml.ClockType) (uint32, error) {
+	value, ok := clocks[clockType]
+	if !ok {
+		return 0, nvml.ErrNotSupported
+	}
+
+	return value, nil
+}
+
+func copyProcesses(list []nvml.ProcessInfo) []nvml.ProcessInfo {
+	return append([]nvml.ProcessInfo{}, list...)
+}
+
+// DeviceGetAPIRestriction returns the restriction set in APIRestrictions.
+func (f *Fake) DeviceGetAPIRestriction(device nvml.Device, apiType nvml.RestrictedAPI) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetAPIRestriction", device)
+	if err != nil {
+		return false, err
+	}
+
+	return d.APIRestrictions[apiType], nil
+}
+
+// DeviceGetApplicationsClock returns the clock set in ApplicationsClocks.
+func (f *Fake) DeviceGetApplicationsClock(device nvml.Device, clockType nvml.ClockType) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetApplicationsClock", device)
+	if err != nil {
+		return 0, err
+	}
+
+	return clockValue(d.ApplicationsClocks, clockType)
+}
+
+// DeviceGetAutoBoostedClocksEnabled returns AutoBoostedClocks and DefaultAutoBoostedClocks.
+func (f *Fake) DeviceGetAutoBoostedClocksEnabled(device nvml.Device) (bool, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetAutoBoostedClocksEnabled", device)
+	if err != nil {
+		return false, false, err
+	}
+
+	return d.AutoBoostedClocks, d.DefaultAutoBoostedClocks, nil
+}
+
+// DeviceGetBAR1MemoryInfo returns BAR1Memory.
+func (f *Fake) DeviceGetBAR1MemoryInfo(device nvml.Device) (nvml.BAR1Memory, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetBAR1MemoryInfo", device)
+	if err != nil {
+		return nvml.BAR1Memory{}, err
+	}
+
+	return d.BAR1Memory, nil
+}
+
+// DeviceGetBoardID returns BoardID.
+func (f *Fake) DeviceGetBoardID(device nvml.Device) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetBoardID", device)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.BoardID, nil
+}
+
+// DeviceGetBoardPartNumber returns BoardPartNumber.
+func (f *Fake) DeviceGetBoardPartNumber(device nvml.Device) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetBoardPartNumber", device)
+	if err != nil {
+		return "", err
+	}
+
+	return d.BoardPartNumber, nil
+}
+
+// DeviceGetBrand returns Brand.
+func (f *Fake) DeviceGetBrand(device nvml.Device) (nvml.BrandType, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetBrand", device)
+	if err != nil {
+		return nvml.BrandUnknown, err
+	}
+
+	return d.Brand, nil
+}
+
+// DeviceGetBridgeChipInfo returns a copy of BridgeChips.
+func (f *Fake) DeviceGetBridgeChipInfo(device nvml.Device) (*nvml.BridgeChipHierarchy, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetBridgeChipInfo", device)
+	if err != nil {
+		return nil, err
+	}
+
+	return &nvml.BridgeChipHierarchy{BridgeChips: append([]nvml.BridgeChipInfo{}, d.BridgeChips...)}, nil
+}
+
+// DeviceGetClock returns the clock matching clockID: Clocks, ApplicationsClocks, DefaultApplicationsClocks or
+// MaxCustomerBoostClocks.
+func (f *Fake) DeviceGetClock(device nvml.Device, clockType nvml.ClockType, clockID nvml.ClockID) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetClock", device)
+	if err != nil {
+		return 0, err
+	}
+
+	switch clockID {
+	case nvml.ClockIDCurrent:
+		return clockValue(d.Clocks, clockType)
+	case nvml.ClockIDAppClockTarget:
+		return clockValue(d.ApplicationsClocks, clockType)
+	case nvml.ClockIDAppClockDefault:
+		return clockValue(d.DefaultApplicationsClocks, clockType)
+	case nvml.ClockIDCustomerBoostMax:
+		return clockValue(d.MaxCustomerBoostClocks, clockType)
+	default:
+		return 0, nvml.ErrInvalidArgument
+	}
+}
+
+// DeviceGetClockInfo returns the clock set in Clocks.
+func (f *Fake) DeviceGetClockInfo(device nvml.Device, clockType nvml.ClockType) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetClockInfo", device)
+	if err != nil {
+		return 0, err
+	}
+
+	return clockValue(d.Clocks, clockType)
+}
+
+// DeviceGetComputeMode returns ComputeMode.
+func (f *Fake) DeviceGetComputeMode(device nvml.Device) (nvml.ComputeMode, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetComputeMode", device)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.ComputeMode, nil
+}
+
+// DeviceGetComputeRunningProcesses returns a copy of ComputeProcesses.
+func (f *Fake) DeviceGetComputeRunningProcesses(device nvml.Device) ([]nvml.ProcessInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetComputeRunningProcesses", device)
+	if err != nil {
+		return nil, err
+	}
+
+	return copyProcesses(d.ComputeProcesses), nil
+}
+
+// DeviceGetCount returns the number of simulated devices.
+func (f *Fake) DeviceGetCount() (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check("DeviceGetCount"); err != nil {
+		return 0, err
+	}
+
+	return uint32(len(f.Devices)), nil
+}
+
+// DeviceGetCudaComputeCapability returns ComputeCapabilityMajor and ComputeCapabilityMinor.
+func (f *Fake) DeviceGetCudaComputeCapability(device nvml.Device) (int32, int32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetCudaComputeCapability", device)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return d.ComputeCapabilityMajor, d.ComputeCapabilityMinor, nil
+}
+
+// DeviceGetCurrPcieLinkGeneration returns PCIeLinkGeneration.
+func (f *Fake) DeviceGetCurrPcieLinkGeneration(device nvml.Device) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetCurrPcieLinkGeneration", device)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.PCIeLinkGeneration, nil
+}
+
+// DeviceGetCurrPcieLinkWidth returns PCIeLinkWidth.
+func (f *Fake) DeviceGetCurrPcieLinkWidth(device nvml.Device) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetCurrPcieLinkWidth", device)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.PCIeLinkWidth, nil
+}
+
+// DeviceGetCurrentClocksThrottleReasons returns ClocksThrottleReasons.
+func (f *Fake) DeviceGetCurrentClocksThrottleReasons(device nvml.Device) (nvml.ClocksThrottleReason, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetCurrentClocksThrottleReasons", device)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.ClocksThrottleReasons, nil
+}
+
+// DeviceGetDecoderUtilization returns DecoderUtilization and SamplingPeriodUs.
+func (f *Fake) DeviceGetDecoderUtilization(device nvml.Device) (uint32, uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetDecoderUtilization", device)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return d.DecoderUtilization, d.SamplingPeriodUs, nil
+}
+
+// DeviceGetDefaultApplicationsClock returns the clock set in DefaultApplicationsClocks.
+func (f *Fake) DeviceGetDefaultApplicationsClock(device nvml.Device, clockType nvml.ClockType) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetDefaultApplicationsClock", device)
+	if err != nil {
+		return 0, err
+	}
+
+	return clockValue(d.DefaultApplicationsClocks, clockType)
+}
+
+// DeviceGetDetailedECCErrors aggregates ECCErrors by memory location.
+func (f *Fake) DeviceGetDetailedECCErrors(device nvml.Device, errorType nvml.MemoryErrorType, counterType nvml.ECCCounterType) (*nvml.ECCErrorCounts, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetDetailedECCErrors", device)
+	if err != nil {
+		return nil, err
+	}
+
+	if !d.ECCMode {
+		return nil, nvml.ErrNotSupported
+	}
+
+	counts := &nvml.ECCErrorCounts{}
+	for _, e := range d.ECCErrors {
+		if e.ErrorType != errorType || e.CounterType != counterType {
+			continue
+		}
+
+		switch e.Location {
+		case nvml.MemoryLocationL1Cache:
+			counts.L1Cache += e.Count
+		case nvml.MemoryLocationL2Cache:
+			counts.L2Cache += e.Count
+		case nvml.MemoryLocationDeviceMemory:
+			counts.DeviceMemory += e.Count
+		case nvml.MemoryLocationRegisterFile:
+			counts.RegisterFile += e.Count
+		}
+	}
+
+	return counts, nil
+}
+
+// DeviceGetDisplayActive returns DisplayActive.
+func (f *Fake) DeviceGetDisplayActive(device nvml.Device) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetDisplayActive", device)
+	if err != nil {
+		return false, err
+	}
+
+	return d.DisplayActive, nil
+}
+
+// DeviceGetDisplayMode returns DisplayMode.
+func (f *Fake) DeviceGetDisplayMode(device nvml.Device) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetDisplayMode", device)
+	if err != nil {
+		return false, err
+	}
+
+	return d.DisplayMode, nil
+}
+
+// DeviceGetDriverModel returns DriverModel and PendingDriverModel.
+func (f *Fake) DeviceGetDriverModel(device nvml.Device) (nvml.DriverModel, nvml.DriverModel, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetDriverModel", device)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return d.DriverModel, d.PendingDriverModel, nil
+}
+
+// DeviceGetECCMode returns ECCMode and PendingECCMode.
+func (f *Fake) DeviceGetECCMode(device nvml.Device) (bool, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetECCMode", device)
+	if err != nil {
+		return false, false, err
+	}
+
+	return d.ECCMode, d.PendingECCMode, nil
+}
+
+// DeviceGetEncoderCapacity returns the capacity set in EncoderCapacity.
+func (f *Fake) DeviceGetEncoderCapacity(device nvml.Device, encoderQueryType nvml.EncoderType) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetEncoderCapacity", device)
+	if err != nil {
+		return 0, err
+	}
+
+	capacity, ok := d.EncoderCapacity[encoderQueryType]
+	if !ok {
+		return 0, nvml.ErrNotSupported
+	}
+
+	return capacity, nil
+}
+
+// DeviceGetEncoderSessions returns a copy of EncoderSessions.
+func (f *Fake) DeviceGetEncoderSessions(device nvml.Device) ([]nvml.EncoderSessionInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetEncoderSessions", device)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]nvml.EncoderSessionInfo{}, d.EncoderSessions...), nil
+}
+
+// DeviceGetEncoderStats returns EncoderStats.
+func (f *Fake) DeviceGetEncoderStats(device nvml.Device) (uint32, uint32, uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetEncoderStats", device)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	return d.EncoderStats.SessionCount, d.EncoderStats.AverageFPS, d.EncoderStats.AverageLatency, nil
+}
+
+// DeviceGetEncoderUtilization returns EncoderUtilization and SamplingPeriodUs.
+func (f *Fake) DeviceGetEncoderUtilization(device nvml.Device) (uint32, uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetEncoderUtilization", device)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return d.EncoderUtilization, d.SamplingPeriodUs, nil
+}
+
+// DeviceGetEnforcedPowerLimit returns EnforcedPowerLimit.
+func (f *Fake) DeviceGetEnforcedPowerLimit(device nvml.Device) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetEnforcedPowerLimit", device)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.EnforcedPowerLimit, nil
+}
+
+// DeviceGetFanSpeed returns FanSpeed.
+func (f *Fake) DeviceGetFanSpeed(device nvml.Device) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetFanSpeed", device)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.FanSpeed, nil
+}
+
+// DeviceGetFieldValues returns the values of the requested fields from FieldValues.
+// Fields missing from FieldValues are reported with nvml.ErrNotSupported.
+func (f *Fake) DeviceGetFieldValues(device nvml.Device, requests []nvml.FieldRequest) ([]nvml.FieldValue, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetFieldValues", device)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]nvml.FieldValue, len(requests))
+	for i, request := range requests {
+		values[i] = nvml.FieldValue{FieldID: request.FieldID, ScopeID: request.ScopeID}
+
+		value, ok := d.FieldValues[request]
+		if !ok {
+			values[i].Err = nvml.ErrNotSupported
+			continue
+		}
+
+		values[i].ValueType = valueTypeOf(value)
+		values[i].Value = value
+	}
+
+	return values, nil
+}
+
+// DeviceGetFBCSessions returns a copy of FBCSessions.
+func (f *Fake) DeviceGetFBCSessions(device nvml.Device) ([]nvml.FBCSessionInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetFBCSessions", device)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]nvml.FBCSessionInfo{}, d.FBCSessions...), nil
+}
+
+// DeviceGetFBCStats returns FBCStats.
+func (f *Fake) DeviceGetFBCStats(device nvml.Device) (nvml.FBCStats, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetFBCStats", device)
+	if err != nil {
+		return nvml.FBCStats{}, err
+	}
+
+	return d.FBCStats, nil
+}
+
+// DeviceGetGPUOperationMode returns GPUOperationMode and PendingGPUOperationMode.
+func (f *Fake) DeviceGetGPUOperationMode(device nvml.Device) (nvml.GPUOperationMode, nvml.GPUOperationMode, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetGPUOperationMode", device)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return d.GPUOperationMode, d.PendingGPUOperationMode, nil
+}
+
+// DeviceGetGraphicsRunningProcesses returns a copy of GraphicsProcesses.
+func (f *Fake) DeviceGetGraphicsRunningProcesses(device nvml.Device) ([]nvml.ProcessInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetGraphicsRunningProcesses", device)
+	if err != nil {
+		return nil, err
+	}
+
+	return copyProcesses(d.GraphicsProcesses), nil
+}
+
+// DeviceGetHandleByIndex returns the handle of the device at index in Devices.
+func (f *Fake) DeviceGetHandleByIndex(index uint32) (nvml.Device, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check("DeviceGetHandleByIndex"); err != nil {
+		return 0, err
+	}
+
+	if int(index) >= len(f.Devices) {
+		return 0, nvml.ErrInvalidArgument
+	}
+
+	return Handle(int(index)), nil
+}
+
+// DeviceGetHandleByPCIBusID returns the handle of the device whose PCI.BusID or PCI.BusIDLegacy matches pciBusID.
+func (f *Fake) DeviceGetHandleByPCIBusID(pciBusID string) (nvml.Device, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.find("DeviceGetHandleByPCIBusID", func(d *Device) bool {
+		return equalBusID(d.PCI.BusID, pciBusID) || equalBusID(d.PCI.BusIDLegacy, pciBusID)
+	})
+}
+
+// DeviceGetHandleBySerial returns the handle of the device with the given Serial.
+func (f *Fake) DeviceGetHandleBySerial(serial string) (nvml.Device, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.find("DeviceGetHandleBySerial", func(d *Device) bool {
+		return d.Serial == serial
+	})
+}
+
+// DeviceGetHandleByUUID returns the handle of the device with the given UUID.
+func (f *Fake) DeviceGetHandleByUUID(uuid string) (nvml.Device, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.find("DeviceGetHandleByUUID", func(d *Device) bool {
+		return d.UUID == uuid
+	})
+}
+
+// DeviceGetIndex returns the index of the device in Devices. MIG device handles are rejected.
+func (f *Fake) DeviceGetIndex(device nvml.Device) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.lookup("DeviceGetIndex", device); err != nil {
+		return 0, err
+	}
+
+	if int(device) > len(f.Devices) {
+		// MIG devices have no index
+		return 0, nvml.ErrInvalidArgument
+	}
+
+	return uint32(device) - 1, nil
+}
+
+// DeviceGetInforomConfigurationChecksum returns InfoROMChecksum.
+func (f *Fake) DeviceGetInforomConfigurationChecksum(device nvml.Device) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetInforomConfigurationChecksum", device)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.InfoROMChecksum, nil
+}
+
+// DeviceGetInfoROMImageVersion returns InfoROMImageVersion.
+func (f *Fake) DeviceGetInfoROMImageVersion(device nvml.Device) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetInfoROMImageVersion", device)
+	if err != nil {
+		return "", err
+	}
+
+	return d.InfoROMImageVersion, nil
+}
+
+// DeviceGetInfoROMVersion returns the version set in InfoROMVersions.
+func (f *Fake) DeviceGetInfoROMVersion(device nvml.Device, object nvml.InfoROMObject) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetInfoROMVersion", device)
+	if err != nil {
+		return "", err
+	}
+
+	version, ok := d.InfoROMVersions[object]
+	if !ok {
+		return "", nvml.ErrNotSupported
+	}
+
+	return version, nil
+}
+
+// DeviceGetMaxClockInfo returns the clock set in MaxClocks.
+func (f *Fake) DeviceGetMaxClockInfo(device nvml.Device, clockType nvml.ClockType) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetMaxClockInfo", device)
+	if err != nil {
+		return 0, err
+	}
+
+	return clockValue(d.MaxClocks, clockType)
+}
+
+// DeviceGetMaxCustomerBoostClock returns the clock set in MaxCustomerBoostClocks.
+func (f *Fake) DeviceGetMaxCustomerBoostClock(device nvml.Device, clockType nvml.ClockType) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetMaxCustomerBoostClock", device)
+	if err != nil {
+		return 0, err
+	}
+
+	return clockValue(d.MaxCustomerBoostClocks, clockType)
+}
+
+// DeviceGetMaxPcieLinkGeneration returns MaxPCIeLinkGeneration.
+func (f *Fake) DeviceGetMaxPcieLinkGeneration(device nvml.Device) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetMaxPcieLinkGeneration", device)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.MaxPCIeLinkGeneration, nil
+}
+
+// DeviceGetMaxPcieLinkWidth returns MaxPCIeLinkWidth.
+func (f *Fake) DeviceGetMaxPcieLinkWidth(device nvml.Device) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetMaxPcieLinkWidth", device)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.MaxPCIeLinkWidth, nil
+}
+
+// DeviceGetMemoryErrorCounter sums the matching ECCErrors.
+func (f *Fake) DeviceGetMemoryErrorCounter(device nvml.Device, errorType nvml.MemoryErrorType, counterType nvml.ECCCounterType, locationType nvml.MemoryLocation) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetMemoryErrorCounter", device)
+	if err != nil {
+		return 0, err
+	}
+
+	if !d.ECCMode {
+		return 0, nvml.ErrNotSupported
+	}
+
+	var count uint64
+	for _, e := range d.ECCErrors {
+		if e.ErrorType == errorType && e.CounterType == counterType && e.Location == locationType {
+			count += e.Count
+		}
+	}
+
+	return count, nil
+}
+
+// DeviceGetMemoryInfo returns Memory.
+func (f *Fake) DeviceGetMemoryInfo(device nvml.Device) (nvml.Memory, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetMemoryInfo", device)
+	if err != nil {
+		return nvml.Memory{}, err
+	}
+
+	return d.Memory, nil
+}
+
+// DeviceGetMinorNumber returns MinorNumber.
+func (f *Fake) DeviceGetMinorNumber(device nvml.Device) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetMinorNumber", device)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.MinorNumber, nil
+}
+
+// DeviceGetMultiGpuBoard returns MultiGPUBoard.
+func (f *Fake) DeviceGetMultiGpuBoard(device nvml.Device) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetMultiGpuBoard", device)
+	if err != nil {
+		return false, err
+	}
+
+	return d.MultiGPUBoard, nil
+}
+
+// DeviceGetName returns Name.
+func (f *Fake) DeviceGetName(device nvml.Device) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetName", device)
+	if err != nil {
+		return "", err
+	}
+
+	return d.Name, nil
+}
+
+// DeviceGetP2PStatus returns the status set in P2P for the given capability.
+// When unset, devices can talk to themselves and every pair of devices is reported as nvml.P2PStatusNotSupported.
+func (f *Fake) DeviceGetP2PStatus(device1 nvml.Device, device2 nvml.Device, p2pIndex nvml.P2PCapsIndex) (nvml.P2PStatus, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.lookup("DeviceGetP2PStatus", device1); err != nil {
+		return nvml.P2PStatusUnknown, err
+	}
+
+	if _, err := f.lookup("DeviceGetP2PStatus", device2); err != nil {
+		return nvml.P2PStatusUnknown, err
+	}
+
+	if p2pIndex < nvml.P2PCapsIndexRead || p2pIndex >= nvml.P2PCapsIndexUnknown {
+		return nvml.P2PStatusUnknown, nvml.ErrInvalidArgument
+	}
+
+	i, j := int(device1)-1, int(device2)-1
+	if matrix := f.P2P[p2pIndex]; i < len(matrix) && j < len(matrix[i]) {
+		return matrix[i][j], nil
+	}
+
+	if i == j {
+		return nvml.P2PStatusOK, nil
+	}
+
+	return nvml.P2PStatusNotSupported, nil
+}
+
+// DeviceGetPCIInfo returns a copy of PCI.
+func (f *Fake) DeviceGetPCIInfo(device nvml.Device) (*nvml.PCIInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetPCIInfo", device)
+	if err != nil {
+		return nil, err
+	}
+
+	info := d.PCI
+	return &info, nil
+}
+
+// DeviceGetPcieReplayCounter returns PCIeReplayCounter.
+func (f *Fake) DeviceGetPcieReplayCounter(device nvml.Device) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetPcieReplayCounter", device)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.PCIeReplayCounter, nil
+}
+
+// DeviceGetPCIeThroughput returns the value set in PCIeThroughput.
+func (f *Fake) DeviceGetPCIeThroughput(device nvml.Device, counter nvml.PCIeUtilCounter) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetPCIeThroughput", device)
+	if err != nil {
+		return 0, err
+	}
+
+	value, ok := d.PCIeThroughput[counter]
+	if !ok {
+		return 0, nvml.ErrNotSupported
+	}
+
+	return value, nil
+}
+
+// DeviceGetPerformanceState returns PerformanceState.
+func (f *Fake) DeviceGetPerformanceState(device nvml.Device) (nvml.PState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetPerformanceState", device)
+	if err != nil {
+		return nvml.PStateUnknown, err
+	}
+
+	return d.PerformanceState, nil
+}
+
+// DeviceGetPowerManagementDefaultLimit returns DefaultPowerLimit.
+func (f *Fake) DeviceGetPowerManagementDefaultLimit(device nvml.Device) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetPowerManagementDefaultLimit", device)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.DefaultPowerLimit, nil
+}
+
+// DeviceGetPowerManagementLimit returns PowerLimit.
+func (f *Fake) DeviceGetPowerManagementLimit(device nvml.Device) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetPowerManagementLimit", device)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.PowerLimit, nil
+}
+
+// DeviceGetPowerManagementLimitConstraints returns MinPowerLimit and MaxPowerLimit.
+func (f *Fake) DeviceGetPowerManagementLimitConstraints(device nvml.Device) (uint32, uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetPowerManagementLimitConstraints", device)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return d.MinPowerLimit, d.MaxPowerLimit, nil
+}
+
+// DeviceGetPowerManagementMode returns PowerManagementMode.
+func (f *Fake) DeviceGetPowerManagementMode(device nvml.Device) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetPowerManagementMode", device)
+	if err != nil {
+		return false, err
+	}
+
+	return d.PowerManagementMode, nil
+}
+
+// DeviceGetPowerState returns PerformanceState.
+func (f *Fake) DeviceGetPowerState(device nvml.Device) (nvml.PState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetPowerState", device)
+	if err != nil {
+		return nvml.PStateUnknown, err
+	}
+
+	return d.PerformanceState, nil
+}
+
+// DeviceGetPowerUsage returns PowerUsage.
+func (f *Fake) DeviceGetPowerUsage(device nvml.Device) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetPowerUsage", device)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.PowerUsage, nil
+}
+
+// DeviceGetProcessUtilization returns the samples in ProcessUtilization more recent than lastSeenTimeStamp.
+func (f *Fake) DeviceGetProcessUtilization(device nvml.Device, lastSeenTimeStamp uint64) ([]nvml.ProcessUtilizationSample, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetProcessUtilization", device)
+	if err != nil {
+		return nil, err
+	}
+
+	list := []nvml.ProcessUtilizationSample{}
+	for _, sample := range d.ProcessUtilization {
+		if sample.TimeStamp > lastSeenTimeStamp {
+			list = append(list, sample)
+		}
+	}
+
+	return list, nil
+}
+
+// DeviceGetRemappedRows returns the counters set in RemappedRows.
+// Devices without RemappedRows don't support row remapping.
+func (f *Fake) DeviceGetRemappedRows(device nvml.Device) (uint32, uint32, bool, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetRemappedRows", device)
+	if err != nil {
+		return 0, 0, false, false, err
+	}
+
+	if d.RemappedRows == nil {
+		return 0, 0, false, false, nvml.ErrNotSupported
+	}
+
+	r := d.RemappedRows
+	return r.Correctable, r.Uncorrectable, r.Pending, r.FailureOccurred, nil
+}
+
+// DeviceGetRetiredPages returns a copy of the pages set in RetiredPages.
+// Devices without RetiredPages don't support page retirement.
+func (f *Fake) DeviceGetRetiredPages(device nvml.Device, cause nvml.PageRetirementCause) ([]uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetRetiredPages", device)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.RetiredPages == nil {
+		return nil, nvml.ErrNotSupported
+	}
+
+	return append([]uint64{}, d.RetiredPages[cause]...), nil
+}
+
+// DeviceGetAllRetiredPages returns the pages set in RetiredPages sorted by time, the time of a page being its
+// RetiredPageTimes entry (0 when missing).
+func (f *Fake) DeviceGetAllRetiredPages(device nvml.Device) ([]nvml.RetiredPage, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetAllRetiredPages", device)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.RetiredPages == nil {
+		return nil, nvml.ErrNotSupported
+	}
+
+	list := []nvml.RetiredPage{}
+	for _, cause := range []nvml.PageRetirementCause{
+		nvml.PageRetirementCauseMultipleSingleBitECCErrors,
+		nvml.PageRetirementCauseDoubleBitECCError,
+	} {
+		for _, address := range d.RetiredPages[cause] {
+			list = append(list, nvml.RetiredPage{Address: address, Time: d.RetiredPageTimes[address], Cause: cause})
+		}
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Time != list[j].Time {
+			return list[i].Time < list[j].Time
+		}
+
+		return list[i].Address < list[j].Address
+	})
+
+	return list, nil
+}
+
+// DeviceGetRetiredPagesPendingStatus returns RetiredPagesPending.
+func (f *Fake) DeviceGetRetiredPagesPendingStatus(device nvml.Device) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetRetiredPagesPendingStatus", device)
+	if err != nil {
+		return false, err
+	}
+
+	if d.RetiredPages == nil {
+		return false, nvml.ErrNotSupported
+	}
+
+	return d.RetiredPagesPending, nil
+}
+
+// DeviceGetRowRemapperHistogram returns the histogram set in RemappedRows.
+func (f *Fake) DeviceGetRowRemapperHistogram(device nvml.Device) (nvml.RowRemapperHistogram, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetRowRemapperHistogram", device)
+	if err != nil {
+		return nvml.RowRemapperHistogram{}, err
+	}
+
+	if d.RemappedRows == nil {
+		return nvml.RowRemapperHistogram{}, nvml.ErrNotSupported
+	}
+
+	return d.RemappedRows.Histogram, nil
+}
+
+// DeviceGetSamples returns the samples of the given type in Samples more recent than lastSeenTimeStamp.
+func (f *Fake) DeviceGetSamples(device nvml.Device, samplingType nvml.SamplingType, lastSeenTimeStamp uint64) (nvml.ValueType, []nvml.Sample, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetSamples", device)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	samples, ok := d.Samples[samplingType]
+	if !ok {
+		return 0, nil, nvml.ErrNotSupported
+	}
+
+	valueType := nvml.ValueTypeUnsignedInt
+	if len(samples) > 0 {
+		valueType = valueTypeOf(samples[0].Value)
+	}
+
+	list := []nvml.Sample{}
+	for _, sample := range samples {
+		if sample.TimeStamp > lastSeenTimeStamp {
+			list = append(list, sample)
+		}
+	}
+
+	return valueType, list, nil
+}
+
+// DeviceGetSerial returns Serial.
+func (f *Fake) DeviceGetSerial(device nvml.Device) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetSerial", device)
+	if err != nil {
+		return "", err
+	}
+
+	return d.Serial, nil
+}
+
+// DeviceGetSupportedClocksThrottleReasons returns SupportedClocksThrottleReasons.
+func (f *Fake) DeviceGetSupportedClocksThrottleReasons(device nvml.Device) (nvml.ClocksThrottleReason, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetSupportedClocksThrottleReasons", device)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.SupportedClocksThrottleReasons, nil
+}
+
+// DeviceGetSupportedGraphicsClocks returns a copy of the clocks set in SupportedGraphicsClocks.
+func (f *Fake) DeviceGetSupportedGraphicsClocks(device nvml.Device, memoryClockMHz uint32) ([]uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetSupportedGraphicsClocks", device)
+	if err != nil {
+		return nil, err
+	}
+
+	clocks, ok := d.SupportedGraphicsClocks[memoryClockMHz]
+	if !ok {
+		return nil, nvml.ErrNotFound
+	}
+
+	return append([]uint32{}, clocks...), nil
+}
+
+// DeviceGetSupportedMemoryClocks returns a copy of SupportedMemoryClocks.
+func (f *Fake) DeviceGetSupportedMemoryClocks(device nvml.Device) ([]uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetSupportedMemoryClocks", device)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]uint32{}, d.SupportedMemoryClocks...), nil
+}
+
+// DeviceGetTemperature returns Temperature.
+func (f *Fake) DeviceGetTemperature(device nvml.Device, sensorType nvml.TemperatureSensor) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetTemperature", device)
+	if err != nil {
+		return 0, err
+	}
+
+	if sensorType != nvml.TemperatureGPU {
+		return 0, nvml.ErrInvalidArgument
+	}
+
+	return d.Temperature, nil
+}
+
+// DeviceGetTemperatureThreshold returns the threshold set in TemperatureThresholds.
+func (f *Fake) DeviceGetTemperatureThreshold(device nvml.Device, thresholdType nvml.TemperatureThreshold) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetTemperatureThreshold", device)
+	if err != nil {
+		return 0, err
+	}
+
+	temp, ok := d.TemperatureThresholds[thresholdType]
+	if !ok {
+		return 0, nvml.ErrNotSupported
+	}
+
+	return temp, nil
+}
+
+// DeviceGetTopologyCommonAncestor returns the level set in Topology.
+func (f *Fake) DeviceGetTopologyCommonAncestor(device1 nvml.Device, device2 nvml.Device) (nvml.GPUTopologyLevel, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.lookup("DeviceGetTopologyCommonAncestor", device1); err != nil {
+		return 0, err
+	}
+
+	if _, err := f.lookup("DeviceGetTopologyCommonAncestor", device2); err != nil {
+		return 0, err
+	}
+
+	return f.topologyLevel(int(device1)-1, int(device2)-1), nil
+}
+
+// topologyLevel returns the common ancestor of devices i and j. Must be called with mu held.
+func (f *Fake) topologyLevel(i, j int) nvml.GPUTopologyLevel {
+	if i < len(f.Topology) && j < len(f.Topology[i]) {
+		return f.Topology[i][j]
+	}
+
+	if i == j {
+		return nvml.TopologyInternal
+	}
+
+	return nvml.TopologySystem
+}
+
+// DeviceGetTopologyNearestGpus returns the other devices whose common ancestor with device (see Topology) is at
+// level or closer.
+func (f *Fake) DeviceGetTopologyNearestGpus(device nvml.Device, level nvml.GPUTopologyLevel) ([]nvml.Device, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.lookup("DeviceGetTopologyNearestGpus", device); err != nil {
+		return nil, err
+	}
+
+	i := int(device) - 1
+	list := []nvml.Device{}
+	for j := range f.Devices {
+		if j != i && f.topologyLevel(i, j) <= level {
+			list = append(list, Handle(j))
+		}
+	}
+
+	return list, nil
+}
+
+// DeviceGetTotalECCErrors sums the matching ECCErrors across all memory locations.
+func (f *Fake) DeviceGetTotalECCErrors(device nvml.Device, errorType nvml.MemoryErrorType, counterType nvml.ECCCounterType) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetTotalECCErrors", device)
+	if err != nil {
+		return 0, err
+	}
+
+	if !d.ECCMode {
+		return 0, nvml.ErrNotSupported
+	}
+
+	var count uint64
+	for _, e := range d.ECCErrors {
+		if e.ErrorType == errorType && e.CounterType == counterType {
+			count += e.Count
+		}
+	}
+
+	return count, nil
+}
+
+// DeviceGetTotalEnergyConsumption returns TotalEnergyConsumption.
+func (f *Fake) DeviceGetTotalEnergyConsumption(device nvml.Device) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetTotalEnergyConsumption", device)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.TotalEnergyConsumption, nil
+}
+
+// DeviceGetUUID returns UUID.
+func (f *Fake) DeviceGetUUID(device nvml.Device) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetUUID", device)
+	if err != nil {
+		return "", err
+	}
+
+	return d.UUID, nil
+}
+
+// DeviceGetUtilizationRates returns Utilization.
+func (f *Fake) DeviceGetUtilizationRates(device nvml.Device) (nvml.Utilization, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetUtilizationRates", device)
+	if err != nil {
+		return nvml.Utilization{}, err
+	}
+
+	return d.Utilization, nil
+}
+
+// DeviceGetVbiosVersion returns VBIOSVersion.
+func (f *Fake) DeviceGetVbiosVersion(device nvml.Device) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetVbiosVersion", device)
+	if err != nil {
+		return "", err
+	}
+
+	return d.VBIOSVersion, nil
+}
+
+// DeviceGetViolationStatus returns the time set in ViolationStatus.
+func (f *Fake) DeviceGetViolationStatus(device nvml.Device, policyType nvml.PerfPolicyType) (nvml.ViolationTime, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetViolationStatus", device)
+	if err != nil {
+		return nvml.ViolationTime{}, err
+	}
+
+	violTime, ok := d.ViolationStatus[policyType]
+	if !ok {
+		return nvml.ViolationTime{}, nvml.ErrNotSupported
+	}
+
+	return violTime, nil
+}
+
+// DeviceOnSameBoard reports whether both handles refer to the same device, or to devices of a multi-GPU board
+// sharing the same BoardID.
+func (f *Fake) DeviceOnSameBoard(device1 nvml.Device, device2 nvml.Device) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d1, err := f.lookup("DeviceOnSameBoard", device1)
+	if err != nil {
+		return false, err
+	}
+
+	d2, err := f.lookup("DeviceOnSameBoard", device2)
+	if err != nil {
+		return false, err
+	}
+
+	if d1 == d2 {
+		return true, nil
+	}
+
+	return d1.MultiGPUBoard && d2.MultiGPUBoard && d1.BoardID == d2.BoardID, nil
+}
+
+// DeviceResetApplicationsClocks restores ApplicationsClocks from DefaultApplicationsClocks.
+func (f *Fake) DeviceResetApplicationsClocks(device nvml.Device) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceResetApplicationsClocks", device)
+	if err != nil {
+		return err
+	}
+
+	d.ApplicationsClocks = map[nvml.ClockType]uint32{}
+	for clockType, clock := range d.DefaultApplicationsClocks {
+		d.ApplicationsClocks[clockType] = clock
+	}
+
+	return nil
+}
+
+// DeviceSetAutoBoostedClocksEnabled sets AutoBoostedClocks.
+func (f *Fake) DeviceSetAutoBoostedClocksEnabled(device nvml.Device, enabled bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceSetAutoBoostedClocksEnabled", device)
+	if err != nil {
+		return err
+	}
+
+	d.AutoBoostedClocks = enabled
+	return nil
+}
+
+// DeviceSetDefaultAutoBoostedClocksEnabled sets DefaultAutoBoostedClocks.
+func (f *Fake) DeviceSetDefaultAutoBoostedClocksEnabled(device nvml.Device, enabled bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceSetDefaultAutoBoostedClocksEnabled", device)
+	if err != nil {
+		return err
+	}
+
+	d.DefaultAutoBoostedClocks = enabled
+	return nil
+}
+
+// DeviceValidateInforom succeeds unless an error is injected.
+func (f *Fake) DeviceValidateInforom(device nvml.Device) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, err := f.lookup("DeviceValidateInforom", device)
+	return err
+}
+
+// valueTypeOf returns the type NVML reports for values of the Go type of v.
+func valueTypeOf(v interface{}) nvml.ValueType {
+	switch v.(type) {
+	case float64:
+		return nvml.ValueTypeDouble
+	case uint64:
+		return nvml.ValueTypeUnsignedLongLong
+	case int64:
+		return nvml.ValueTypeSignedLongLong
+	default:
+		return nvml.ValueTypeUnsignedInt
+	}
+}
@@ -0,0 +1,158 @@
+package fake
+
+import (
+	"time"
+
+	nvml "github.com/mxpv/nvml-go"
+)
+
+// EventSet is the nvml.EventSet returned by Fake.EventSetCreate. Events are delivered with Fake.SendEvent.
+type EventSet struct {
+	f *Fake
+	// Registered event types by device, guarded by f.mu
+	registered map[nvml.Device]nvml.EventType
+	// Pending events, guarded by f.mu
+	queue []nvml.EventData
+	// Signaled when an event is queued
+	notify chan struct{}
+	freed  bool
+}
+
+var _ nvml.EventSet = &EventSet{}
+
+// DeviceGetSupportedEventTypes returns Device.SupportedEventTypes.
+func (f *Fake) DeviceGetSupportedEventTypes(device nvml.Device) (nvml.EventType, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetSupportedEventTypes", device)
+	if err != nil {
+		return 0, err
+	}
+
+	return d.SupportedEventTypes, nil
+}
+
+// EventSetCreate creates an empty EventSet.
+func (f *Fake) EventSetCreate() (nvml.EventSet, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check("EventSetCreate"); err != nil {
+		return nil, err
+	}
+
+	set := &EventSet{
+		f:          f,
+		registered: map[nvml.Device]nvml.EventType{},
+		notify:     make(chan struct{}, 1),
+	}
+
+	f.eventSets = append(f.eventSets, set)
+	return set, nil
+}
+
+// SendEvent delivers an event to the sets the event's device is registered in for its type.
+// It returns the number of sets the event was delivered to.
+func (f *Fake) SendEvent(event nvml.EventData) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	count := 0
+	for _, set := range f.eventSets {
+		if set.registered[event.Device]&event.Type == 0 {
+			continue
+		}
+
+		set.queue = append(set.queue, event)
+		select {
+		case set.notify <- struct{}{}:
+		default:
+		}
+
+		count++
+	}
+
+	return count
+}
+
+// Register adds the device to the set. Event types not in Device.SupportedEventTypes fail with nvml.ErrNotSupported.
+func (s *EventSet) Register(device nvml.Device, eventTypes nvml.EventType) error {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+
+	d, err := s.f.lookup("DeviceRegisterEvents", device)
+	if err != nil {
+		return err
+	}
+
+	if s.freed {
+		return nvml.ErrInvalidArgument
+	}
+
+	if eventTypes&^d.SupportedEventTypes != 0 {
+		return nvml.ErrNotSupported
+	}
+
+	s.registered[device] |= eventTypes
+	return nil
+}
+
+// Wait returns the oldest pending event, waiting up to timeout for one to be sent.
+func (s *EventSet) Wait(timeout time.Duration) (nvml.EventData, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		s.f.mu.Lock()
+		if err := s.f.check("EventSetWait"); err != nil {
+			s.f.mu.Unlock()
+			return nvml.EventData{}, err
+		}
+
+		if s.freed {
+			s.f.mu.Unlock()
+			return nvml.EventData{}, nvml.ErrInvalidArgument
+		}
+
+		if len(s.queue) > 0 {
+			event := s.queue[0]
+			s.queue = s.queue[1:]
+			s.f.mu.Unlock()
+			return event, nil
+		}
+		s.f.mu.Unlock()
+
+		select {
+		case <-s.notify:
+		case <-timer.C:
+			return nvml.EventData{}, nvml.ErrTimeout
+		}
+	}
+}
+
+// Free releases the set. Further calls to it fail with nvml.ErrInvalidArgument.
+func (s *EventSet) Free() error {
+	s.f.mu.Lock()
+	defer s.f.mu.Unlock()
+
+	if err := s.f.check("EventSetFree"); err != nil {
+		return err
+	}
+
+	if s.freed {
+		return nvml.ErrInvalidArgument
+	}
+
+	s.freed = true
+	s.queue = nil
+
+	for i, set := range s.f.eventSets {
+		if set == s {
+			s.f.eventSets = append(s.f.eventSets[:i], s.f.eventSets[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package fake
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	nvml "github.com/mxpv/nvml-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventSet(t *testing.T) {
+	f := create(t, 2)
+	defer f.Shutdown()
+
+	types, err := f.DeviceGetSupportedEventTypes(Handle(0))
+	require.NoError(t, err)
+	require.NotZero(t, types&nvml.EventTypeXIDCriticalError)
+
+	set, err := f.EventSetCreate()
+	require.NoError(t, err)
+
+	err = set.Register(Handle(0), nvml.EventTypeXIDCriticalError|nvml.EventTypeDoubleBitECCError)
+	require.NoError(t, err)
+
+	err = set.Register(Handle(1), nvml.EventTypePowerSourceChange)
+	require.Equal(t, nvml.ErrNotSupported, err)
+
+	_, err = set.Wait(time.Millisecond)
+	require.Equal(t, nvml.ErrTimeout, err)
+
+	xid := nvml.EventData{
+		Device:            Handle(0),
+		Type:              nvml.EventTypeXIDCriticalError,
+		XID:               79,
+		GPUInstanceID:     math.MaxUint32,
+		ComputeInstanceID: math.MaxUint32,
+	}
+
+	// Not registered for this device or type
+	require.Zero(t, f.SendEvent(nvml.EventData{Device: Handle(1), Type: nvml.EventTypeXIDCriticalError}))
+	require.Zero(t, f.SendEvent(nvml.EventData{Device: Handle(0), Type: nvml.EventTypePState}))
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		f.SendEvent(xid)
+	}()
+
+	event, err := set.Wait(time.Second)
+	require.NoError(t, err)
+	require.Equal(t, xid, event)
+
+	require.NoError(t, set.Free())
+	require.Zero(t, f.SendEvent(xid))
+
+	_, err = set.Wait(time.Millisecond)
+	require.Equal(t, nvml.ErrInvalidArgument, err)
+}
@@ -0,0 +1,430 @@
+// Package fake provides an in-memory implementation of nvml.Interface.
+// It's intended for unit testing code built on top of nvml on machines without NVIDIA GPUs.
+//
+// Every simulated GPU is described by a Device. Calls return the values stored in the Device, setters update them.
+// Optional features (clocks of a given type, violation counters, etc) are reported as nvml.ErrNotSupported when the
+// corresponding map entry is missing. Errors can be injected for any method either globally (Fake.Errors) or
+// for a single device (Device.Errors), using the method name as a key:
+//
+//	f := fake.New(2)
+//	f.Devices[1].Errors["DeviceGetTemperature"] = nvml.ErrGPULost
+package fake
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	nvml "github.com/mxpv/nvml-go"
+)
+
+// EncoderStats holds the values returned by DeviceGetEncoderStats.
+type EncoderStats struct {
+	SessionCount   uint32
+	AverageFPS     uint32
+	AverageLatency uint32
+}
+
+// ECCErrorCount is a single memory error counter of a simulated device.
+type ECCErrorCount struct {
+	ErrorType   nvml.MemoryErrorType
+	CounterType nvml.ECCCounterType
+	Location    nvml.MemoryLocation
+	Count       uint64
+}
+
+// RemappedRows holds the row remapping state of a simulated device.
+type RemappedRows struct {
+	Correctable     uint32
+	Uncorrectable   uint32
+	Pending         bool
+	FailureOccurred bool
+	Histogram       nvml.RowRemapperHistogram
+}
+
+// Device describes a simulated GPU.
+type Device struct {
+	Name                string
+	Brand               nvml.BrandType
+	Serial              string
+	UUID                string
+	BoardID             uint32
+	BoardPartNumber     string
+	MultiGPUBoard       bool
+	BridgeChips         []nvml.BridgeChipInfo
+	MinorNumber         uint32
+	VBIOSVersion        string
+	InfoROMImageVersion string
+	InfoROMVersions     map[nvml.InfoROMObject]string
+	InfoROMChecksum     uint32
+
+	PCI                   nvml.PCIInfo
+	PCIeLinkGeneration    uint32
+	MaxPCIeLinkGeneration uint32
+	PCIeLinkWidth         uint32
+	MaxPCIeLinkWidth      uint32
+	PCIeReplayCounter     uint32
+	PCIeThroughput        map[nvml.PCIeUtilCounter]uint32
+
+	ComputeCapabilityMajor  int32
+	ComputeCapabilityMinor  int32
+	ComputeMode             nvml.ComputeMode
+	DriverModel             nvml.DriverModel
+	PendingDriverModel      nvml.DriverModel
+	GPUOperationMode        nvml.GPUOperationMode
+	PendingGPUOperationMode nvml.GPUOperationMode
+	PersistenceMode         bool
+	Drained                 bool
+	Removed                 bool
+	DisplayActive           bool
+	DisplayMode             bool
+	APIRestrictions         map[nvml.RestrictedAPI]bool
+	CPUAffinity             []uint64
+
+	Memory     nvml.Memory
+	BAR1Memory nvml.BAR1Memory
+
+	// Current clocks, as reported by DeviceGetClockInfo
+	Clocks                    map[nvml.ClockType]uint32
+	MaxClocks                 map[nvml.ClockType]uint32
+	MaxCustomerBoostClocks    map[nvml.ClockType]uint32
+	ApplicationsClocks        map[nvml.ClockType]uint32
+	DefaultApplicationsClocks map[nvml.ClockType]uint32
+	SupportedMemoryClocks     []uint32
+	// Supported graphics clocks by memory clock
+	SupportedGraphicsClocks        map[uint32][]uint32
+	AutoBoostedClocks              bool
+	DefaultAutoBoostedClocks       bool
+	ClocksThrottleReasons          nvml.ClocksThrottleReason
+	SupportedClocksThrottleReasons nvml.ClocksThrottleReason
+
+	PerformanceState       nvml.PState
+	Temperature            uint32
+	TemperatureThresholds  map[nvml.TemperatureThreshold]uint32
+	FanSpeed               uint32
+	PowerManagementMode    bool
+	PowerUsage             uint32
+	PowerLimit             uint32
+	DefaultPowerLimit      uint32
+	EnforcedPowerLimit     uint32
+	MinPowerLimit          uint32
+	MaxPowerLimit          uint32
+	TotalEnergyConsumption uint64
+	ViolationStatus        map[nvml.PerfPolicyType]nvml.ViolationTime
+
+	Utilization        nvml.Utilization
+	EncoderUtilization uint32
+	DecoderUtilization uint32
+	SamplingPeriodUs   uint32
+	EncoderCapacity    map[nvml.EncoderType]uint32
+	EncoderStats       EncoderStats
+	EncoderSessions    []nvml.EncoderSessionInfo
+	FBCStats           nvml.FBCStats
+	FBCSessions        []nvml.FBCSessionInfo
+	// Samples returned by DeviceGetSamples by type, sorted by timestamp. Missing types are not supported.
+	// The value type is derived from the Go type of the first sample value.
+	Samples map[nvml.SamplingType][]nvml.Sample
+	// Samples returned by DeviceGetProcessUtilization, sorted by timestamp
+	ProcessUtilization []nvml.ProcessUtilizationSample
+	// Values returned by DeviceGetFieldValues, a float64, uint32, uint64 or int64. Missing fields are not supported.
+	FieldValues map[nvml.FieldRequest]interface{}
+
+	ECCMode             bool
+	PendingECCMode      bool
+	ECCErrors           []ECCErrorCount
+	RetiredPages        map[nvml.PageRetirementCause][]uint64
+	RetiredPageTimes    map[uint64]uint64
+	RetiredPagesPending bool
+	// Row remapping state, nil on devices not supporting row remapping.
+	// Devices with a nil RetiredPages don't support page retirement.
+	RemappedRows *RemappedRows
+
+	ComputeProcesses  []nvml.ProcessInfo
+	GraphicsProcesses []nvml.ProcessInfo
+
+	AccountingMode       bool
+	AccountingBufferSize uint32
+	// Accounting statistics by PID, kept while AccountingMode is enabled
+	AccountingStats map[uint32]nvml.AccountingStats
+
+	SupportedEventTypes nvml.EventType
+
+	// Links of the device by index, none by default (see ConnectNvLinks)
+	NvLinks []*NvLink
+
+	MigMode        nvml.MigMode
+	PendingMigMode nvml.MigMode
+	// GPU instance profiles, MIG is not supported when empty (see NewMigDevice)
+	GpuInstanceProfiles map[nvml.GpuInstanceProfile]*GpuInstanceProfile
+	GpuInstances        []*GpuInstance
+
+	// Errors to return from calls targeting this device, by method name
+	Errors map[string]error
+}
+
+// Fake is an in-memory nvml.Interface implementation. It's safe for concurrent use.
+type Fake struct {
+	mu        sync.Mutex
+	initCount int
+	eventSets []*EventSet
+	// Last handle given to a GPU instance or a compute instance (see newHandle)
+	nextHandle uintptr
+
+	DriverVersion     string
+	NVMLVersion       string
+	CUDADriverVersion int32
+	// Names of the processes returned by SystemGetProcessName, by PID
+	ProcessNames map[uint]string
+	Devices      []*Device
+	// S-class units, none by default
+	Units []*Unit
+	// Topology[i][j] is the common ancestor of devices i and j.
+	// When empty, a device is reported as internal to itself and every pair of devices is connected through SMP.
+	Topology [][]nvml.GPUTopologyLevel
+	// P2P[index][i][j] is the peer-to-peer status of devices i and j for the given capability.
+	// When missing, a device is reported as able to talk to itself and every pair of devices as not supported.
+	P2P map[nvml.P2PCapsIndex][][]nvml.P2PStatus
+
+	// Errors to return from calls, by method name. Checked before device specific errors.
+	Errors map[string]error
+}
+
+var _ nvml.Interface = &Fake{}
+
+// New creates a Fake simulating count identical GPUs (see NewDevice).
+func New(count int) *Fake {
+	f := &Fake{
+		DriverVersion:     "450.80.02",
+		NVMLVersion:       "11.450.80.02",
+		CUDADriverVersion: 11000,
+		ProcessNames:      map[uint]string{},
+		Errors:            map[string]error{},
+	}
+
+	for i := 0; i < count; i++ {
+		f.Devices = append(f.Devices, NewDevice(i))
+	}
+
+	return f
+}
+
+// NewDevice returns a Device resembling a Tesla V100. Identifiers (serial, UUID, PCI bus ID) are derived from index.
+func NewDevice(index int) *Device {
+	bus := uint32(index + 1)
+
+	return &Device{
+		Name:                "Tesla V100-SXM2-16GB",
+		Brand:               nvml.BrandTesla,
+		Serial:              fmt.Sprintf("0323118%06d", index),
+		UUID:                fmt.Sprintf("GPU-%08x-0000-0000-0000-%012x", index, index),
+		BoardID:             bus << 8,
+		BoardPartNumber:     "900-2G503-0000-000",
+		MinorNumber:         uint32(index),
+		VBIOSVersion:        "88.00.4F.00.09",
+		InfoROMImageVersion: "G503.0201.00.03",
+		InfoROMVersions: map[nvml.InfoROMObject]string{
+			nvml.InfoROMObjectOEM:   "1.1",
+			nvml.InfoROMObjectECC:   "5.0",
+			nvml.InfoROMObjectPower: "N/A",
+		},
+		InfoROMChecksum: 0x8b5c2a0e,
+		PCI: nvml.PCIInfo{
+			BusID:          fmt.Sprintf("00000000:%02X:00.0", bus),
+			BusIDLegacy:    fmt.Sprintf("0000:%02X:00.0", bus),
+			Bus:            bus,
+			PCIDeviceID:    0x1db110de,
+			PCISubsystemID: 0x121210de,
+		},
+		PCIeLinkGeneration:    3,
+		MaxPCIeLinkGeneration: 3,
+		PCIeLinkWidth:         16,
+		MaxPCIeLinkWidth:      16,
+		PCIeThroughput: map[nvml.PCIeUtilCounter]uint32{
+			nvml.PCIeUtilTXBytes: 0,
+			nvml.PCIeUtilRXBytes: 0,
+		},
+		ComputeCapabilityMajor:  7,
+		ComputeCapabilityMinor:  0,
+		ComputeMode:             nvml.ComputeModeDefault,
+		DriverModel:             nvml.DriverModelWDM,
+		PendingDriverModel:      nvml.DriverModelWDM,
+		GPUOperationMode:        nvml.GPUOperationModeAllOn,
+		PendingGPUOperationMode: nvml.GPUOperationModeAllOn,
+		APIRestrictions:         map[nvml.RestrictedAPI]bool{},
+		CPUAffinity:             []uint64{0xffff},
+		Memory:                  nvml.Memory{Total: 16 << 30, Free: 16<<30 - 300<<20, Used: 300 << 20},
+		BAR1Memory:              nvml.BAR1Memory{Total: 16 << 30, Free: 16<<30 - 2<<20, Used: 2 << 20},
+		Clocks: map[nvml.ClockType]uint32{
+			nvml.ClockGraphics: 135,
+			nvml.ClockSM:       135,
+			nvml.ClockMem:      877,
+			nvml.ClockVideo:    555,
+		},
+		MaxClocks: map[nvml.ClockType]uint32{
+			nvml.ClockGraphics: 1530,
+			nvml.ClockSM:       1530,
+			nvml.ClockMem:      877,
+			nvml.ClockVideo:    1372,
+		},
+		MaxCustomerBoostClocks: map[nvml.ClockType]uint32{
+			nvml.ClockGraphics: 1530,
+			nvml.ClockSM:       1530,
+			nvml.ClockMem:      877,
+			nvml.ClockVideo:    1372,
+		},
+		ApplicationsClocks: map[nvml.ClockType]uint32{
+			nvml.ClockGraphics: 1312,
+			nvml.ClockMem:      877,
+		},
+		DefaultApplicationsClocks: map[nvml.ClockType]uint32{
+			nvml.ClockGraphics: 1312,
+			nvml.ClockMem:      877,
+		},
+		SupportedMemoryClocks: []uint32{877},
+		SupportedGraphicsClocks: map[uint32][]uint32{
+			877: {1530, 1312, 1005, 135},
+		},
+		ClocksThrottleReasons:          nvml.ClocksThrottleReasonGPUIdle,
+		SupportedClocksThrottleReasons: 0xff,
+		PerformanceState:               nvml.PState0,
+		Temperature:                    35,
+		TemperatureThresholds: map[nvml.TemperatureThreshold]uint32{
+			nvml.TemperatureThresholdShutdown: 90,
+			nvml.TemperatureThresholdSlowdown: 87,
+			nvml.TemperatureThresholdMemMax:   95,
+			nvml.TemperatureThresholdGPUMax:   83,
+		},
+		PowerManagementMode: true,
+		PowerUsage:          42000,
+		PowerLimit:          300000,
+		DefaultPowerLimit:   300000,
+		EnforcedPowerLimit:  300000,
+		MinPowerLimit:       150000,
+		MaxPowerLimit:       300000,
+		ViolationStatus: map[nvml.PerfPolicyType]nvml.ViolationTime{
+			nvml.PerfPolicyPower:   {},
+			nvml.PerfPolicyThermal: {},
+		},
+		SamplingPeriodUs: 167000,
+		EncoderCapacity: map[nvml.EncoderType]uint32{
+			nvml.EncoderTypeQueryH264: 100,
+			nvml.EncoderTypeQueryHEVC: 100,
+		},
+		ECCMode:        true,
+		PendingECCMode: true,
+		RetiredPages:   map[nvml.PageRetirementCause][]uint64{},
+		SupportedEventTypes: nvml.EventTypeSingleBitECCError | nvml.EventTypeDoubleBitECCError |
+			nvml.EventTypePState | nvml.EventTypeXIDCriticalError | nvml.EventTypeClock,
+		FieldValues:          map[nvml.FieldRequest]interface{}{},
+		AccountingBufferSize: 4000,
+		AccountingStats:      map[uint32]nvml.AccountingStats{},
+		Errors:               map[string]error{},
+	}
+}
+
+// Handle returns the handle of the device with the given index, as returned by DeviceGetHandleByIndex.
+func Handle(index int) nvml.Device {
+	return nvml.Device(index + 1)
+}
+
+// check returns the error to report from method before looking at its arguments. Must be called with mu held.
+func (f *Fake) check(method string) error {
+	if f.initCount == 0 {
+		return nvml.ErrUninitialized
+	}
+
+	return f.Errors[method]
+}
+
+// lookup returns the device referred by handle, or the error method should report. Must be called with mu held.
+// MIG device handles resolve to the MIG device of the compute instance (see ComputeInstance.Device).
+func (f *Fake) lookup(method string, handle nvml.Device) (*Device, error) {
+	if err := f.check(method); err != nil {
+		return nil, err
+	}
+
+	var d *Device
+	if index := int(handle) - 1; index >= 0 && index < len(f.Devices) {
+		d = f.Devices[index]
+	} else if _, _, ci := f.computeInstance(nvml.ComputeInstance(handle)); ci != nil {
+		d = ci.Device
+	} else {
+		return nil, nvml.ErrInvalidArgument
+	}
+
+	if d.Removed {
+		// Handles of removed devices are invalid until they're rediscovered
+		return nil, nvml.ErrInvalidArgument
+	}
+
+	if err := d.Errors[method]; err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// find returns the handle of the first device matching the predicate. Must be called with mu held.
+func (f *Fake) find(method string, match func(d *Device) bool) (nvml.Device, error) {
+	if err := f.check(method); err != nil {
+		return 0, err
+	}
+
+	for i, d := range f.Devices {
+		if !d.Removed && match(d) {
+			return Handle(i), nil
+		}
+	}
+
+	return 0, nvml.ErrNotFound
+}
+
+// findByPCI returns the device at the domain, bus and device of pci, or the error method should report.
+// Must be called with mu held.
+func (f *Fake) findByPCI(method string, pci *nvml.PCIInfo) (*Device, error) {
+	if err := f.check(method); err != nil {
+		return nil, err
+	}
+
+	for _, d := range f.Devices {
+		if d.PCI.Domain == pci.Domain && d.PCI.Bus == pci.Bus && d.PCI.Device == pci.Device {
+			if err := d.Errors[method]; err != nil {
+				return nil, err
+			}
+
+			return d, nil
+		}
+	}
+
+	return nil, nvml.ErrNotFound
+}
+
+// Init increments the initialization reference count. All other calls fail with nvml.ErrUninitialized until Init is called.
+func (f *Fake) Init() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.Errors["Init"]; err != nil {
+		return err
+	}
+
+	f.initCount++
+	return nil
+}
+
+// Shutdown decrements the initialization reference count.
+func (f *Fake) Shutdown() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check("Shutdown"); err != nil {
+		return err
+	}
+
+	f.initCount--
+	return nil
+}
+
+func equalBusID(a, b string) bool {
+	return a != "" && strings.EqualFold(a, b)
+}
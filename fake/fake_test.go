@@ -0,0 +1,461 @@
+package fake
+
+import (
+	"testing"
+
+	nvml "github.com/mxpv/nvml-go"
+	"github.com/stretchr/testify/require"
+)
+
+func create(t *testing.T, count int) *Fake {
+	f := New(count)
+	require.NoError(t, f.Init())
+	return f
+}
+
+func TestUninitialized(t *testing.T) {
+	f := New(1)
+
+	_, err := f.DeviceGetCount()
+	require.Equal(t, nvml.ErrUninitialized, err)
+
+	require.NoError(t, f.Init())
+	require.NoError(t, f.Shutdown())
+
+	_, err = f.DeviceGetHandleByIndex(0)
+	require.Equal(t, nvml.ErrUninitialized, err)
+}
+
+func TestDeviceHandles(t *testing.T) {
+	f := create(t, 4)
+	defer f.Shutdown()
+
+	count, err := f.DeviceGetCount()
+	require.NoError(t, err)
+	require.Equal(t, uint32(4), count)
+
+	device, err := f.DeviceGetHandleByIndex(2)
+	require.NoError(t, err)
+
+	index, err := f.DeviceGetIndex(device)
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), index)
+
+	_, err = f.DeviceGetHandleByIndex(4)
+	require.Equal(t, nvml.ErrInvalidArgument, err)
+
+	uuid, err := f.DeviceGetUUID(device)
+	require.NoError(t, err)
+
+	result, err := f.DeviceGetHandleByUUID(uuid)
+	require.NoError(t, err)
+	require.Equal(t, device, result)
+
+	info, err := f.DeviceGetPCIInfo(device)
+	require.NoError(t, err)
+
+	result, err = f.DeviceGetHandleByPCIBusID(info.BusIDLegacy)
+	require.NoError(t, err)
+	require.Equal(t, device, result)
+
+	_, err = f.DeviceGetHandleBySerial("unknown")
+	require.Equal(t, nvml.ErrNotFound, err)
+
+	_, err = f.DeviceGetName(nvml.Device(42))
+	require.Equal(t, nvml.ErrInvalidArgument, err)
+}
+
+func TestErrorInjection(t *testing.T) {
+	f := create(t, 2)
+	defer f.Shutdown()
+
+	f.Devices[1].Errors["DeviceGetTemperature"] = nvml.ErrGPULost
+
+	_, err := f.DeviceGetTemperature(Handle(0), nvml.TemperatureGPU)
+	require.NoError(t, err)
+
+	_, err = f.DeviceGetTemperature(Handle(1), nvml.TemperatureGPU)
+	require.Equal(t, nvml.ErrGPULost, err)
+
+	f.Errors["SystemGetDriverVersion"] = nvml.ErrLibRMVersionMismatch
+	_, err = f.SystemGetDriverVersion()
+	require.Equal(t, nvml.ErrLibRMVersionMismatch, err)
+}
+
+func TestClocks(t *testing.T) {
+	f := create(t, 1)
+	defer f.Shutdown()
+
+	device := Handle(0)
+	f.Devices[0].Clocks[nvml.ClockGraphics] = 1200
+	delete(f.Devices[0].Clocks, nvml.ClockVideo)
+
+	clock, err := f.DeviceGetClockInfo(device, nvml.ClockGraphics)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1200), clock)
+
+	_, err = f.DeviceGetClock(device, nvml.ClockVideo, nvml.ClockIDCurrent)
+	require.Equal(t, nvml.ErrNotSupported, err)
+
+	err = f.DeviceSetApplicationsClocks(device, 877, 1005)
+	require.NoError(t, err)
+
+	clock, err = f.DeviceGetClock(device, nvml.ClockGraphics, nvml.ClockIDAppClockTarget)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1005), clock)
+
+	err = f.DeviceSetApplicationsClocks(device, 877, 1006)
+	require.Equal(t, nvml.ErrInvalidArgument, err)
+
+	err = f.DeviceResetApplicationsClocks(device)
+	require.NoError(t, err)
+
+	clock, err = f.DeviceGetApplicationsClock(device, nvml.ClockGraphics)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1312), clock)
+}
+
+func TestProcesses(t *testing.T) {
+	f := create(t, 1)
+	defer f.Shutdown()
+
+	f.Devices[0].ComputeProcesses = []nvml.ProcessInfo{{PID: 1234, UsedGPUMemory: 1 << 30}}
+	f.ProcessNames[1234] = "python"
+
+	list, err := f.DeviceGetComputeRunningProcesses(Handle(0))
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	name, err := f.SystemGetProcessName(uint(list[0].PID))
+	require.NoError(t, err)
+	require.Equal(t, "python", name)
+
+	list, err = f.DeviceGetGraphicsRunningProcesses(Handle(0))
+	require.NoError(t, err)
+	require.Empty(t, list)
+}
+
+func TestECCErrors(t *testing.T) {
+	f := create(t, 1)
+	defer f.Shutdown()
+
+	f.Devices[0].ECCErrors = []ECCErrorCount{
+		{ErrorType: nvml.MemoryErrorTypeCorrected, CounterType: nvml.VolatileECC, Location: nvml.MemoryLocationL2Cache, Count: 3},
+		{ErrorType: nvml.MemoryErrorTypeCorrected, CounterType: nvml.VolatileECC, Location: nvml.MemoryLocationDeviceMemory, Count: 4},
+		{ErrorType: nvml.MemoryErrorTypeCorrected, CounterType: nvml.AggregateECC, Location: nvml.MemoryLocationDeviceMemory, Count: 10},
+	}
+
+	total, err := f.DeviceGetTotalECCErrors(Handle(0), nvml.MemoryErrorTypeCorrected, nvml.VolatileECC)
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), total)
+
+	counts, err := f.DeviceGetDetailedECCErrors(Handle(0), nvml.MemoryErrorTypeCorrected, nvml.VolatileECC)
+	require.NoError(t, err)
+	require.Equal(t, &nvml.ECCErrorCounts{L2Cache: 3, DeviceMemory: 4}, counts)
+
+	err = f.DeviceClearECCErrorCounts(Handle(0), nvml.VolatileECC)
+	require.NoError(t, err)
+
+	total, err = f.DeviceGetTotalECCErrors(Handle(0), nvml.MemoryErrorTypeCorrected, nvml.VolatileECC)
+	require.NoError(t, err)
+	require.Zero(t, total)
+
+	count, err := f.DeviceGetMemoryErrorCounter(Handle(0), nvml.MemoryErrorTypeCorrected, nvml.AggregateECC, nvml.MemoryLocationDeviceMemory)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), count)
+}
+
+func TestPowerLimit(t *testing.T) {
+	f := create(t, 1)
+	defer f.Shutdown()
+
+	err := f.DeviceSetPowerManagementLimit(Handle(0), 200000)
+	require.NoError(t, err)
+
+	limit, err := f.DeviceGetEnforcedPowerLimit(Handle(0))
+	require.NoError(t, err)
+	require.Equal(t, uint32(200000), limit)
+
+	err = f.DeviceSetPowerManagementLimit(Handle(0), 1000)
+	require.Equal(t, nvml.ErrInvalidArgument, err)
+}
+
+func TestTopologyCommonAncestor(t *testing.T) {
+	f := create(t, 2)
+	defer f.Shutdown()
+
+	level, err := f.DeviceGetTopologyCommonAncestor(Handle(0), Handle(0))
+	require.NoError(t, err)
+	require.Equal(t, nvml.TopologyInternal, level)
+
+	level, err = f.DeviceGetTopologyCommonAncestor(Handle(0), Handle(1))
+	require.NoError(t, err)
+	require.Equal(t, nvml.TopologySystem, level)
+
+	f.Topology = [][]nvml.GPUTopologyLevel{
+		{nvml.TopologyInternal, nvml.TopologySingle},
+		{nvml.TopologySingle, nvml.TopologyInternal},
+	}
+
+	level, err = f.DeviceGetTopologyCommonAncestor(Handle(1), Handle(0))
+	require.NoError(t, err)
+	require.Equal(t, nvml.TopologySingle, level)
+}
+
+func TestP2PStatus(t *testing.T) {
+	f := create(t, 2)
+	defer f.Shutdown()
+
+	status, err := f.DeviceGetP2PStatus(Handle(0), Handle(1), nvml.P2PCapsIndexRead)
+	require.NoError(t, err)
+	require.Equal(t, nvml.P2PStatusNotSupported, status)
+
+	f.P2P = map[nvml.P2PCapsIndex][][]nvml.P2PStatus{
+		nvml.P2PCapsIndexRead: {
+			{nvml.P2PStatusOK, nvml.P2PStatusChipsetNotSupported},
+			{nvml.P2PStatusChipsetNotSupported, nvml.P2PStatusOK},
+		},
+	}
+
+	status, err = f.DeviceGetP2PStatus(Handle(1), Handle(0), nvml.P2PCapsIndexRead)
+	require.NoError(t, err)
+	require.Equal(t, nvml.P2PStatusChipsetNotSupported, status)
+
+	_, err = f.DeviceGetP2PStatus(Handle(0), Handle(2), nvml.P2PCapsIndexRead)
+	require.Equal(t, nvml.ErrInvalidArgument, err)
+
+	_, err = f.DeviceGetP2PStatus(Handle(0), Handle(1), nvml.P2PCapsIndexUnknown)
+	require.Equal(t, nvml.ErrInvalidArgument, err)
+}
+
+func TestTopologyNearestGpus(t *testing.T) {
+	f := create(t, 3)
+	defer f.Shutdown()
+
+	devices, err := f.DeviceGetTopologyNearestGpus(Handle(0), nvml.TopologySingle)
+	require.NoError(t, err)
+	require.Empty(t, devices)
+
+	f.Topology = [][]nvml.GPUTopologyLevel{
+		{nvml.TopologyInternal, nvml.TopologySingle, nvml.TopologyNode},
+		{nvml.TopologySingle, nvml.TopologyInternal, nvml.TopologyNode},
+		{nvml.TopologyNode, nvml.TopologyNode, nvml.TopologyInternal},
+	}
+
+	devices, err = f.DeviceGetTopologyNearestGpus(Handle(0), nvml.TopologyHostbridge)
+	require.NoError(t, err)
+	require.Equal(t, []nvml.Device{Handle(1)}, devices)
+
+	devices, err = f.DeviceGetTopologyNearestGpus(Handle(2), nvml.TopologySystem)
+	require.NoError(t, err)
+	require.Equal(t, []nvml.Device{Handle(0), Handle(1)}, devices)
+}
+
+func TestSystemGetTopologyGpuSet(t *testing.T) {
+	f := create(t, 2)
+	defer f.Shutdown()
+
+	f.Devices[1].CPUAffinity = []uint64{0xff00, 0x1}
+
+	devices, err := f.SystemGetTopologyGpuSet(0)
+	require.NoError(t, err)
+	require.Equal(t, []nvml.Device{Handle(0)}, devices)
+
+	devices, err = f.SystemGetTopologyGpuSet(8)
+	require.NoError(t, err)
+	require.Equal(t, []nvml.Device{Handle(0), Handle(1)}, devices)
+
+	devices, err = f.SystemGetTopologyGpuSet(64)
+	require.NoError(t, err)
+	require.Equal(t, []nvml.Device{Handle(1)}, devices)
+
+	devices, err = f.SystemGetTopologyGpuSet(40)
+	require.NoError(t, err)
+	require.Empty(t, devices)
+
+	devices, err = f.SystemGetTopologyGpuSet(200)
+	require.NoError(t, err)
+	require.Empty(t, devices)
+
+	cpuSet, err := f.DeviceGetCPUAffinity(Handle(1), 3)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{0xff00, 0x1, 0}, cpuSet)
+}
+
+func TestSamples(t *testing.T) {
+	f := create(t, 1)
+	defer f.Shutdown()
+
+	_, _, err := f.DeviceGetSamples(Handle(0), nvml.SamplingTypeTotalPower, 0)
+	require.Equal(t, nvml.ErrNotSupported, err)
+
+	f.Devices[0].Samples = map[nvml.SamplingType][]nvml.Sample{
+		nvml.SamplingTypeTotalPower: {
+			{TimeStamp: 100, Value: uint32(65000)},
+			{TimeStamp: 200, Value: uint32(250000)},
+		},
+		nvml.SamplingTypeProcessorClock: {},
+	}
+
+	valueType, samples, err := f.DeviceGetSamples(Handle(0), nvml.SamplingTypeTotalPower, 100)
+	require.NoError(t, err)
+	require.Equal(t, nvml.ValueTypeUnsignedInt, valueType)
+	require.Equal(t, []nvml.Sample{{TimeStamp: 200, Value: uint32(250000)}}, samples)
+
+	_, samples, err = f.DeviceGetSamples(Handle(0), nvml.SamplingTypeProcessorClock, 0)
+	require.NoError(t, err)
+	require.Empty(t, samples)
+}
+
+func TestEncoderSessions(t *testing.T) {
+	f := create(t, 1)
+	defer f.Shutdown()
+
+	sessions, err := f.DeviceGetEncoderSessions(Handle(0))
+	require.NoError(t, err)
+	require.Empty(t, sessions)
+
+	f.Devices[0].EncoderSessions = []nvml.EncoderSessionInfo{{SessionID: 1, PID: 1234, CodecType: nvml.EncoderTypeQueryH264}}
+	f.Devices[0].FBCSessions = []nvml.FBCSessionInfo{{SessionID: 2, PID: 1234, SessionType: nvml.FBCSessionTypeCUDA}}
+	f.Devices[0].FBCStats = nvml.FBCStats{SessionsCount: 1, AverageFPS: 60}
+
+	sessions, err = f.DeviceGetEncoderSessions(Handle(0))
+	require.NoError(t, err)
+	require.Equal(t, f.Devices[0].EncoderSessions, sessions)
+
+	fbc, err := f.DeviceGetFBCSessions(Handle(0))
+	require.NoError(t, err)
+	require.Equal(t, nvml.FBCSessionTypeCUDA, fbc[0].SessionType)
+
+	stats, err := f.DeviceGetFBCStats(Handle(0))
+	require.NoError(t, err)
+	require.Equal(t, uint32(60), stats.AverageFPS)
+}
+
+func TestBridgeChipInfo(t *testing.T) {
+	f := create(t, 2)
+	defer f.Shutdown()
+
+	hierarchy, err := f.DeviceGetBridgeChipInfo(Handle(0))
+	require.NoError(t, err)
+	require.Empty(t, hierarchy.BridgeChips)
+
+	f.Devices[1].BridgeChips = []nvml.BridgeChipInfo{{Type: nvml.BridgeChipPLX, FirmwareVersion: 0x1b0}}
+
+	hierarchy, err = f.DeviceGetBridgeChipInfo(Handle(1))
+	require.NoError(t, err)
+	require.Equal(t, f.Devices[1].BridgeChips, hierarchy.BridgeChips)
+}
+
+func TestFieldValues(t *testing.T) {
+	f := create(t, 1)
+	defer f.Shutdown()
+
+	replay := nvml.FieldRequest{FieldID: nvml.FieldPCIeReplayCounter}
+	energy := nvml.FieldRequest{FieldID: nvml.FieldTotalEnergyConsumption}
+	f.Devices[0].FieldValues[replay] = uint64(12)
+	f.Devices[0].FieldValues[energy] = uint64(1000)
+
+	values, err := f.DeviceGetFieldValues(Handle(0), []nvml.FieldRequest{replay, {FieldID: nvml.FieldMemoryTemp}})
+	require.NoError(t, err)
+	require.Equal(t, []nvml.FieldValue{
+		{FieldID: nvml.FieldPCIeReplayCounter, ValueType: nvml.ValueTypeUnsignedLongLong, Value: uint64(12)},
+		{FieldID: nvml.FieldMemoryTemp, Err: nvml.ErrNotSupported},
+	}, values)
+
+	err = f.DeviceClearFieldValues(Handle(0), []nvml.FieldRequest{replay})
+	require.NoError(t, err)
+
+	values, err = f.DeviceGetFieldValues(Handle(0), []nvml.FieldRequest{replay, energy})
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), values[0].Value)
+	require.Equal(t, uint64(1000), values[1].Value)
+}
+
+func TestRemappedRows(t *testing.T) {
+	f := New(0)
+	f.Devices = []*Device{NewDevice(0), NewMigDevice(1)}
+	require.NoError(t, f.Init())
+	defer f.Shutdown()
+
+	_, _, _, _, err := f.DeviceGetRemappedRows(Handle(0))
+	require.Equal(t, nvml.ErrNotSupported, err)
+
+	_, err = f.DeviceGetRetiredPages(Handle(1), nvml.PageRetirementCauseDoubleBitECCError)
+	require.Equal(t, nvml.ErrNotSupported, err)
+
+	f.Devices[1].RemappedRows.Correctable = 4
+	f.Devices[1].RemappedRows.Pending = true
+
+	correctable, uncorrectable, pending, failed, err := f.DeviceGetRemappedRows(Handle(1))
+	require.NoError(t, err)
+	require.Equal(t, uint32(4), correctable)
+	require.Zero(t, uncorrectable)
+	require.True(t, pending)
+	require.False(t, failed)
+
+	histogram, err := f.DeviceGetRowRemapperHistogram(Handle(1))
+	require.NoError(t, err)
+	require.Equal(t, uint32(640), histogram.Max)
+}
+
+func TestAllRetiredPages(t *testing.T) {
+	f := create(t, 1)
+	defer f.Shutdown()
+
+	pages, err := f.DeviceGetAllRetiredPages(Handle(0))
+	require.NoError(t, err)
+	require.Empty(t, pages)
+
+	f.Devices[0].RetiredPages[nvml.PageRetirementCauseMultipleSingleBitECCErrors] = []uint64{0x1000, 0x3000}
+	f.Devices[0].RetiredPages[nvml.PageRetirementCauseDoubleBitECCError] = []uint64{0x2000}
+	f.Devices[0].RetiredPageTimes = map[uint64]uint64{0x1000: 300, 0x2000: 100, 0x3000: 200}
+
+	pages, err = f.DeviceGetAllRetiredPages(Handle(0))
+	require.NoError(t, err)
+	require.Equal(t, []nvml.RetiredPage{
+		{Address: 0x2000, Time: 100, Cause: nvml.PageRetirementCauseDoubleBitECCError},
+		{Address: 0x3000, Time: 200, Cause: nvml.PageRetirementCauseMultipleSingleBitECCErrors},
+		{Address: 0x1000, Time: 300, Cause: nvml.PageRetirementCauseMultipleSingleBitECCErrors},
+	}, pages)
+}
+
+func TestDrainRemoveDiscover(t *testing.T) {
+	f := create(t, 2)
+	defer f.Shutdown()
+
+	pci := f.Devices[1].PCI
+
+	f.Devices[1].PersistenceMode = true
+	err := f.DeviceModifyDrainState(&pci, true)
+	require.Equal(t, nvml.ErrInUse, err)
+
+	f.Devices[1].PersistenceMode = false
+	err = f.DeviceModifyDrainState(&pci, true)
+	require.NoError(t, err)
+
+	drained, err := f.DeviceQueryDrainState(&pci)
+	require.NoError(t, err)
+	require.True(t, drained)
+
+	err = f.DeviceRemoveGPU(&pci)
+	require.NoError(t, err)
+
+	_, err = f.DeviceGetName(Handle(1))
+	require.Equal(t, nvml.ErrInvalidArgument, err)
+
+	_, err = f.DeviceGetHandleByUUID(f.Devices[1].UUID)
+	require.Equal(t, nvml.ErrNotFound, err)
+
+	err = f.DeviceDiscoverGPUs(&nvml.PCIInfo{})
+	require.NoError(t, err)
+
+	device, err := f.DeviceGetHandleByUUID(f.Devices[1].UUID)
+	require.NoError(t, err)
+	require.Equal(t, Handle(1), device)
+
+	err = f.DeviceModifyDrainState(&nvml.PCIInfo{Bus: 0xff}, true)
+	require.Equal(t, nvml.ErrNotFound, err)
+
+	require.NoError(t, f.DeviceResetGPULockedClocks(Handle(0)))
+}
@@ -0,0 +1,378 @@
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"strings"
+
+	nvml "github.com/mxpv/nvml-go"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Fixture describes a simulated machine. It can be loaded from a JSON or YAML file and turned into a Fake with Build.
+// Properties that are not set in the fixture keep the values assigned by New and NewDevice.
+//
+//	driverVersion: "450.80.02"
+//	devices:
+//	  - uuid: GPU-6e2ea7ac-0000-0000-0000-000000000000
+//	    pci: {busId: "00000000:06:00.0"}
+//	    processes:
+//	      - {pid: 1234, name: python, usedMemory: 1073741824}
+//	  - uuid: GPU-87b6ff2e-0000-0000-0000-000000000001
+//	    pci: {busId: "00000000:07:00.0"}
+//	topology:
+//	  - [X, PIX]
+//	  - [PIX, X]
+type Fixture struct {
+	DriverVersion     string          `json:"driverVersion" yaml:"driverVersion"`
+	NVMLVersion       string          `json:"nvmlVersion" yaml:"nvmlVersion"`
+	CUDADriverVersion int32           `json:"cudaDriverVersion" yaml:"cudaDriverVersion"`
+	Devices           []DeviceFixture `json:"devices" yaml:"devices"`
+	// Topology[i][j] is the common ancestor of devices i and j, either as a nvidia-smi topo abbreviation
+	// (X, PIX, PXB, PHB, NODE, SYS) or as a level name (internal, single, multiple, hostbridge, node, system).
+	Topology [][]string `json:"topology" yaml:"topology"`
+}
+
+// DeviceFixture describes a single simulated GPU of a Fixture.
+type DeviceFixture struct {
+	Name   string `json:"name" yaml:"name"`
+	UUID   string `json:"uuid" yaml:"uuid"`
+	Serial string `json:"serial" yaml:"serial"`
+
+	PCI    *PCIFixture    `json:"pci" yaml:"pci"`
+	Memory *MemoryFixture `json:"memory" yaml:"memory"`
+
+	// Current and max clocks in MHz by domain (graphics, sm, memory, video)
+	Clocks    map[string]uint32 `json:"clocks" yaml:"clocks"`
+	MaxClocks map[string]uint32 `json:"maxClocks" yaml:"maxClocks"`
+
+	Processes []ProcessFixture `json:"processes" yaml:"processes"`
+	ECCErrors []ECCFixture     `json:"eccErrors" yaml:"eccErrors"`
+}
+
+// PCIFixture describes the PCI attributes of a simulated GPU.
+type PCIFixture struct {
+	// Bus ID in "domain:bus:device.function" format, the domain may have either 4 or 8 hex digits
+	BusID          string `json:"busId" yaml:"busId"`
+	PCIDeviceID    uint32 `json:"deviceId" yaml:"deviceId"`
+	PCISubsystemID uint32 `json:"subsystemId" yaml:"subsystemId"`
+}
+
+// MemoryFixture describes the FB memory of a simulated GPU, in bytes. Free memory is derived from Total and Used.
+type MemoryFixture struct {
+	Total uint64 `json:"total" yaml:"total"`
+	Used  uint64 `json:"used" yaml:"used"`
+}
+
+// ProcessFixture describes a process running on a simulated GPU.
+type ProcessFixture struct {
+	PID        uint32 `json:"pid" yaml:"pid"`
+	Name       string `json:"name" yaml:"name"`
+	UsedMemory uint64 `json:"usedMemory" yaml:"usedMemory"`
+	// Either compute (default) or graphics
+	Type string `json:"type" yaml:"type"`
+}
+
+// ECCFixture describes a memory error counter of a simulated GPU.
+type ECCFixture struct {
+	// Either corrected or uncorrected
+	Type string `json:"type" yaml:"type"`
+	// Either volatile or aggregate
+	Counter string `json:"counter" yaml:"counter"`
+	// One of l1_cache, l2_cache, device_memory, register_file, texture_memory, texture_shm, cbu
+	Location string `json:"location" yaml:"location"`
+	Count    uint64 `json:"count" yaml:"count"`
+}
+
+var (
+	clockTypes = map[string]nvml.ClockType{
+		"graphics": nvml.ClockGraphics,
+		"sm":       nvml.ClockSM,
+		"memory":   nvml.ClockMem,
+		"video":    nvml.ClockVideo,
+	}
+
+	topologyLevels = map[string]nvml.GPUTopologyLevel{
+		"x":          nvml.TopologyInternal,
+		"internal":   nvml.TopologyInternal,
+		"pix":        nvml.TopologySingle,
+		"single":     nvml.TopologySingle,
+		"pxb":        nvml.TopologyMultiple,
+		"multiple":   nvml.TopologyMultiple,
+		"phb":        nvml.TopologyHostbridge,
+		"hostbridge": nvml.TopologyHostbridge,
+		"node":       nvml.TopologyNode,
+		"sys":        nvml.TopologySystem,
+		"system":     nvml.TopologySystem,
+	}
+
+	memoryErrorTypes = map[string]nvml.MemoryErrorType{
+		"corrected":   nvml.MemoryErrorTypeCorrected,
+		"uncorrected": nvml.MemoryErrorTypeUncorrected,
+	}
+
+	eccCounterTypes = map[string]nvml.ECCCounterType{
+		"volatile":  nvml.VolatileECC,
+		"aggregate": nvml.AggregateECC,
+	}
+
+	memoryLocations = map[string]nvml.MemoryLocation{
+		"l1_cache":       nvml.MemoryLocationL1Cache,
+		"l2_cache":       nvml.MemoryLocationL2Cache,
+		"device_memory":  nvml.MemoryLocationDeviceMemory,
+		"register_file":  nvml.MemoryLocationRegisterFile,
+		"texture_memory": nvml.MemoryLocationTextureMemory,
+		"texture_shm":    nvml.MemoryLocationTextureSHM,
+		"cbu":            nvml.MemoryLocationCBU,
+	}
+)
+
+// Load reads a fixture file and builds a Fake from it. The format is picked by the file extension (.json, .yaml or .yml).
+func Load(path string) (*Fake, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var f *Fake
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		f, err = LoadJSON(data)
+	case ".yaml", ".yml":
+		f, err = LoadYAML(data)
+	default:
+		return nil, errors.Errorf("unsupported fixture format %q", ext)
+	}
+
+	return f, errors.Wrapf(err, "failed to load fixture %s", path)
+}
+
+// LoadJSON builds a Fake from a JSON encoded Fixture.
+func LoadJSON(data []byte) (*Fake, error) {
+	fixture := &Fixture{}
+	if err := json.Unmarshal(data, fixture); err != nil {
+		return nil, errors.Wrap(err, "failed to decode JSON fixture")
+	}
+
+	return fixture.Build()
+}
+
+// LoadYAML builds a Fake from a YAML encoded Fixture.
+func LoadYAML(data []byte) (*Fake, error) {
+	fixture := &Fixture{}
+	if err := yaml.UnmarshalStrict(data, fixture); err != nil {
+		return nil, errors.Wrap(err, "failed to decode YAML fixture")
+	}
+
+	return fixture.Build()
+}
+
+// Build creates a Fake simulating the machine described by the fixture.
+func (x *Fixture) Build() (*Fake, error) {
+	f := New(len(x.Devices))
+
+	if x.DriverVersion != "" {
+		f.DriverVersion = x.DriverVersion
+	}
+
+	if x.NVMLVersion != "" {
+		f.NVMLVersion = x.NVMLVersion
+	}
+
+	if x.CUDADriverVersion != 0 {
+		f.CUDADriverVersion = x.CUDADriverVersion
+	}
+
+	uuids := map[string]int{}
+	busIDs := map[string]int{}
+
+	for i, dx := range x.Devices {
+		d := f.Devices[i]
+		if err := dx.apply(f, d); err != nil {
+			return nil, errors.Wrapf(err, "device %d", i)
+		}
+
+		if j, ok := uuids[d.UUID]; ok {
+			return nil, errors.Errorf("device %d: UUID %s is already used by device %d", i, d.UUID, j)
+		}
+
+		if j, ok := busIDs[d.PCI.BusID]; ok {
+			return nil, errors.Errorf("device %d: PCI bus ID %s is already used by device %d", i, d.PCI.BusID, j)
+		}
+
+		uuids[d.UUID] = i
+		busIDs[d.PCI.BusID] = i
+	}
+
+	if len(x.Topology) > 0 {
+		topology, err := parseTopology(x.Topology, len(x.Devices))
+		if err != nil {
+			return nil, err
+		}
+
+		f.Topology = topology
+	}
+
+	return f, nil
+}
+
+func (x *DeviceFixture) apply(f *Fake, d *Device) error {
+	if x.Name != "" {
+		d.Name = x.Name
+	}
+
+	if x.UUID != "" {
+		d.UUID = x.UUID
+	}
+
+	if x.Serial != "" {
+		d.Serial = x.Serial
+	}
+
+	if x.PCI != nil {
+		if x.PCI.BusID != "" {
+			domain, bus, device, function, err := parseBusID(x.PCI.BusID)
+			if err != nil {
+				return err
+			}
+
+			d.PCI.Domain = domain
+			d.PCI.Bus = bus
+			d.PCI.Device = device
+			d.PCI.BusID = fmt.Sprintf("%08X:%02X:%02X.%X", domain, bus, device, function)
+			d.PCI.BusIDLegacy = fmt.Sprintf("%04X:%02X:%02X.%X", domain, bus, device, function)
+		}
+
+		if x.PCI.PCIDeviceID != 0 {
+			d.PCI.PCIDeviceID = x.PCI.PCIDeviceID
+		}
+
+		if x.PCI.PCISubsystemID != 0 {
+			d.PCI.PCISubsystemID = x.PCI.PCISubsystemID
+		}
+	}
+
+	if x.Memory != nil {
+		if x.Memory.Used > x.Memory.Total {
+			return errors.Errorf("used memory %d exceeds total memory %d", x.Memory.Used, x.Memory.Total)
+		}
+
+		d.Memory = nvml.Memory{Total: x.Memory.Total, Used: x.Memory.Used, Free: x.Memory.Total - x.Memory.Used}
+	}
+
+	if err := applyClocks(d.Clocks, x.Clocks); err != nil {
+		return err
+	}
+
+	if err := applyClocks(d.MaxClocks, x.MaxClocks); err != nil {
+		return err
+	}
+
+	for _, px := range x.Processes {
+		info := nvml.ProcessInfo{
+			PID:               px.PID,
+			UsedGPUMemory:     px.UsedMemory,
+			GPUInstanceID:     math.MaxUint32,
+			ComputeInstanceID: math.MaxUint32,
+		}
+
+		switch strings.ToLower(px.Type) {
+		case "", "compute":
+			d.ComputeProcesses = append(d.ComputeProcesses, info)
+		case "graphics":
+			d.GraphicsProcesses = append(d.GraphicsProcesses, info)
+		default:
+			return errors.Errorf("unknown process type %q", px.Type)
+		}
+
+		if px.Name != "" {
+			f.ProcessNames[uint(px.PID)] = px.Name
+		}
+	}
+
+	for _, ex := range x.ECCErrors {
+		errorType, ok := memoryErrorTypes[strings.ToLower(ex.Type)]
+		if !ok {
+			return errors.Errorf("unknown memory error type %q", ex.Type)
+		}
+
+		counterType, ok := eccCounterTypes[strings.ToLower(ex.Counter)]
+		if !ok {
+			return errors.Errorf("unknown ECC counter type %q", ex.Counter)
+		}
+
+		location, ok := memoryLocations[strings.ToLower(ex.Location)]
+		if !ok {
+			return errors.Errorf("unknown memory location %q", ex.Location)
+		}
+
+		d.ECCErrors = append(d.ECCErrors, ECCErrorCount{
+			ErrorType:   errorType,
+			CounterType: counterType,
+			Location:    location,
+			Count:       ex.Count,
+		})
+	}
+
+	return nil
+}
+
+func applyClocks(clocks map[nvml.ClockType]uint32, values map[string]uint32) error {
+	for name, value := range values {
+		clockType, ok := clockTypes[strings.ToLower(name)]
+		if !ok {
+			return errors.Errorf("unknown clock type %q", name)
+		}
+
+		clocks[clockType] = value
+	}
+
+	return nil
+}
+
+// parseBusID parses a PCI bus ID in "domain:bus:device.function" format.
+func parseBusID(busID string) (domain, bus, device, function uint32, err error) {
+	n, err := fmt.Sscanf(busID, "%x:%x:%x.%x", &domain, &bus, &device, &function)
+	if err != nil || n != 4 {
+		return 0, 0, 0, 0, errors.Errorf("invalid PCI bus ID %q", busID)
+	}
+
+	return
+}
+
+func parseTopology(matrix [][]string, count int) ([][]nvml.GPUTopologyLevel, error) {
+	if len(matrix) != count {
+		return nil, errors.Errorf("topology has %d rows, expected %d", len(matrix), count)
+	}
+
+	topology := make([][]nvml.GPUTopologyLevel, count)
+	for i, row := range matrix {
+		if len(row) != count {
+			return nil, errors.Errorf("topology row %d has %d columns, expected %d", i, len(row), count)
+		}
+
+		topology[i] = make([]nvml.GPUTopologyLevel, count)
+		for j, name := range row {
+			level, ok := topologyLevels[strings.ToLower(name)]
+			if !ok {
+				return nil, errors.Errorf("unknown topology level %q between devices %d and %d", name, i, j)
+			}
+
+			topology[i][j] = level
+		}
+	}
+
+	for i := range topology {
+		for j := range topology {
+			if topology[i][j] != topology[j][i] {
+				return nil, errors.Errorf("topology is not symmetric between devices %d and %d", i, j)
+			}
+		}
+	}
+
+	return topology, nil
+}
@@ -0,0 +1,140 @@
+package fake
+
+import (
+	"testing"
+
+	nvml "github.com/mxpv/nvml-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadDGX(t *testing.T) {
+	f, err := Load("testdata/dgx1.yaml")
+	require.NoError(t, err)
+
+	var lib nvml.Interface = f
+	require.NoError(t, lib.Init())
+	defer lib.Shutdown()
+
+	count, err := lib.DeviceGetCount()
+	require.NoError(t, err)
+	require.Equal(t, uint32(8), count)
+
+	gpu0, err := lib.DeviceGetHandleByPCIBusID("0000:06:00.0")
+	require.NoError(t, err)
+
+	gpu1, err := lib.DeviceGetHandleByPCIBusID("00000000:07:00.0")
+	require.NoError(t, err)
+
+	gpu2, err := lib.DeviceGetHandleByPCIBusID("00000000:0a:00.0")
+	require.NoError(t, err)
+
+	gpu7, err := lib.DeviceGetHandleByPCIBusID("0000:8A:00.0")
+	require.NoError(t, err)
+
+	index, err := lib.DeviceGetIndex(gpu7)
+	require.NoError(t, err)
+	require.Equal(t, uint32(7), index)
+
+	info, err := lib.DeviceGetPCIInfo(gpu7)
+	require.NoError(t, err)
+	require.Equal(t, uint32(0x8a), info.Bus)
+	require.Equal(t, "0000:8A:00.0", info.BusIDLegacy)
+
+	tests := []struct {
+		a, b  nvml.Device
+		level nvml.GPUTopologyLevel
+	}{
+		{gpu0, gpu0, nvml.TopologyInternal},
+		{gpu0, gpu1, nvml.TopologySingle},
+		{gpu1, gpu2, nvml.TopologyHostbridge},
+		{gpu7, gpu0, nvml.TopologySystem},
+	}
+
+	for _, tt := range tests {
+		level, err := lib.DeviceGetTopologyCommonAncestor(tt.a, tt.b)
+		require.NoError(t, err)
+		require.Equal(t, tt.level, level)
+	}
+
+	processes, err := lib.DeviceGetComputeRunningProcesses(gpu2)
+	require.NoError(t, err)
+	require.Len(t, processes, 1)
+	require.Equal(t, uint32(4242), processes[0].PID)
+	require.Equal(t, uint64(16000<<20), processes[0].UsedGPUMemory)
+
+	name, err := lib.SystemGetProcessName(uint(processes[0].PID))
+	require.NoError(t, err)
+	require.Equal(t, "python", name)
+
+	memory, err := lib.DeviceGetMemoryInfo(gpu2)
+	require.NoError(t, err)
+	require.Equal(t, memory.Total, memory.Free+memory.Used)
+
+	total, err := lib.DeviceGetTotalECCErrors(gpu2, nvml.MemoryErrorTypeCorrected, nvml.VolatileECC)
+	require.NoError(t, err)
+	require.Equal(t, uint64(12), total)
+
+	processes, err = lib.DeviceGetComputeRunningProcesses(gpu0)
+	require.NoError(t, err)
+	require.Empty(t, processes)
+}
+
+func TestLoadJSON(t *testing.T) {
+	f, err := LoadJSON([]byte(`{
+		"driverVersion": "460.32.03",
+		"devices": [
+			{"uuid": "GPU-a", "clocks": {"graphics": 1410}, "processes": [{"pid": 7, "type": "graphics"}]},
+			{"uuid": "GPU-b"}
+		],
+		"topology": [["internal", "node"], ["node", "internal"]]
+	}`))
+	require.NoError(t, err)
+
+	require.NoError(t, f.Init())
+	defer f.Shutdown()
+
+	version, err := f.SystemGetDriverVersion()
+	require.NoError(t, err)
+	require.Equal(t, "460.32.03", version)
+
+	device, err := f.DeviceGetHandleByUUID("GPU-b")
+	require.NoError(t, err)
+	require.Equal(t, Handle(1), device)
+
+	clock, err := f.DeviceGetClockInfo(Handle(0), nvml.ClockGraphics)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1410), clock)
+
+	processes, err := f.DeviceGetGraphicsRunningProcesses(Handle(0))
+	require.NoError(t, err)
+	require.Len(t, processes, 1)
+
+	level, err := f.DeviceGetTopologyCommonAncestor(Handle(0), Handle(1))
+	require.NoError(t, err)
+	require.Equal(t, nvml.TopologyNode, level)
+}
+
+func TestLoadInvalidFixture(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+	}{
+		{"unknown field", `devices: [{uuid: GPU-a, color: green}]`},
+		{"duplicate UUID", `devices: [{uuid: GPU-a}, {uuid: GPU-a}]`},
+		{"duplicate bus ID", `devices: [{pci: {busId: "0000:01:00.0"}}, {pci: {busId: "00000000:01:00.0"}}]`},
+		{"invalid bus ID", `devices: [{pci: {busId: "01:00"}}]`},
+		{"memory", `devices: [{memory: {total: 1, used: 2}}]`},
+		{"clock", `devices: [{clocks: {shader: 100}}]`},
+		{"ECC location", `devices: [{eccErrors: [{type: corrected, counter: volatile, location: disk}]}]`},
+		{"topology size", `{devices: [{}, {}], topology: [[X]]}`},
+		{"topology level", `{devices: [{}], topology: [[NV2]]}`},
+		{"asymmetric topology", `{devices: [{}, {}], topology: [[X, PIX], [SYS, X]]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadYAML([]byte(tt.fixture))
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,852 @@
+package fake
+
+import (
+	"fmt"
+
+	nvml "github.com/mxpv/nvml-go"
+)
+
+// migHandleBase is the first handle given to GPU instances, compute instances and MIG devices, so they never clash
+// with the handles of the devices (see Handle).
+const migHandleBase = 0x10000
+
+// GpuInstanceProfile describes a GPU instance profile supported by a simulated device.
+type GpuInstanceProfile struct {
+	Info nvml.GpuInstanceProfileInfo
+	// Placements reported by DeviceGetGpuInstancePossiblePlacements
+	Placements []nvml.GpuInstancePlacement
+}
+
+// GpuInstance is a GPU instance created on a simulated device.
+type GpuInstance struct {
+	Handle           nvml.GpuInstance
+	Info             nvml.GpuInstanceInfo
+	ComputeInstances []*ComputeInstance
+}
+
+// ComputeInstance is a compute instance created within a GpuInstance.
+type ComputeInstance struct {
+	Handle nvml.ComputeInstance
+	Info   nvml.ComputeInstanceInfo
+	// MIG device backing the compute instance. Its handle is the one of the compute instance.
+	Device *Device
+}
+
+// NewMigDevice returns a Device resembling an A100-SXM4-40GB, with MIG disabled.
+// Identifiers (serial, UUID, PCI bus ID) are derived from index.
+func NewMigDevice(index int) *Device {
+	d := NewDevice(index)
+	d.Name = "A100-SXM4-40GB"
+	d.Serial = fmt.Sprintf("1562520%06d", index)
+	d.BoardPartNumber = "692-2G506-0200-002"
+	d.PCI.PCIDeviceID = 0x20b010de
+	d.PCI.PCISubsystemID = 0x134f10de
+	d.PCIeLinkGeneration = 4
+	d.MaxPCIeLinkGeneration = 4
+	d.ComputeCapabilityMajor = 8
+	d.Memory = nvml.Memory{Total: 40 << 30, Free: 40<<30 - 400<<20, Used: 400 << 20}
+	d.RetiredPages = nil
+	d.RemappedRows = &RemappedRows{Histogram: nvml.RowRemapperHistogram{Max: 640}}
+	d.MigMode = nvml.MigModeDisabled
+	d.PendingMigMode = nvml.MigModeDisabled
+	d.GpuInstanceProfiles = map[nvml.GpuInstanceProfile]*GpuInstanceProfile{
+		nvml.GpuInstanceProfile1Slice: {
+			Info:       gpuInstanceProfileInfo(19, 1, 7, 0, 4864),
+			Placements: placements(1, 0, 1, 2, 3, 4, 5, 6),
+		},
+		nvml.GpuInstanceProfile2Slice: {
+			Info:       gpuInstanceProfileInfo(14, 2, 3, 1, 9856),
+			Placements: placements(2, 0, 2, 4),
+		},
+		nvml.GpuInstanceProfile3Slice: {
+			Info:       gpuInstanceProfileInfo(9, 3, 2, 2, 19968),
+			Placements: placements(4, 0, 4),
+		},
+		nvml.GpuInstanceProfile4Slice: {
+			Info:       gpuInstanceProfileInfo(5, 4, 1, 2, 19968),
+			Placements: placements(4, 0),
+		},
+		nvml.GpuInstanceProfile7Slice: {
+			Info:       gpuInstanceProfileInfo(0, 7, 1, 5, 40192),
+			Placements: placements(8, 0),
+		},
+	}
+
+	return d
+}
+
+func gpuInstanceProfileInfo(id, slices, count, decoders uint32, memoryMB uint64) nvml.GpuInstanceProfileInfo {
+	info := nvml.GpuInstanceProfileInfo{
+		ID:                  id,
+		SliceCount:          slices,
+		InstanceCount:       count,
+		MultiprocessorCount: 14 * slices,
+		CopyEngineCount:     slices,
+		DecoderCount:        decoders,
+		MemorySizeMB:        memoryMB,
+	}
+
+	if slices == 7 {
+		info.JPEGCount = 1
+		info.OFACount = 1
+	}
+
+	return info
+}
+
+// placements returns placements of the given size (in memory slices) starting at each of starts.
+func placements(size uint32, starts ...uint32) []nvml.GpuInstancePlacement {
+	list := make([]nvml.GpuInstancePlacement, len(starts))
+	for i, start := range starts {
+		list[i] = nvml.GpuInstancePlacement{Start: start, Size: size}
+	}
+
+	return list
+}
+
+// computeInstanceProfiles lists the compute instance profiles and their slice count.
+var computeInstanceProfiles = []struct {
+	profile nvml.ComputeInstanceProfile
+	slices  uint32
+}{
+	{nvml.ComputeInstanceProfile1Slice, 1},
+	{nvml.ComputeInstanceProfile2Slice, 2},
+	{nvml.ComputeInstanceProfile3Slice, 3},
+	{nvml.ComputeInstanceProfile4Slice, 4},
+	{nvml.ComputeInstanceProfile7Slice, 7},
+}
+
+// computeInstanceProfileInfo returns the given compute instance profile within gi. Must be called with mu held.
+func (d *Device) computeInstanceProfileInfo(gi *GpuInstance, profile nvml.ComputeInstanceProfile) (nvml.ComputeInstanceProfileInfo, bool) {
+	parent, ok := d.gpuInstanceProfile(gi.Info.ProfileID)
+	if !ok {
+		return nvml.ComputeInstanceProfileInfo{}, false
+	}
+
+	for _, p := range computeInstanceProfiles {
+		if p.profile != profile || p.slices > parent.Info.SliceCount {
+			continue
+		}
+
+		return nvml.ComputeInstanceProfileInfo{
+			ID:                    uint32(profile),
+			SliceCount:            p.slices,
+			InstanceCount:         parent.Info.SliceCount / p.slices,
+			MultiprocessorCount:   14 * p.slices,
+			SharedCopyEngineCount: parent.Info.CopyEngineCount,
+			SharedDecoderCount:    parent.Info.DecoderCount,
+			SharedEncoderCount:    parent.Info.EncoderCount,
+			SharedJPEGCount:       parent.Info.JPEGCount,
+			SharedOFACount:        parent.Info.OFACount,
+		}, true
+	}
+
+	return nvml.ComputeInstanceProfileInfo{}, false
+}
+
+// gpuInstanceProfile returns the GPU instance profile with the given ID.
+func (d *Device) gpuInstanceProfile(id uint32) (*GpuInstanceProfile, bool) {
+	for _, p := range d.GpuInstanceProfiles {
+		if p.Info.ID == id {
+			return p, true
+		}
+	}
+
+	return nil, false
+}
+
+// freeGpuInstancePlacements returns the placements of profile that could be used together, without overlapping
+// the existing GPU instances.
+func (d *Device) freeGpuInstancePlacements(profile *GpuInstanceProfile) []nvml.GpuInstancePlacement {
+	var used []nvml.GpuInstancePlacement
+	for _, gi := range d.GpuInstances {
+		used = append(used, gi.Info.Placement)
+	}
+
+	var free []nvml.GpuInstancePlacement
+	for _, p := range profile.Placements {
+		if !overlaps(p.Start, p.Size, used) {
+			free = append(free, p)
+			used = append(used, p)
+		}
+	}
+
+	return free
+}
+
+// freeComputeInstancePlacements returns the placements of info that could be used together within gi, without
+// overlapping its existing compute instances.
+func freeComputeInstancePlacements(gi *GpuInstance, info nvml.ComputeInstanceProfileInfo) []nvml.ComputeInstancePlacement {
+	var used []nvml.GpuInstancePlacement
+	for _, ci := range gi.ComputeInstances {
+		used = append(used, nvml.GpuInstancePlacement(ci.Info.Placement))
+	}
+
+	var free []nvml.ComputeInstancePlacement
+	for start := uint32(0); start+info.SliceCount <= info.SliceCount*info.InstanceCount; start += info.SliceCount {
+		if !overlaps(start, info.SliceCount, used) {
+			free = append(free, nvml.ComputeInstancePlacement{Start: start, Size: info.SliceCount})
+			used = append(used, nvml.GpuInstancePlacement{Start: start, Size: info.SliceCount})
+		}
+	}
+
+	return free
+}
+
+func overlaps(start, size uint32, used []nvml.GpuInstancePlacement) bool {
+	for _, u := range used {
+		if start < u.Start+u.Size && u.Start < start+size {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newHandle returns a handle not used by any other object. Must be called with mu held.
+func (f *Fake) newHandle() uintptr {
+	if f.nextHandle == 0 {
+		f.nextHandle = migHandleBase
+	}
+
+	f.nextHandle++
+	return f.nextHandle
+}
+
+// computeInstance returns the compute instance with the given handle along with its parents, nil if there's none.
+// Must be called with mu held.
+func (f *Fake) computeInstance(handle nvml.ComputeInstance) (*Device, *GpuInstance, *ComputeInstance) {
+	for _, d := range f.Devices {
+		for _, gi := range d.GpuInstances {
+			for _, ci := range gi.ComputeInstances {
+				if ci.Handle == handle {
+					return d, gi, ci
+				}
+			}
+		}
+	}
+
+	return nil, nil, nil
+}
+
+// lookupMig returns the device referred by handle if it's in MIG mode, or the error method should report.
+// Must be called with mu held.
+func (f *Fake) lookupMig(method string, handle nvml.Device) (*Device, error) {
+	d, err := f.lookup(method, handle)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(d.GpuInstanceProfiles) == 0 || d.MigMode != nvml.MigModeEnabled {
+		return nil, nvml.ErrNotSupported
+	}
+
+	return d, nil
+}
+
+// lookupGpuInstance returns the GPU instance referred by handle along with its device, or the error method should
+// report. Must be called with mu held.
+func (f *Fake) lookupGpuInstance(method string, handle nvml.GpuInstance) (*Device, *GpuInstance, error) {
+	if err := f.check(method); err != nil {
+		return nil, nil, err
+	}
+
+	for _, d := range f.Devices {
+		for _, gi := range d.GpuInstances {
+			if gi.Handle != handle {
+				continue
+			}
+
+			if err := d.Errors[method]; err != nil {
+				return nil, nil, err
+			}
+
+			return d, gi, nil
+		}
+	}
+
+	return nil, nil, nvml.ErrInvalidArgument
+}
+
+// lookupComputeInstance returns the compute instance referred by handle along with its parents, or the error method
+// should report. Must be called with mu held.
+func (f *Fake) lookupComputeInstance(method string, handle nvml.ComputeInstance) (*GpuInstance, *ComputeInstance, error) {
+	if err := f.check(method); err != nil {
+		return nil, nil, err
+	}
+
+	d, gi, ci := f.computeInstance(handle)
+	if ci == nil {
+		return nil, nil, nvml.ErrInvalidArgument
+	}
+
+	if err := d.Errors[method]; err != nil {
+		return nil, nil, err
+	}
+
+	return gi, ci, nil
+}
+
+// DeviceGetMigMode returns MigMode and PendingMigMode. MIG is not supported when GpuInstanceProfiles is empty.
+func (f *Fake) DeviceGetMigMode(device nvml.Device) (nvml.MigMode, nvml.MigMode, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetMigMode", device)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if len(d.GpuInstanceProfiles) == 0 {
+		return 0, 0, nvml.ErrNotSupported
+	}
+
+	return d.MigMode, d.PendingMigMode, nil
+}
+
+// DeviceSetMigMode updates PendingMigMode, and MigMode unless the device is in use. The device is in use while it
+// runs compute processes or has GPU instances, in which case nvml.ErrInUse is returned.
+func (f *Fake) DeviceSetMigMode(device nvml.Device, mode nvml.MigMode) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceSetMigMode", device)
+	if err != nil {
+		return err
+	}
+
+	if len(d.GpuInstanceProfiles) == 0 {
+		return nvml.ErrNotSupported
+	}
+
+	if mode != nvml.MigModeDisabled && mode != nvml.MigModeEnabled {
+		return nvml.ErrInvalidArgument
+	}
+
+	d.PendingMigMode = mode
+	if mode != d.MigMode && (len(d.ComputeProcesses) > 0 || len(d.GpuInstances) > 0) {
+		return nvml.ErrInUse
+	}
+
+	d.MigMode = mode
+	return nil
+}
+
+// DeviceGetGpuInstanceProfileInfo returns the Info of the given GpuInstanceProfiles entry.
+func (f *Fake) DeviceGetGpuInstanceProfileInfo(device nvml.Device, profile nvml.GpuInstanceProfile) (nvml.GpuInstanceProfileInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookupMig("DeviceGetGpuInstanceProfileInfo", device)
+	if err != nil {
+		return nvml.GpuInstanceProfileInfo{}, err
+	}
+
+	p, ok := d.GpuInstanceProfiles[profile]
+	if !ok {
+		return nvml.GpuInstanceProfileInfo{}, nvml.ErrNotSupported
+	}
+
+	return p.Info, nil
+}
+
+// DeviceGetGpuInstancePossiblePlacements returns a copy of the placements of the given profile.
+func (f *Fake) DeviceGetGpuInstancePossiblePlacements(device nvml.Device, profileID uint32) ([]nvml.GpuInstancePlacement, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookupMig("DeviceGetGpuInstancePossiblePlacements", device)
+	if err != nil {
+		return nil, err
+	}
+
+	p, ok := d.gpuInstanceProfile(profileID)
+	if !ok {
+		return nil, nvml.ErrInvalidArgument
+	}
+
+	return append([]nvml.GpuInstancePlacement{}, p.Placements...), nil
+}
+
+// DeviceGetGpuInstanceRemainingCapacity returns how many more GPU instances of the given profile fit on the device.
+func (f *Fake) DeviceGetGpuInstanceRemainingCapacity(device nvml.Device, profileID uint32) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookupMig("DeviceGetGpuInstanceRemainingCapacity", device)
+	if err != nil {
+		return 0, err
+	}
+
+	p, ok := d.gpuInstanceProfile(profileID)
+	if !ok {
+		return 0, nvml.ErrInvalidArgument
+	}
+
+	return uint32(len(d.freeGpuInstancePlacements(p))), nil
+}
+
+// DeviceCreateGpuInstance creates a GPU instance of the given profile at the first free placement.
+func (f *Fake) DeviceCreateGpuInstance(device nvml.Device, profileID uint32) (nvml.GpuInstance, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookupMig("DeviceCreateGpuInstance", device)
+	if err != nil {
+		return 0, err
+	}
+
+	p, ok := d.gpuInstanceProfile(profileID)
+	if !ok {
+		return 0, nvml.ErrInvalidArgument
+	}
+
+	free := d.freeGpuInstancePlacements(p)
+	if len(free) == 0 {
+		return 0, nvml.ErrInsufficientResources
+	}
+
+	return f.createGpuInstance(device, d, p, free[0]), nil
+}
+
+// DeviceCreateGpuInstanceWithPlacement creates a GPU instance of the given profile at the given placement.
+// The placement must be one of the profile placements and must not overlap existing GPU instances.
+func (f *Fake) DeviceCreateGpuInstanceWithPlacement(device nvml.Device, profileID uint32, placement nvml.GpuInstancePlacement) (nvml.GpuInstance, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookupMig("DeviceCreateGpuInstanceWithPlacement", device)
+	if err != nil {
+		return 0, err
+	}
+
+	p, ok := d.gpuInstanceProfile(profileID)
+	if !ok {
+		return 0, nvml.ErrInvalidArgument
+	}
+
+	valid := false
+	for _, possible := range p.Placements {
+		valid = valid || possible == placement
+	}
+
+	if !valid {
+		return 0, nvml.ErrInvalidArgument
+	}
+
+	for _, gi := range d.GpuInstances {
+		if overlaps(placement.Start, placement.Size, []nvml.GpuInstancePlacement{gi.Info.Placement}) {
+			return 0, nvml.ErrInsufficientResources
+		}
+	}
+
+	return f.createGpuInstance(device, d, p, placement), nil
+}
+
+// createGpuInstance adds a GPU instance to d. Must be called with mu held.
+func (f *Fake) createGpuInstance(device nvml.Device, d *Device, p *GpuInstanceProfile, placement nvml.GpuInstancePlacement) nvml.GpuInstance {
+	// GPU instance IDs are the lowest ones not in use, starting at 1
+	id := uint32(1)
+	for used := true; used; {
+		used = false
+		for _, gi := range d.GpuInstances {
+			if gi.Info.ID == id {
+				used = true
+				id++
+			}
+		}
+	}
+
+	gi := &GpuInstance{
+		Handle: nvml.GpuInstance(f.newHandle()),
+		Info: nvml.GpuInstanceInfo{
+			Device:    device,
+			ID:        id,
+			ProfileID: p.Info.ID,
+			Placement: placement,
+		},
+	}
+
+	d.GpuInstances = append(d.GpuInstances, gi)
+	return gi.Handle
+}
+
+// GpuInstanceDestroy removes the GPU instance from its device. Fails with nvml.ErrInUse while it has compute instances.
+func (f *Fake) GpuInstanceDestroy(gpuInstance nvml.GpuInstance) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, gi, err := f.lookupGpuInstance("GpuInstanceDestroy", gpuInstance)
+	if err != nil {
+		return err
+	}
+
+	if len(gi.ComputeInstances) > 0 {
+		return nvml.ErrInUse
+	}
+
+	for i := range d.GpuInstances {
+		if d.GpuInstances[i] == gi {
+			d.GpuInstances = append(d.GpuInstances[:i], d.GpuInstances[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+// DeviceGetGpuInstances returns the handles of the GPU instances of the given profile.
+func (f *Fake) DeviceGetGpuInstances(device nvml.Device, info nvml.GpuInstanceProfileInfo) ([]nvml.GpuInstance, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookupMig("DeviceGetGpuInstances", device)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := d.gpuInstanceProfile(info.ID); !ok {
+		return nil, nvml.ErrInvalidArgument
+	}
+
+	list := []nvml.GpuInstance{}
+	for _, gi := range d.GpuInstances {
+		if gi.Info.ProfileID == info.ID {
+			list = append(list, gi.Handle)
+		}
+	}
+
+	if uint32(len(list)) > info.InstanceCount {
+		return nil, nvml.ErrInsufficientSize
+	}
+
+	return list, nil
+}
+
+// DeviceGetGpuInstanceByID returns the handle of the GPU instance with the given ID.
+func (f *Fake) DeviceGetGpuInstanceByID(device nvml.Device, id uint32) (nvml.GpuInstance, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookupMig("DeviceGetGpuInstanceByID", device)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, gi := range d.GpuInstances {
+		if gi.Info.ID == id {
+			return gi.Handle, nil
+		}
+	}
+
+	return 0, nvml.ErrNotFound
+}
+
+// GpuInstanceGetInfo returns GpuInstance.Info.
+func (f *Fake) GpuInstanceGetInfo(gpuInstance nvml.GpuInstance) (nvml.GpuInstanceInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, gi, err := f.lookupGpuInstance("GpuInstanceGetInfo", gpuInstance)
+	if err != nil {
+		return nvml.GpuInstanceInfo{}, err
+	}
+
+	return gi.Info, nil
+}
+
+// GpuInstanceGetComputeInstanceProfileInfo returns the given compute instance profile, available when it doesn't
+// span more slices than the GPU instance. Only the shared engine profile is supported.
+func (f *Fake) GpuInstanceGetComputeInstanceProfileInfo(gpuInstance nvml.GpuInstance, profile nvml.ComputeInstanceProfile, engProfile nvml.ComputeInstanceEngineProfile) (nvml.ComputeInstanceProfileInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, gi, err := f.lookupGpuInstance("GpuInstanceGetComputeInstanceProfileInfo", gpuInstance)
+	if err != nil {
+		return nvml.ComputeInstanceProfileInfo{}, err
+	}
+
+	if engProfile != nvml.ComputeInstanceEngineProfileShared {
+		return nvml.ComputeInstanceProfileInfo{}, nvml.ErrNotSupported
+	}
+
+	info, ok := d.computeInstanceProfileInfo(gi, profile)
+	if !ok {
+		return nvml.ComputeInstanceProfileInfo{}, nvml.ErrNotSupported
+	}
+
+	return info, nil
+}
+
+// lookupComputeInstanceProfile returns the compute instance profile with the given ID within gi, or
+// nvml.ErrInvalidArgument. Must be called with mu held.
+func (d *Device) lookupComputeInstanceProfile(gi *GpuInstance, profileID uint32) (nvml.ComputeInstanceProfileInfo, error) {
+	info, ok := d.computeInstanceProfileInfo(gi, nvml.ComputeInstanceProfile(profileID))
+	if !ok {
+		return nvml.ComputeInstanceProfileInfo{}, nvml.ErrInvalidArgument
+	}
+
+	return info, nil
+}
+
+// GpuInstanceGetComputeInstanceRemainingCapacity returns how many more compute instances of the given profile fit
+// in the GPU instance.
+func (f *Fake) GpuInstanceGetComputeInstanceRemainingCapacity(gpuInstance nvml.GpuInstance, profileID uint32) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, gi, err := f.lookupGpuInstance("GpuInstanceGetComputeInstanceRemainingCapacity", gpuInstance)
+	if err != nil {
+		return 0, err
+	}
+
+	info, err := d.lookupComputeInstanceProfile(gi, profileID)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint32(len(freeComputeInstancePlacements(gi, info))), nil
+}
+
+// GpuInstanceCreateComputeInstance creates a compute instance of the given profile at the first free placement.
+// The compute instance is backed by a MIG device with the memory of the GPU instance.
+func (f *Fake) GpuInstanceCreateComputeInstance(gpuInstance nvml.GpuInstance, profileID uint32) (nvml.ComputeInstance, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, gi, err := f.lookupGpuInstance("GpuInstanceCreateComputeInstance", gpuInstance)
+	if err != nil {
+		return 0, err
+	}
+
+	info, err := d.lookupComputeInstanceProfile(gi, profileID)
+	if err != nil {
+		return 0, err
+	}
+
+	free := freeComputeInstancePlacements(gi, info)
+	if len(free) == 0 {
+		return 0, nvml.ErrInsufficientResources
+	}
+
+	// Compute instance IDs are the lowest ones not in use within the GPU instance, starting at 0
+	id := uint32(0)
+	for used := true; used; {
+		used = false
+		for _, ci := range gi.ComputeInstances {
+			if ci.Info.ID == id {
+				used = true
+				id++
+			}
+		}
+	}
+
+	parent, _ := d.gpuInstanceProfile(gi.Info.ProfileID)
+	memory := parent.Info.MemorySizeMB << 20
+	handle := f.newHandle()
+
+	ci := &ComputeInstance{
+		Handle: nvml.ComputeInstance(handle),
+		Info: nvml.ComputeInstanceInfo{
+			Device:      gi.Info.Device,
+			GpuInstance: gi.Handle,
+			ID:          id,
+			ProfileID:   info.ID,
+			Placement:   free[0],
+		},
+		Device: &Device{
+			Name:   d.Name,
+			UUID:   fmt.Sprintf("MIG-%08x-%04x-%04x-0000-%012x", handle, gi.Info.ID, id, handle),
+			Memory: nvml.Memory{Total: memory, Free: memory},
+			Errors: map[string]error{},
+		},
+	}
+
+	gi.ComputeInstances = append(gi.ComputeInstances, ci)
+	return ci.Handle, nil
+}
+
+// ComputeInstanceDestroy removes the compute instance from its GPU instance.
+func (f *Fake) ComputeInstanceDestroy(computeInstance nvml.ComputeInstance) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	gi, ci, err := f.lookupComputeInstance("ComputeInstanceDestroy", computeInstance)
+	if err != nil {
+		return err
+	}
+
+	for i := range gi.ComputeInstances {
+		if gi.ComputeInstances[i] == ci {
+			gi.ComputeInstances = append(gi.ComputeInstances[:i], gi.ComputeInstances[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+// GpuInstanceGetComputeInstances returns the handles of the compute instances of the given profile.
+func (f *Fake) GpuInstanceGetComputeInstances(gpuInstance nvml.GpuInstance, info nvml.ComputeInstanceProfileInfo) ([]nvml.ComputeInstance, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, gi, err := f.lookupGpuInstance("GpuInstanceGetComputeInstances", gpuInstance)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := d.lookupComputeInstanceProfile(gi, info.ID); err != nil {
+		return nil, err
+	}
+
+	list := []nvml.ComputeInstance{}
+	for _, ci := range gi.ComputeInstances {
+		if ci.Info.ProfileID == info.ID {
+			list = append(list, ci.Handle)
+		}
+	}
+
+	if uint32(len(list)) > info.InstanceCount {
+		return nil, nvml.ErrInsufficientSize
+	}
+
+	return list, nil
+}
+
+// GpuInstanceGetComputeInstanceByID returns the handle of the compute instance with the given ID.
+func (f *Fake) GpuInstanceGetComputeInstanceByID(gpuInstance nvml.GpuInstance, id uint32) (nvml.ComputeInstance, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, gi, err := f.lookupGpuInstance("GpuInstanceGetComputeInstanceByID", gpuInstance)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, ci := range gi.ComputeInstances {
+		if ci.Info.ID == id {
+			return ci.Handle, nil
+		}
+	}
+
+	return 0, nvml.ErrNotFound
+}
+
+// ComputeInstanceGetInfo returns ComputeInstance.Info.
+func (f *Fake) ComputeInstanceGetInfo(computeInstance nvml.ComputeInstance) (nvml.ComputeInstanceInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, ci, err := f.lookupComputeInstance("ComputeInstanceGetInfo", computeInstance)
+	if err != nil {
+		return nvml.ComputeInstanceInfo{}, err
+	}
+
+	return ci.Info, nil
+}
+
+// DeviceIsMigDeviceHandle reports whether the handle refers to the MIG device of a compute instance.
+func (f *Fake) DeviceIsMigDeviceHandle(device nvml.Device) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.lookup("DeviceIsMigDeviceHandle", device); err != nil {
+		return false, err
+	}
+
+	_, _, ci := f.computeInstance(nvml.ComputeInstance(device))
+	return ci != nil, nil
+}
+
+// DeviceGetGpuInstanceID returns the ID of the GPU instance of a MIG device.
+func (f *Fake) DeviceGetGpuInstanceID(device nvml.Device) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.lookup("DeviceGetGpuInstanceID", device); err != nil {
+		return 0, err
+	}
+
+	_, gi, ci := f.computeInstance(nvml.ComputeInstance(device))
+	if ci == nil {
+		return 0, nvml.ErrNotSupported
+	}
+
+	return gi.Info.ID, nil
+}
+
+// DeviceGetComputeInstanceID returns the ID of the compute instance of a MIG device.
+func (f *Fake) DeviceGetComputeInstanceID(device nvml.Device) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.lookup("DeviceGetComputeInstanceID", device); err != nil {
+		return 0, err
+	}
+
+	_, _, ci := f.computeInstance(nvml.ComputeInstance(device))
+	if ci == nil {
+		return 0, nvml.ErrNotSupported
+	}
+
+	return ci.Info.ID, nil
+}
+
+// DeviceGetMaxMigDeviceCount returns the number of 1 slice GPU instances the device can hold, 0 when MIG is disabled.
+func (f *Fake) DeviceGetMaxMigDeviceCount(device nvml.Device) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookup("DeviceGetMaxMigDeviceCount", device)
+	if err != nil {
+		return 0, err
+	}
+
+	p, ok := d.GpuInstanceProfiles[nvml.GpuInstanceProfile1Slice]
+	if !ok || d.MigMode != nvml.MigModeEnabled {
+		return 0, nil
+	}
+
+	return p.Info.InstanceCount, nil
+}
+
+// DeviceGetMigDeviceHandleByIndex returns the handle of the MIG device with the given index, counting the compute
+// instances of every GPU instance in creation order.
+func (f *Fake) DeviceGetMigDeviceHandleByIndex(device nvml.Device, index uint32) (nvml.Device, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	d, err := f.lookupMig("DeviceGetMigDeviceHandleByIndex", device)
+	if err != nil {
+		return 0, err
+	}
+
+	n := index
+	for _, gi := range d.GpuInstances {
+		if n < uint32(len(gi.ComputeInstances)) {
+			return nvml.Device(gi.ComputeInstances[n].Handle), nil
+		}
+
+		n -= uint32(len(gi.ComputeInstances))
+	}
+
+	return 0, nvml.ErrNotFound
+}
+
+// DeviceGetDeviceHandleFromMigDeviceHandle returns the handle of the device a MIG device belongs to.
+func (f *Fake) DeviceGetDeviceHandleFromMigDeviceHandle(migDevice nvml.Device) (nvml.Device, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.lookup("DeviceGetDeviceHandleFromMigDeviceHandle", migDevice); err != nil {
+		return 0, err
+	}
+
+	_, _, ci := f.computeInstance(nvml.ComputeInstance(migDevice))
+	if ci == nil {
+		return 0, nvml.ErrInvalidArgument
+	}
+
+	return ci.Info.Device, nil
+}
@@ -0,0 +1,221 @@
+package fake
+
+import (
+	"strings"
+	"testing"
+
+	nvml "github.com/mxpv/nvml-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigMode(t *testing.T) {
+	f := create(t, 1)
+	defer f.Shutdown()
+
+	_, _, err := f.DeviceGetMigMode(Handle(0))
+	require.Equal(t, nvml.ErrNotSupported, err)
+
+	f.Devices[0] = NewMigDevice(0)
+
+	current, pending, err := f.DeviceGetMigMode(Handle(0))
+	require.NoError(t, err)
+	require.Equal(t, nvml.MigModeDisabled, current)
+	require.Equal(t, nvml.MigModeDisabled, pending)
+
+	_, err = f.DeviceGetGpuInstanceProfileInfo(Handle(0), nvml.GpuInstanceProfile1Slice)
+	require.Equal(t, nvml.ErrNotSupported, err)
+
+	count, err := f.DeviceGetMaxMigDeviceCount(Handle(0))
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	f.Devices[0].ComputeProcesses = []nvml.ProcessInfo{{PID: 1}}
+	err = f.DeviceSetMigMode(Handle(0), nvml.MigModeEnabled)
+	require.Equal(t, nvml.ErrInUse, err)
+
+	current, pending, err = f.DeviceGetMigMode(Handle(0))
+	require.NoError(t, err)
+	require.Equal(t, nvml.MigModeDisabled, current)
+	require.Equal(t, nvml.MigModeEnabled, pending)
+
+	f.Devices[0].ComputeProcesses = nil
+	err = f.DeviceSetMigMode(Handle(0), nvml.MigModeEnabled)
+	require.NoError(t, err)
+
+	count, err = f.DeviceGetMaxMigDeviceCount(Handle(0))
+	require.NoError(t, err)
+	require.Equal(t, uint32(7), count)
+}
+
+func TestGpuInstances(t *testing.T) {
+	f := create(t, 1)
+	defer f.Shutdown()
+
+	f.Devices[0] = NewMigDevice(0)
+	f.Devices[0].MigMode = nvml.MigModeEnabled
+
+	half, err := f.DeviceGetGpuInstanceProfileInfo(Handle(0), nvml.GpuInstanceProfile3Slice)
+	require.NoError(t, err)
+	require.Equal(t, uint32(9), half.ID)
+
+	placements, err := f.DeviceGetGpuInstancePossiblePlacements(Handle(0), half.ID)
+	require.NoError(t, err)
+	require.Equal(t, []nvml.GpuInstancePlacement{{Start: 0, Size: 4}, {Start: 4, Size: 4}}, placements)
+
+	gi, err := f.DeviceCreateGpuInstanceWithPlacement(Handle(0), half.ID, placements[1])
+	require.NoError(t, err)
+
+	_, err = f.DeviceCreateGpuInstanceWithPlacement(Handle(0), half.ID, placements[1])
+	require.Equal(t, nvml.ErrInsufficientResources, err)
+
+	_, err = f.DeviceCreateGpuInstanceWithPlacement(Handle(0), half.ID, nvml.GpuInstancePlacement{Start: 1, Size: 4})
+	require.Equal(t, nvml.ErrInvalidArgument, err)
+
+	capacity, err := f.DeviceGetGpuInstanceRemainingCapacity(Handle(0), 19)
+	require.NoError(t, err)
+	require.Equal(t, uint32(4), capacity)
+
+	capacity, err = f.DeviceGetGpuInstanceRemainingCapacity(Handle(0), 0)
+	require.NoError(t, err)
+	require.Zero(t, capacity)
+
+	_, err = f.DeviceCreateGpuInstance(Handle(0), 0)
+	require.Equal(t, nvml.ErrInsufficientResources, err)
+
+	small, err := f.DeviceCreateGpuInstance(Handle(0), 19)
+	require.NoError(t, err)
+
+	info, err := f.GpuInstanceGetInfo(small)
+	require.NoError(t, err)
+	require.Equal(t, nvml.GpuInstanceInfo{
+		Device:    Handle(0),
+		ID:        2,
+		ProfileID: 19,
+		Placement: nvml.GpuInstancePlacement{Start: 0, Size: 1},
+	}, info)
+
+	found, err := f.DeviceGetGpuInstanceByID(Handle(0), 1)
+	require.NoError(t, err)
+	require.Equal(t, gi, found)
+
+	_, err = f.DeviceGetGpuInstanceByID(Handle(0), 3)
+	require.Equal(t, nvml.ErrNotFound, err)
+
+	list, err := f.DeviceGetGpuInstances(Handle(0), half)
+	require.NoError(t, err)
+	require.Equal(t, []nvml.GpuInstance{gi}, list)
+
+	require.NoError(t, f.GpuInstanceDestroy(gi))
+	require.Equal(t, nvml.ErrInvalidArgument, f.GpuInstanceDestroy(gi))
+
+	list, err = f.DeviceGetGpuInstances(Handle(0), half)
+	require.NoError(t, err)
+	require.Empty(t, list)
+}
+
+func TestComputeInstances(t *testing.T) {
+	f := create(t, 2)
+	defer f.Shutdown()
+
+	f.Devices[1] = NewMigDevice(1)
+	f.Devices[1].MigMode = nvml.MigModeEnabled
+
+	gi, err := f.DeviceCreateGpuInstance(Handle(1), 9)
+	require.NoError(t, err)
+
+	_, err = f.GpuInstanceGetComputeInstanceProfileInfo(gi, nvml.ComputeInstanceProfile4Slice, nvml.ComputeInstanceEngineProfileShared)
+	require.Equal(t, nvml.ErrNotSupported, err)
+
+	profile, err := f.GpuInstanceGetComputeInstanceProfileInfo(gi, nvml.ComputeInstanceProfile1Slice, nvml.ComputeInstanceEngineProfileShared)
+	require.NoError(t, err)
+	require.Equal(t, uint32(3), profile.InstanceCount)
+
+	var cis []nvml.ComputeInstance
+	for i := 0; i < 3; i++ {
+		ci, err := f.GpuInstanceCreateComputeInstance(gi, profile.ID)
+		require.NoError(t, err)
+		cis = append(cis, ci)
+	}
+
+	_, err = f.GpuInstanceCreateComputeInstance(gi, profile.ID)
+	require.Equal(t, nvml.ErrInsufficientResources, err)
+
+	capacity, err := f.GpuInstanceGetComputeInstanceRemainingCapacity(gi, profile.ID)
+	require.NoError(t, err)
+	require.Zero(t, capacity)
+
+	list, err := f.GpuInstanceGetComputeInstances(gi, profile)
+	require.NoError(t, err)
+	require.Equal(t, cis, list)
+
+	info, err := f.ComputeInstanceGetInfo(cis[2])
+	require.NoError(t, err)
+	require.Equal(t, nvml.ComputeInstanceInfo{
+		Device:      Handle(1),
+		GpuInstance: gi,
+		ID:          2,
+		ProfileID:   profile.ID,
+		Placement:   nvml.ComputeInstancePlacement{Start: 2, Size: 1},
+	}, info)
+
+	found, err := f.GpuInstanceGetComputeInstanceByID(gi, 1)
+	require.NoError(t, err)
+	require.Equal(t, cis[1], found)
+
+	require.Equal(t, nvml.ErrInUse, f.GpuInstanceDestroy(gi))
+
+	// MIG devices
+	mig, err := f.DeviceGetMigDeviceHandleByIndex(Handle(1), 1)
+	require.NoError(t, err)
+
+	_, err = f.DeviceGetMigDeviceHandleByIndex(Handle(1), 3)
+	require.Equal(t, nvml.ErrNotFound, err)
+
+	isMig, err := f.DeviceIsMigDeviceHandle(mig)
+	require.NoError(t, err)
+	require.True(t, isMig)
+
+	isMig, err = f.DeviceIsMigDeviceHandle(Handle(1))
+	require.NoError(t, err)
+	require.False(t, isMig)
+
+	parent, err := f.DeviceGetDeviceHandleFromMigDeviceHandle(mig)
+	require.NoError(t, err)
+	require.Equal(t, Handle(1), parent)
+
+	id, err := f.DeviceGetGpuInstanceID(mig)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), id)
+
+	id, err = f.DeviceGetComputeInstanceID(mig)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), id)
+
+	_, err = f.DeviceGetComputeInstanceID(Handle(1))
+	require.Equal(t, nvml.ErrNotSupported, err)
+
+	uuid, err := f.DeviceGetUUID(mig)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(uuid, "MIG-"))
+
+	mem, err := f.DeviceGetMemoryInfo(mig)
+	require.NoError(t, err)
+	require.Equal(t, uint64(19968<<20), mem.Total)
+
+	_, err = f.DeviceGetIndex(mig)
+	require.Equal(t, nvml.ErrInvalidArgument, err)
+
+	require.NoError(t, f.ComputeInstanceDestroy(cis[1]))
+
+	_, err = f.DeviceGetUUID(mig)
+	require.Equal(t, nvml.ErrInvalidArgument, err)
+
+	// The lowest free ID and placement are reused
+	ci, err := f.GpuInstanceCreateComputeInstance(gi, profile.ID)
+	require.NoError(t, err)
+
+	info, err = f.ComputeInstanceGetInfo(ci)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), info.ID)
+	require.Equal(t, nvml.ComputeInstancePlacement{Start: 1, Size: 1}, info.Placement)
+}
@@ -0,0 +1,285 @@
+package fake
+
+import (
+	nvml "github.com/mxpv/nvml-go"
+)
+
+// NvLink describes a link of a simulated device.
+type NvLink struct {
+	Active  bool
+	Version uint32
+	// Capabilities reported by DeviceGetNvLinkCapability, missing ones are not supported
+	Capabilities map[nvml.NvLinkCapability]bool
+	// PCI attributes of the remote end of the link
+	Remote nvml.PCIInfo
+	// Error counters, missing ones are not supported
+	ErrorCounters map[nvml.NvLinkErrorCounter]uint64
+	// Utilization counters 0 and 1
+	Utilization [2]NvLinkUtilization
+}
+
+// NvLinkUtilization is a utilization counter of a simulated link.
+type NvLinkUtilization struct {
+	Control nvml.NvLinkUtilizationControl
+	RX      uint64
+	TX      uint64
+	Frozen  bool
+}
+
+// NewNvLink returns an active NVLink 2.0 link to the given remote end, like the ones of a Tesla V100.
+func NewNvLink(remote nvml.PCIInfo) *NvLink {
+	return &NvLink{
+		Active:  true,
+		Version: 2,
+		Capabilities: map[nvml.NvLinkCapability]bool{
+			nvml.NvLinkCapabilityP2PSupported:  true,
+			nvml.NvLinkCapabilitySysmemAccess:  false,
+			nvml.NvLinkCapabilityP2PAtomics:    true,
+			nvml.NvLinkCapabilitySysmemAtomics: false,
+			nvml.NvLinkCapabilitySLIBridge:     false,
+			nvml.NvLinkCapabilityValid:         true,
+		},
+		Remote: remote,
+		ErrorCounters: map[nvml.NvLinkErrorCounter]uint64{
+			nvml.NvLinkErrorDLReplay:   0,
+			nvml.NvLinkErrorDLRecovery: 0,
+			nvml.NvLinkErrorDLCRCFlit:  0,
+			nvml.NvLinkErrorDLCRCData:  0,
+		},
+	}
+}
+
+// ConnectNvLinks adds count links between devices i and j (see NewNvLink).
+func (f *Fake) ConnectNvLinks(i, j, count int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	a, b := f.Devices[i], f.Devices[j]
+	for n := 0; n < count; n++ {
+		a.NvLinks = append(a.NvLinks, NewNvLink(b.PCI))
+		b.NvLinks = append(b.NvLinks, NewNvLink(a.PCI))
+	}
+}
+
+// AddNvLinkTraffic simulates rx and tx bytes going through the given link of device i.
+// Only the utilization counters counting bytes and not frozen are updated.
+func (f *Fake) AddNvLinkTraffic(i, link int, rx, tx uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	l := f.Devices[i].NvLinks[link]
+	for n := range l.Utilization {
+		u := &l.Utilization[n]
+		if u.Control.Units == nvml.NvLinkCounterUnitBytes && !u.Frozen {
+			u.RX += rx
+			u.TX += tx
+		}
+	}
+}
+
+// lookupLink returns the given link of device, or the error method should report. Must be called with mu held.
+func (f *Fake) lookupLink(method string, device nvml.Device, link uint32) (*NvLink, error) {
+	d, err := f.lookup(method, device)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(d.NvLinks) == 0 {
+		return nil, nvml.ErrNotSupported
+	}
+
+	if int(link) >= len(d.NvLinks) {
+		return nil, nvml.ErrInvalidArgument
+	}
+
+	l := d.NvLinks[link]
+	if l == nil {
+		return nil, nvml.ErrNotSupported
+	}
+
+	return l, nil
+}
+
+// DeviceGetNvLinkCapability returns the capability from NvLink.Capabilities.
+func (f *Fake) DeviceGetNvLinkCapability(device nvml.Device, link uint32, capability nvml.NvLinkCapability) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	l, err := f.lookupLink("DeviceGetNvLinkCapability", device, link)
+	if err != nil {
+		return false, err
+	}
+
+	supported, ok := l.Capabilities[capability]
+	if !ok {
+		return false, nvml.ErrNotSupported
+	}
+
+	return supported, nil
+}
+
+// DeviceGetNvLinkErrorCounter returns the counter from NvLink.ErrorCounters.
+func (f *Fake) DeviceGetNvLinkErrorCounter(device nvml.Device, link uint32, counter nvml.NvLinkErrorCounter) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	l, err := f.lookupLink("DeviceGetNvLinkErrorCounter", device, link)
+	if err != nil {
+		return 0, err
+	}
+
+	value, ok := l.ErrorCounters[counter]
+	if !ok {
+		return 0, nvml.ErrNotSupported
+	}
+
+	return value, nil
+}
+
+// DeviceGetNvLinkRemotePciInfo returns a copy of NvLink.Remote.
+func (f *Fake) DeviceGetNvLinkRemotePciInfo(device nvml.Device, link uint32) (*nvml.PCIInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	l, err := f.lookupLink("DeviceGetNvLinkRemotePciInfo", device, link)
+	if err != nil {
+		return nil, err
+	}
+
+	remote := l.Remote
+	return &remote, nil
+}
+
+// DeviceGetNvLinkState returns NvLink.Active.
+func (f *Fake) DeviceGetNvLinkState(device nvml.Device, link uint32) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	l, err := f.lookupLink("DeviceGetNvLinkState", device, link)
+	if err != nil {
+		return false, err
+	}
+
+	return l.Active, nil
+}
+
+// DeviceGetNvLinkVersion returns NvLink.Version.
+func (f *Fake) DeviceGetNvLinkVersion(device nvml.Device, link uint32) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	l, err := f.lookupLink("DeviceGetNvLinkVersion", device, link)
+	if err != nil {
+		return 0, err
+	}
+
+	return l.Version, nil
+}
+
+// DeviceResetNvLinkErrorCounters sets all the counters in NvLink.ErrorCounters to zero.
+func (f *Fake) DeviceResetNvLinkErrorCounters(device nvml.Device, link uint32) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	l, err := f.lookupLink("DeviceResetNvLinkErrorCounters", device, link)
+	if err != nil {
+		return err
+	}
+
+	for counter := range l.ErrorCounters {
+		l.ErrorCounters[counter] = 0
+	}
+
+	return nil
+}
+
+// lookupUtilization returns the given utilization counter of a link, or the error method should report.
+// Must be called with mu held.
+func (f *Fake) lookupUtilization(method string, device nvml.Device, link, counter uint32) (*NvLinkUtilization, error) {
+	l, err := f.lookupLink(method, device, link)
+	if err != nil {
+		return nil, err
+	}
+
+	if int(counter) >= len(l.Utilization) {
+		return nil, nvml.ErrInvalidArgument
+	}
+
+	return &l.Utilization[counter], nil
+}
+
+// DeviceFreezeNvLinkUtilizationCounter sets NvLinkUtilization.Frozen.
+func (f *Fake) DeviceFreezeNvLinkUtilizationCounter(device nvml.Device, link, counter uint32, freeze bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	u, err := f.lookupUtilization("DeviceFreezeNvLinkUtilizationCounter", device, link, counter)
+	if err != nil {
+		return err
+	}
+
+	u.Frozen = freeze
+	return nil
+}
+
+// DeviceGetNvLinkUtilizationControl returns NvLinkUtilization.Control.
+func (f *Fake) DeviceGetNvLinkUtilizationControl(device nvml.Device, link, counter uint32) (nvml.NvLinkUtilizationControl, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	u, err := f.lookupUtilization("DeviceGetNvLinkUtilizationControl", device, link, counter)
+	if err != nil {
+		return nvml.NvLinkUtilizationControl{}, err
+	}
+
+	return u.Control, nil
+}
+
+// DeviceGetNvLinkUtilizationCounter returns NvLinkUtilization.RX and TX.
+func (f *Fake) DeviceGetNvLinkUtilizationCounter(device nvml.Device, link, counter uint32) (uint64, uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	u, err := f.lookupUtilization("DeviceGetNvLinkUtilizationCounter", device, link, counter)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return u.RX, u.TX, nil
+}
+
+// DeviceResetNvLinkUtilizationCounter sets NvLinkUtilization.RX and TX to zero.
+func (f *Fake) DeviceResetNvLinkUtilizationCounter(device nvml.Device, link, counter uint32) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	u, err := f.lookupUtilization("DeviceResetNvLinkUtilizationCounter", device, link, counter)
+	if err != nil {
+		return err
+	}
+
+	u.RX, u.TX = 0, 0
+	return nil
+}
+
+// DeviceSetNvLinkUtilizationControl updates NvLinkUtilization.Control, resetting the counters if requested.
+func (f *Fake) DeviceSetNvLinkUtilizationControl(device nvml.Device, link, counter uint32, control nvml.NvLinkUtilizationControl, reset bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	u, err := f.lookupUtilization("DeviceSetNvLinkUtilizationControl", device, link, counter)
+	if err != nil {
+		return err
+	}
+
+	if control.Units < nvml.NvLinkCounterUnitCycles || control.Units > nvml.NvLinkCounterUnitBytes {
+		return nvml.ErrInvalidArgument
+	}
+
+	u.Control = control
+	if reset {
+		u.RX, u.TX = 0, 0
+	}
+
+	return nil
+}
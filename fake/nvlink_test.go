@@ -0,0 +1,95 @@
+package fake
+
+import (
+	"testing"
+
+	nvml "github.com/mxpv/nvml-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNvLink(t *testing.T) {
+	f := create(t, 3)
+	defer f.Shutdown()
+
+	_, err := f.DeviceGetNvLinkState(Handle(0), 0)
+	require.Equal(t, nvml.ErrNotSupported, err)
+
+	f.ConnectNvLinks(0, 1, 2)
+
+	active, err := f.DeviceGetNvLinkState(Handle(1), 1)
+	require.NoError(t, err)
+	require.True(t, active)
+
+	_, err = f.DeviceGetNvLinkState(Handle(1), 2)
+	require.Equal(t, nvml.ErrInvalidArgument, err)
+
+	remote, err := f.DeviceGetNvLinkRemotePciInfo(Handle(1), 0)
+	require.NoError(t, err)
+	require.Equal(t, f.Devices[0].PCI.BusID, remote.BusID)
+
+	version, err := f.DeviceGetNvLinkVersion(Handle(0), 0)
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), version)
+
+	supported, err := f.DeviceGetNvLinkCapability(Handle(0), 0, nvml.NvLinkCapabilityP2PAtomics)
+	require.NoError(t, err)
+	require.True(t, supported)
+
+	_, err = f.DeviceGetNvLinkErrorCounter(Handle(0), 0, nvml.NvLinkErrorDLECCData)
+	require.Equal(t, nvml.ErrNotSupported, err)
+
+	f.Devices[0].NvLinks[1].ErrorCounters[nvml.NvLinkErrorDLReplay] = 17
+
+	value, err := f.DeviceGetNvLinkErrorCounter(Handle(0), 1, nvml.NvLinkErrorDLReplay)
+	require.NoError(t, err)
+	require.Equal(t, uint64(17), value)
+
+	require.NoError(t, f.DeviceResetNvLinkErrorCounters(Handle(0), 1))
+
+	value, err = f.DeviceGetNvLinkErrorCounter(Handle(0), 1, nvml.NvLinkErrorDLReplay)
+	require.NoError(t, err)
+	require.Zero(t, value)
+}
+
+func TestNvLinkUtilization(t *testing.T) {
+	f := create(t, 2)
+	defer f.Shutdown()
+
+	f.ConnectNvLinks(0, 1, 1)
+
+	control := nvml.NvLinkUtilizationControl{Units: nvml.NvLinkCounterUnitBytes, PacketFilter: nvml.NvLinkCounterPktTypeRead}
+	require.NoError(t, f.DeviceSetNvLinkUtilizationControl(Handle(1), 0, 0, control, false))
+
+	err := f.DeviceSetNvLinkUtilizationControl(Handle(1), 0, 2, control, false)
+	require.Equal(t, nvml.ErrInvalidArgument, err)
+
+	actual, err := f.DeviceGetNvLinkUtilizationControl(Handle(1), 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, control, actual)
+
+	f.AddNvLinkTraffic(1, 0, 100, 200)
+
+	rx, tx, err := f.DeviceGetNvLinkUtilizationCounter(Handle(1), 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), rx)
+	require.Equal(t, uint64(200), tx)
+
+	// Counter 1 counts cycles
+	rx, _, err = f.DeviceGetNvLinkUtilizationCounter(Handle(1), 0, 1)
+	require.NoError(t, err)
+	require.Zero(t, rx)
+
+	require.NoError(t, f.DeviceFreezeNvLinkUtilizationCounter(Handle(1), 0, 0, true))
+	f.AddNvLinkTraffic(1, 0, 100, 200)
+
+	rx, _, err = f.DeviceGetNvLinkUtilizationCounter(Handle(1), 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), rx)
+
+	require.NoError(t, f.DeviceResetNvLinkUtilizationCounter(Handle(1), 0, 0))
+
+	rx, tx, err = f.DeviceGetNvLinkUtilizationCounter(Handle(1), 0, 0)
+	require.NoError(t, err)
+	require.Zero(t, rx)
+	require.Zero(t, tx)
+}
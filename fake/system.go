@@ -0,0 +1,77 @@
+package fake
+
+import (
+	nvml "github.com/mxpv/nvml-go"
+)
+
+// SystemGetCudaDriverVersion returns CUDADriverVersion.
+func (f *Fake) SystemGetCudaDriverVersion() (int32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check("SystemGetCudaDriverVersion"); err != nil {
+		return 0, err
+	}
+
+	return f.CUDADriverVersion, nil
+}
+
+// SystemGetDriverVersion returns DriverVersion.
+func (f *Fake) SystemGetDriverVersion() (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check("SystemGetDriverVersion"); err != nil {
+		return "", err
+	}
+
+	return f.DriverVersion, nil
+}
+
+// SystemGetNVMLVersion returns NVMLVersion.
+func (f *Fake) SystemGetNVMLVersion() (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check("SystemGetNVMLVersion"); err != nil {
+		return "", err
+	}
+
+	return f.NVMLVersion, nil
+}
+
+// SystemGetProcessName looks up the process name in ProcessNames.
+func (f *Fake) SystemGetProcessName(pid uint) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check("SystemGetProcessName"); err != nil {
+		return "", err
+	}
+
+	name, ok := f.ProcessNames[pid]
+	if !ok {
+		return "", nvml.ErrNotFound
+	}
+
+	return name, nil
+}
+
+// SystemGetTopologyGpuSet returns the devices whose CPUAffinity includes cpuNumber.
+func (f *Fake) SystemGetTopologyGpuSet(cpuNumber uint32) ([]nvml.Device, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check("SystemGetTopologyGpuSet"); err != nil {
+		return nil, err
+	}
+
+	list := []nvml.Device{}
+	for i, d := range f.Devices {
+		if word := int(cpuNumber / 64); word < len(d.CPUAffinity) && d.CPUAffinity[word]&(1<<(cpuNumber%64)) != 0 {
+			list = append(list, Handle(i))
+		}
+	}
+
+	return list, nil
+}
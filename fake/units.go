@@ -0,0 +1,208 @@
+package fake
+
+import (
+	"fmt"
+
+	nvml "github.com/mxpv/nvml-go"
+)
+
+// Unit describes a simulated S-class unit.
+type Unit struct {
+	Info         nvml.UnitInfo
+	LED          nvml.LEDState
+	PSU          nvml.PSUInfo
+	Temperatures map[nvml.UnitTemperatureSensor]uint32
+	Fans         []nvml.UnitFanInfo
+	// Indices of the devices attached to the unit
+	Devices []int
+
+	// Errors to return from calls targeting this unit, by method name
+	Errors map[string]error
+}
+
+// NewUnit returns a healthy Unit with the given devices attached. Identifiers are derived from index.
+func NewUnit(index int, devices ...int) *Unit {
+	return &Unit{
+		Info: nvml.UnitInfo{
+			Name:            "S2050",
+			ID:              fmt.Sprintf("%d", index),
+			Serial:          fmt.Sprintf("0322010%06d", index),
+			FirmwareVersion: "6.2",
+		},
+		LED: nvml.LEDState{Color: nvml.LEDColorGreen},
+		PSU: nvml.PSUInfo{State: "Normal", Current: 10, Voltage: 12, Power: 120},
+		Temperatures: map[nvml.UnitTemperatureSensor]uint32{
+			nvml.UnitTemperatureIntake:  24,
+			nvml.UnitTemperatureExhaust: 35,
+			nvml.UnitTemperatureBoard:   30,
+		},
+		Fans: []nvml.UnitFanInfo{
+			{Speed: 6200, State: nvml.FanNormal},
+			{Speed: 6150, State: nvml.FanNormal},
+		},
+		Devices: devices,
+		Errors:  map[string]error{},
+	}
+}
+
+// UnitHandle returns the handle of the unit with the given index, as returned by UnitGetHandleByIndex.
+func UnitHandle(index int) nvml.Unit {
+	return nvml.Unit(index + 1)
+}
+
+// lookupUnit returns the unit referred by handle, or the error method should report. Must be called with mu held.
+func (f *Fake) lookupUnit(method string, handle nvml.Unit) (*Unit, error) {
+	if err := f.check(method); err != nil {
+		return nil, err
+	}
+
+	index := int(handle) - 1
+	if index < 0 || index >= len(f.Units) {
+		return nil, nvml.ErrInvalidArgument
+	}
+
+	u := f.Units[index]
+	if err := u.Errors[method]; err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+// UnitGetCount returns the number of Units.
+func (f *Fake) UnitGetCount() (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check("UnitGetCount"); err != nil {
+		return 0, err
+	}
+
+	return uint32(len(f.Units)), nil
+}
+
+// UnitGetHandleByIndex returns UnitHandle(index).
+func (f *Fake) UnitGetHandleByIndex(index uint32) (nvml.Unit, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.check("UnitGetHandleByIndex"); err != nil {
+		return 0, err
+	}
+
+	if int(index) >= len(f.Units) {
+		return 0, nvml.ErrInvalidArgument
+	}
+
+	return UnitHandle(int(index)), nil
+}
+
+// UnitGetUnitInfo returns Unit.Info.
+func (f *Fake) UnitGetUnitInfo(unit nvml.Unit) (nvml.UnitInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	u, err := f.lookupUnit("UnitGetUnitInfo", unit)
+	if err != nil {
+		return nvml.UnitInfo{}, err
+	}
+
+	return u.Info, nil
+}
+
+// UnitGetLedState returns Unit.LED.
+func (f *Fake) UnitGetLedState(unit nvml.Unit) (nvml.LEDState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	u, err := f.lookupUnit("UnitGetLedState", unit)
+	if err != nil {
+		return nvml.LEDState{}, err
+	}
+
+	return u.LED, nil
+}
+
+// UnitGetPsuInfo returns Unit.PSU.
+func (f *Fake) UnitGetPsuInfo(unit nvml.Unit) (nvml.PSUInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	u, err := f.lookupUnit("UnitGetPsuInfo", unit)
+	if err != nil {
+		return nvml.PSUInfo{}, err
+	}
+
+	return u.PSU, nil
+}
+
+// UnitGetTemperature returns the reading of the given sensor from Unit.Temperatures.
+func (f *Fake) UnitGetTemperature(unit nvml.Unit, sensorType nvml.UnitTemperatureSensor) (uint32, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	u, err := f.lookupUnit("UnitGetTemperature", unit)
+	if err != nil {
+		return 0, err
+	}
+
+	temp, ok := u.Temperatures[sensorType]
+	if !ok {
+		return 0, nvml.ErrNotSupported
+	}
+
+	return temp, nil
+}
+
+// UnitGetFanSpeedInfo returns a copy of Unit.Fans.
+func (f *Fake) UnitGetFanSpeedInfo(unit nvml.Unit) ([]nvml.UnitFanInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	u, err := f.lookupUnit("UnitGetFanSpeedInfo", unit)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]nvml.UnitFanInfo{}, u.Fans...), nil
+}
+
+// UnitGetDevices returns the handles of the devices listed in Unit.Devices.
+func (f *Fake) UnitGetDevices(unit nvml.Unit) ([]nvml.Device, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	u, err := f.lookupUnit("UnitGetDevices", unit)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]nvml.Device, len(u.Devices))
+	for i, index := range u.Devices {
+		list[i] = Handle(index)
+	}
+
+	return list, nil
+}
+
+// UnitSetLedState updates Unit.LED. Setting the LED green clears its cause.
+func (f *Fake) UnitSetLedState(unit nvml.Unit, color nvml.LEDColor) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	u, err := f.lookupUnit("UnitSetLedState", unit)
+	if err != nil {
+		return err
+	}
+
+	if color != nvml.LEDColorGreen && color != nvml.LEDColorAmber {
+		return nvml.ErrInvalidArgument
+	}
+
+	u.LED.Color = color
+	if color == nvml.LEDColorGreen {
+		u.LED.Cause = ""
+	}
+
+	return nil
+}
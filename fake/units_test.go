@@ -0,0 +1,67 @@
+package fake
+
+import (
+	"testing"
+
+	nvml "github.com/mxpv/nvml-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnits(t *testing.T) {
+	f := create(t, 4)
+	defer f.Shutdown()
+
+	count, err := f.UnitGetCount()
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	f.Units = []*Unit{NewUnit(0, 0, 1), NewUnit(1, 2, 3)}
+	f.Units[1].Fans[1].State = nvml.FanFailed
+	f.Units[1].LED = nvml.LEDState{Cause: "Fan failure", Color: nvml.LEDColorAmber}
+
+	count, err = f.UnitGetCount()
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), count)
+
+	unit, err := f.UnitGetHandleByIndex(1)
+	require.NoError(t, err)
+
+	_, err = f.UnitGetHandleByIndex(2)
+	require.Equal(t, nvml.ErrInvalidArgument, err)
+
+	devices, err := f.UnitGetDevices(unit)
+	require.NoError(t, err)
+	require.Equal(t, []nvml.Device{Handle(2), Handle(3)}, devices)
+
+	fans, err := f.UnitGetFanSpeedInfo(unit)
+	require.NoError(t, err)
+	require.Len(t, fans, 2)
+	require.Equal(t, nvml.FanFailed, fans[1].State)
+
+	temp, err := f.UnitGetTemperature(unit, nvml.UnitTemperatureExhaust)
+	require.NoError(t, err)
+	require.Equal(t, uint32(35), temp)
+
+	state, err := f.UnitGetLedState(unit)
+	require.NoError(t, err)
+	require.Equal(t, nvml.LEDColorAmber, state.Color)
+
+	err = f.UnitSetLedState(unit, nvml.LEDColorGreen)
+	require.NoError(t, err)
+
+	state, err = f.UnitGetLedState(unit)
+	require.NoError(t, err)
+	require.Equal(t, nvml.LEDState{Color: nvml.LEDColorGreen}, state)
+
+	f.Units[0].Errors["UnitGetPsuInfo"] = nvml.ErrNoPermission
+	_, err = f.UnitGetPsuInfo(UnitHandle(0))
+	require.Equal(t, nvml.ErrNoPermission, err)
+
+	psu, err := f.UnitGetPsuInfo(unit)
+	require.NoError(t, err)
+	require.Equal(t, "Normal", psu.State)
+
+	info, err := f.UnitGetUnitInfo(unit)
+	require.NoError(t, err)
+	require.Equal(t, "1", info.ID)
+}
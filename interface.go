@@ -0,0 +1,188 @@
+package nvml
+
+// Interface is implemented by API and lists the NVML calls available on all platforms.
+// Code built on top of this package should depend on Interface rather than *API, so it can be tested
+// with an in-memory implementation (see the fake sub-package) on machines without NVIDIA GPUs.
+type Interface interface {
+	platformInterface
+
+	// Initialization and cleanup
+	Init() error
+	Shutdown() error
+
+	// System Queries
+	SystemGetCudaDriverVersion() (cudaDriverVersion int32, err error)
+	SystemGetDriverVersion() (string, error)
+	SystemGetNVMLVersion() (string, error)
+	SystemGetProcessName(pid uint) (string, error)
+	SystemGetTopologyGpuSet(cpuNumber uint32) ([]Device, error)
+
+	// Device Queries
+	DeviceGetAPIRestriction(device Device, apiType RestrictedAPI) (bool, error)
+	DeviceGetApplicationsClock(device Device, clockType ClockType) (clockMHz uint32, err error)
+	DeviceGetAutoBoostedClocksEnabled(device Device) (isEnabled, defaultIsEnabled bool, err error)
+	DeviceGetBAR1MemoryInfo(device Device) (mem BAR1Memory, err error)
+	DeviceGetBoardID(device Device) (boardID uint32, err error)
+	DeviceGetBoardPartNumber(device Device) (string, error)
+	DeviceGetBrand(device Device) (brand BrandType, err error)
+	DeviceGetBridgeChipInfo(device Device) (*BridgeChipHierarchy, error)
+	DeviceGetClock(device Device, clockType ClockType, clockID ClockID) (clockMHz uint32, err error)
+	DeviceGetClockInfo(device Device, clockType ClockType) (clock uint32, err error)
+	DeviceGetComputeMode(device Device) (mode ComputeMode, err error)
+	DeviceGetComputeRunningProcesses(device Device) ([]ProcessInfo, error)
+	DeviceGetCount() (count uint32, err error)
+	DeviceGetCudaComputeCapability(device Device) (major, minor int32, err error)
+	DeviceGetCurrPcieLinkGeneration(device Device) (currLinkGen uint32, err error)
+	DeviceGetCurrPcieLinkWidth(device Device) (currLinkWidth uint32, err error)
+	DeviceGetCurrentClocksThrottleReasons(device Device) (clocksThrottleReasons ClocksThrottleReason, err error)
+	DeviceGetDecoderUtilization(device Device) (utilization, samplingPeriodUs uint32, err error)
+	DeviceGetDefaultApplicationsClock(device Device, clockType ClockType) (clockMHz uint32, err error)
+	DeviceGetDetailedECCErrors(device Device, errorType MemoryErrorType, counterType ECCCounterType) (*ECCErrorCounts, error)
+	DeviceGetDisplayActive(device Device) (bool, error)
+	DeviceGetDisplayMode(device Device) (bool, error)
+	DeviceGetDriverModel(device Device) (current, pending DriverModel, err error)
+	DeviceGetECCMode(device Device) (current, pending bool, err error)
+	DeviceGetEncoderCapacity(device Device, encoderQueryType EncoderType) (encoderCapacity uint32, err error)
+	DeviceGetEncoderSessions(device Device) ([]EncoderSessionInfo, error)
+	DeviceGetEncoderStats(device Device) (sessionCount, averageFPS, averageLatency uint32, err error)
+	DeviceGetEncoderUtilization(device Device) (utilization, samplingPeriodUs uint32, err error)
+	DeviceGetEnforcedPowerLimit(device Device) (limit uint32, err error)
+	DeviceGetFanSpeed(device Device) (speed uint32, err error)
+	DeviceGetFieldValues(device Device, requests []FieldRequest) ([]FieldValue, error)
+	DeviceGetFBCSessions(device Device) ([]FBCSessionInfo, error)
+	DeviceGetFBCStats(device Device) (fbcStats FBCStats, err error)
+	DeviceGetGPUOperationMode(device Device) (current, pending GPUOperationMode, err error)
+	DeviceGetGraphicsRunningProcesses(device Device) ([]ProcessInfo, error)
+	DeviceGetHandleByIndex(index uint32) (device Device, err error)
+	DeviceGetHandleByPCIBusID(pciBusID string) (device Device, err error)
+	DeviceGetHandleBySerial(serial string) (device Device, err error)
+	DeviceGetHandleByUUID(uuid string) (device Device, err error)
+	DeviceGetIndex(device Device) (index uint32, err error)
+	DeviceGetInforomConfigurationChecksum(device Device) (checksum uint32, err error)
+	DeviceGetInfoROMImageVersion(device Device) (string, error)
+	DeviceGetInfoROMVersion(device Device, object InfoROMObject) (string, error)
+	DeviceGetMaxClockInfo(device Device, clockType ClockType) (clock uint32, err error)
+	DeviceGetMaxCustomerBoostClock(device Device, clockType ClockType) (clockMHz uint32, err error)
+	DeviceGetMaxPcieLinkGeneration(device Device) (maxLinkGen uint32, err error)
+	DeviceGetMaxPcieLinkWidth(device Device) (maxLinkWidth uint32, err error)
+	DeviceGetMemoryErrorCounter(device Device, errorType MemoryErrorType, counterType ECCCounterType, locationType MemoryLocation) (count uint64, err error)
+	DeviceGetMemoryInfo(device Device) (mem Memory, err error)
+	DeviceGetMinorNumber(device Device) (minorNumber uint32, err error)
+	DeviceGetMultiGpuBoard(device Device) (multiGpu bool, err error)
+	DeviceGetName(device Device) (string, error)
+	DeviceGetP2PStatus(device1 Device, device2 Device, p2pIndex P2PCapsIndex) (p2pStatus P2PStatus, err error)
+	DeviceGetPCIInfo(device Device) (*PCIInfo, error)
+	DeviceGetPcieReplayCounter(device Device) (value uint32, err error)
+	DeviceGetPCIeThroughput(device Device, counter PCIeUtilCounter) (value uint32, err error)
+	DeviceGetPerformanceState(device Device) (state PState, err error)
+	DeviceGetPowerManagementDefaultLimit(device Device) (defaultLimit uint32, err error)
+	DeviceGetPowerManagementLimit(device Device) (limit uint32, err error)
+	DeviceGetPowerManagementLimitConstraints(device Device) (minLimit, maxLimit uint32, err error)
+	DeviceGetPowerManagementMode(device Device) (bool, error)
+	DeviceGetPowerState(device Device) (state PState, err error)
+	DeviceGetPowerUsage(device Device) (power uint32, err error)
+	DeviceGetProcessUtilization(device Device, lastSeenTimeStamp uint64) ([]ProcessUtilizationSample, error)
+	DeviceGetRemappedRows(device Device) (correctable, uncorrectable uint32, isPending, failureOccurred bool, err error)
+	DeviceGetRetiredPages(device Device, cause PageRetirementCause) ([]uint64, error)
+	DeviceGetAllRetiredPages(device Device) ([]RetiredPage, error)
+	DeviceGetRetiredPagesPendingStatus(device Device) (isPending bool, err error)
+	DeviceGetRowRemapperHistogram(device Device) (histogram RowRemapperHistogram, err error)
+	DeviceGetSamples(device Device, samplingType SamplingType, lastSeenTimeStamp uint64) (ValueType, []Sample, error)
+	DeviceGetSerial(device Device) (serial string, err error)
+	DeviceGetSupportedClocksThrottleReasons(device Device) (supportedClocksThrottleReasons ClocksThrottleReason, err error)
+	DeviceGetSupportedGraphicsClocks(device Device, memoryClockMHz uint32) ([]uint32, error)
+	DeviceGetSupportedMemoryClocks(device Device) ([]uint32, error)
+	DeviceGetTemperature(device Device, sensorType TemperatureSensor) (temp uint32, err error)
+	DeviceGetTemperatureThreshold(device Device, thresholdType TemperatureThreshold) (temp uint32, err error)
+	DeviceGetTopologyCommonAncestor(device1 Device, device2 Device) (pathInfo GPUTopologyLevel, err error)
+	DeviceGetTopologyNearestGpus(device Device, level GPUTopologyLevel) ([]Device, error)
+	DeviceGetTotalECCErrors(device Device, errorType MemoryErrorType, counterType ECCCounterType) (eccCount uint64, err error)
+	DeviceGetTotalEnergyConsumption(device Device) (energy uint64, err error)
+	DeviceGetUUID(device Device) (string, error)
+	DeviceGetUtilizationRates(device Device) (u Utilization, err error)
+	DeviceGetVbiosVersion(device Device) (string, error)
+	DeviceGetViolationStatus(device Device, policyType PerfPolicyType) (violTime ViolationTime, err error)
+	DeviceOnSameBoard(device1 Device, device2 Device) (bool, error)
+	DeviceResetApplicationsClocks(device Device) error
+	DeviceSetAutoBoostedClocksEnabled(device Device, enabled bool) error
+	DeviceSetDefaultAutoBoostedClocksEnabled(device Device, enabled bool) error
+	DeviceValidateInforom(device Device) (err error)
+
+	// Device Commands
+	DeviceClearECCErrorCounts(device Device, counterType ECCCounterType) error
+	DeviceClearFieldValues(device Device, requests []FieldRequest) error
+	DeviceResetGPULockedClocks(device Device) error
+	DeviceSetAPIRestriction(device Device, apiType RestrictedAPI, isRestricted bool) error
+	DeviceSetApplicationsClocks(device Device, memClockMHz, graphicsClockMHz uint32) error
+	DeviceSetComputeMode(device Device, mode ComputeMode) error
+	DeviceSetDriverModel(device Device, model DriverModel, flags uint32) error
+	DeviceSetECCMode(device Device, ecc bool) error
+	DeviceSetGPUOperationMode(device Device, mode GPUOperationMode) error
+	DeviceSetPowerManagementLimit(device Device, limit uint32) error
+
+	// Unit Queries
+	UnitGetCount() (unitCount uint32, err error)
+	UnitGetDevices(unit Unit) ([]Device, error)
+	UnitGetFanSpeedInfo(unit Unit) ([]UnitFanInfo, error)
+	UnitGetHandleByIndex(index uint32) (unit Unit, err error)
+	UnitGetLedState(unit Unit) (LEDState, error)
+	UnitGetPsuInfo(unit Unit) (PSUInfo, error)
+	UnitGetTemperature(unit Unit, sensorType UnitTemperatureSensor) (temp uint32, err error)
+	UnitGetUnitInfo(unit Unit) (UnitInfo, error)
+
+	// Unit Commands
+	UnitSetLedState(unit Unit, color LEDColor) error
+
+	// Event Handling
+	DeviceGetSupportedEventTypes(device Device) (eventTypes EventType, err error)
+	EventSetCreate() (EventSet, error)
+
+	// NvLink Methods
+	DeviceFreezeNvLinkUtilizationCounter(device Device, link, counter uint32, freeze bool) error
+	DeviceGetNvLinkCapability(device Device, link uint32, capability NvLinkCapability) (bool, error)
+	DeviceGetNvLinkErrorCounter(device Device, link uint32, counter NvLinkErrorCounter) (counterValue uint64, err error)
+	DeviceGetNvLinkRemotePciInfo(device Device, link uint32) (*PCIInfo, error)
+	DeviceGetNvLinkState(device Device, link uint32) (isActive bool, err error)
+	DeviceGetNvLinkUtilizationControl(device Device, link, counter uint32) (control NvLinkUtilizationControl, err error)
+	DeviceGetNvLinkUtilizationCounter(device Device, link, counter uint32) (rxCounter, txCounter uint64, err error)
+	DeviceGetNvLinkVersion(device Device, link uint32) (version uint32, err error)
+	DeviceResetNvLinkErrorCounters(device Device, link uint32) error
+	DeviceResetNvLinkUtilizationCounter(device Device, link, counter uint32) error
+	DeviceSetNvLinkUtilizationControl(device Device, link, counter uint32, control NvLinkUtilizationControl, reset bool) error
+
+	// Multi Instance GPU Management
+	ComputeInstanceDestroy(computeInstance ComputeInstance) error
+	ComputeInstanceGetInfo(computeInstance ComputeInstance) (ComputeInstanceInfo, error)
+	DeviceCreateGpuInstance(device Device, profileID uint32) (gpuInstance GpuInstance, err error)
+	DeviceCreateGpuInstanceWithPlacement(device Device, profileID uint32, placement GpuInstancePlacement) (gpuInstance GpuInstance, err error)
+	DeviceGetComputeInstanceID(device Device) (id uint32, err error)
+	DeviceGetDeviceHandleFromMigDeviceHandle(migDevice Device) (device Device, err error)
+	DeviceGetGpuInstanceByID(device Device, id uint32) (gpuInstance GpuInstance, err error)
+	DeviceGetGpuInstanceID(device Device) (id uint32, err error)
+	DeviceGetGpuInstancePossiblePlacements(device Device, profileID uint32) ([]GpuInstancePlacement, error)
+	DeviceGetGpuInstanceProfileInfo(device Device, profile GpuInstanceProfile) (info GpuInstanceProfileInfo, err error)
+	DeviceGetGpuInstanceRemainingCapacity(device Device, profileID uint32) (count uint32, err error)
+	DeviceGetGpuInstances(device Device, info GpuInstanceProfileInfo) ([]GpuInstance, error)
+	DeviceGetMaxMigDeviceCount(device Device) (count uint32, err error)
+	DeviceGetMigDeviceHandleByIndex(device Device, index uint32) (migDevice Device, err error)
+	DeviceGetMigMode(device Device) (current, pending MigMode, err error)
+	DeviceIsMigDeviceHandle(device Device) (bool, error)
+	DeviceSetMigMode(device Device, mode MigMode) error
+	GpuInstanceCreateComputeInstance(gpuInstance GpuInstance, profileID uint32) (computeInstance ComputeInstance, err error)
+	GpuInstanceDestroy(gpuInstance GpuInstance) error
+	GpuInstanceGetComputeInstanceByID(gpuInstance GpuInstance, id uint32) (computeInstance ComputeInstance, err error)
+	GpuInstanceGetComputeInstanceProfileInfo(gpuInstance GpuInstance, profile ComputeInstanceProfile, engProfile ComputeInstanceEngineProfile) (info ComputeInstanceProfileInfo, err error)
+	GpuInstanceGetComputeInstanceRemainingCapacity(gpuInstance GpuInstance, profileID uint32) (count uint32, err error)
+	GpuInstanceGetComputeInstances(gpuInstance GpuInstance, info ComputeInstanceProfileInfo) ([]ComputeInstance, error)
+	GpuInstanceGetInfo(gpuInstance GpuInstance) (info GpuInstanceInfo, err error)
+
+	// Accounting Statistics
+	DeviceClearAccountingPids(device Device) error
+	DeviceGetAccountingBufferSize(device Device) (bufferSize uint32, err error)
+	DeviceGetAccountingMode(device Device) (enabled bool, err error)
+	DeviceGetAccountingPids(device Device) ([]uint32, error)
+	DeviceGetAccountingStats(device Device, pid uint32) (stats AccountingStats, err error)
+	DeviceSetAccountingMode(device Device, mode bool) error
+}
+
+var _ Interface = API{}
@@ -0,0 +1,16 @@
+// +build linux,cgo
+
+package nvml
+
+// platformInterface lists the NVML calls only available on Linux.
+type platformInterface interface {
+	DeviceGetCPUAffinity(device Device, cpuSetSize uint32) ([]uint64, error)
+	DeviceSetCpuAffinity(device Device) error
+	DeviceClearCpuAffinity(device Device) (err error)
+	DeviceGetPersistenceMode(device Device) (enabled bool, err error)
+	DeviceSetPersistenceMode(device Device, mode bool) error
+	DeviceModifyDrainState(pci *PCIInfo, drain bool) error
+	DeviceQueryDrainState(pci *PCIInfo) (drained bool, err error)
+	DeviceRemoveGPU(pci *PCIInfo) error
+	DeviceDiscoverGPUs(pci *PCIInfo) error
+}
@@ -0,0 +1,6 @@
+// +build !linux !cgo
+
+package nvml
+
+// platformInterface lists the NVML calls only available on Linux, so it's empty on other platforms.
+type platformInterface interface{}
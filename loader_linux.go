@@ -0,0 +1,155 @@
+// +build linux,cgo
+
+package nvml
+
+// #cgo LDFLAGS: -ldl
+// #include <dlfcn.h>
+// #include <stdint.h>
+// #include <stdlib.h>
+//
+// typedef uintptr_t (*nvmlProc)(uintptr_t, uintptr_t, uintptr_t, uintptr_t,
+//                               uintptr_t, uintptr_t, uintptr_t, uintptr_t);
+//
+// static uintptr_t nvmlCallProc(void *fn,
+//                               uintptr_t a0, uintptr_t a1, uintptr_t a2, uintptr_t a3,
+//                               uintptr_t a4, uintptr_t a5, uintptr_t a6, uintptr_t a7) {
+//     return ((nvmlProc)fn)(a0, a1, a2, a3, a4, a5, a6, a7);
+// }
+import "C"
+
+import (
+	"os"
+	"path/filepath"
+	"unsafe"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	libraryName = "libnvidia-ml.so.1"
+	// Maximum number of arguments accepted by any NVML entry point.
+	maxProcArgs = 8
+)
+
+// librarySearchPaths lists directories probed for libnvidia-ml.so.1 when no explicit path is given.
+// The last entries are the driver mounts used by the NVIDIA container toolkit and GPU operator.
+var librarySearchPaths = []string{
+	"/usr/lib/x86_64-linux-gnu",
+	"/usr/lib/aarch64-linux-gnu",
+	"/usr/lib64",
+	"/usr/lib",
+	"/usr/local/nvidia/lib64",
+	"/usr/local/nvidia/lib",
+	"/run/nvidia/driver/usr/lib/x86_64-linux-gnu",
+	"/run/nvidia/driver/usr/lib64",
+}
+
+// sharedObject is a library opened with dlopen.
+type sharedObject struct {
+	path   string
+	handle unsafe.Pointer
+}
+
+// symbol is an entry point resolved with dlsym.
+type symbol struct {
+	name string
+	addr unsafe.Pointer
+}
+
+// Call invokes the symbol with up to maxProcArgs integer or pointer arguments.
+// Like syscall.Proc, it returns the raw value of the return register.
+func (s *symbol) Call(args ...uintptr) (uintptr, uintptr, error) {
+	if len(args) > maxProcArgs {
+		panic(errors.Errorf("%s called with %d arguments, max %d", s.name, len(args), maxProcArgs))
+	}
+
+	var a [maxProcArgs]uintptr
+	copy(a[:], args)
+
+	ret := C.nvmlCallProc(s.addr,
+		C.uintptr_t(a[0]), C.uintptr_t(a[1]), C.uintptr_t(a[2]), C.uintptr_t(a[3]),
+		C.uintptr_t(a[4]), C.uintptr_t(a[5]), C.uintptr_t(a[6]), C.uintptr_t(a[7]))
+
+	return uintptr(ret), 0, nil
+}
+
+func (so *sharedObject) FindProc(name string) (proc, error) {
+	cname := C.CString(name)
+	defer C.free(unsafe.Pointer(cname))
+
+	// Clear any stale error before the lookup, dlsym may legitimately return NULL
+	C.dlerror()
+	addr := C.dlsym(so.handle, cname)
+	if addr == nil {
+		return nil, errors.Errorf("failed to find %s in %s: %s", name, so.path, dlerror())
+	}
+
+	return &symbol{name: name, addr: addr}, nil
+}
+
+func (so *sharedObject) Release() error {
+	if C.dlclose(so.handle) != 0 {
+		return errors.Errorf("failed to unload %s: %s", so.path, dlerror())
+	}
+
+	return nil
+}
+
+func dlerror() string {
+	if msg := C.dlerror(); msg != nil {
+		return C.GoString(msg)
+	}
+
+	return "unknown error"
+}
+
+func dlopen(path string) (*sharedObject, error) {
+	cpath := C.CString(path)
+	defer C.free(unsafe.Pointer(cpath))
+
+	handle := C.dlopen(cpath, C.RTLD_LAZY|C.RTLD_LOCAL)
+	if handle == nil {
+		return nil, errors.New(dlerror())
+	}
+
+	return &sharedObject{path: path, handle: handle}, nil
+}
+
+// libraryCandidates returns the locations probed for libnvidia-ml.so.1, in order:
+// directories from LD_LIBRARY_PATH, the dynamic linker's own lookup (ld.so.cache) and librarySearchPaths.
+func libraryCandidates() []string {
+	var candidates []string
+	for _, dir := range filepath.SplitList(os.Getenv("LD_LIBRARY_PATH")) {
+		if dir != "" {
+			candidates = append(candidates, filepath.Join(dir, libraryName))
+		}
+	}
+
+	candidates = append(candidates, libraryName)
+	for _, dir := range librarySearchPaths {
+		candidates = append(candidates, filepath.Join(dir, libraryName))
+	}
+
+	return candidates
+}
+
+// loadLibrary opens libnvidia-ml.so.1 from path or, if path is empty, from the first location
+// returned by libraryCandidates that can be loaded.
+func loadLibrary(path string) (library, error) {
+	candidates := []string{path}
+	if path == "" {
+		candidates = libraryCandidates()
+	}
+
+	var lastErr error
+	for _, candidate := range candidates {
+		so, err := dlopen(candidate)
+		if err == nil {
+			return so, nil
+		}
+
+		lastErr = err
+	}
+
+	return nil, errors.Wrapf(ErrLibraryNotFound, "failed to load %s: %v", libraryName, lastErr)
+}
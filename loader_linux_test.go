@@ -0,0 +1,345 @@
+// +build linux,cgo
+
+package nvml
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// stubFunctions holds the bodies of the stub library entry points that do more than return NVML_ERROR_NOT_SUPPORTED.
+// Only the loader ones are listed here, the other test files add the entry points they exercise with
+// addStubFunctions.
+var stubFunctions = map[string]string{
+	"nvmlInit":     "int nvmlInit(void) { return 0; }",
+	"nvmlShutdown": "int nvmlShutdown(void) { return 0; }",
+	"nvmlErrorString": `const char *nvmlErrorString(int result) {
+		return result == 3 ? "Not Supported" : "Stub Error";
+	}`,
+	"nvmlSystemGetDriverVersion": `int nvmlSystemGetDriverVersion(char *version, unsigned int length) {
+		snprintf(version, length, "%s", "999.99");
+		return 0;
+	}`,
+	"nvmlDeviceGetCount": "int nvmlDeviceGetCount(unsigned int *count) { *count = 2; return 0; }",
+	"nvmlDeviceGetHandleByIndex": `int nvmlDeviceGetHandleByIndex(unsigned int index, void **device) {
+		if (index >= 2) return 2;
+		*device = (void *)(uintptr_t)(0x1000 + index);
+		return 0;
+	}`,
+	"nvmlInit_v2":             "int nvmlInit_v2(void) { return 0; }",
+	"nvmlDeviceGetCount_v2":   "int nvmlDeviceGetCount_v2(unsigned int *count) { *count = 4; return 0; }",
+	"nvmlDeviceGetPciInfo":    "int nvmlDeviceGetPciInfo(void *d, pciInfoLegacy *pci) { fillLegacyPci(pci, 1); return 0; }",
+	"nvmlDeviceGetPciInfo_v2": "int nvmlDeviceGetPciInfo_v2(void *d, pciInfoLegacy *pci) { fillLegacyPci(pci, 2); return 0; }",
+	"nvmlDeviceGetPciInfo_v3": `int nvmlDeviceGetPciInfo_v3(void *d, pciInfo *pci) {
+		snprintf(pci->busIdLegacy, sizeof(pci->busIdLegacy), "0000:03:00.0");
+		snprintf(pci->busId, sizeof(pci->busId), "00000000:03:00.0");
+		pci->bus = 3;
+		pci->pciDeviceId = 0x1db410de;
+		return 0;
+	}`,
+	"nvmlDeviceGetComputeRunningProcesses": `int nvmlDeviceGetComputeRunningProcesses(void *d, unsigned int *count, processInfoV1 *infos) {
+		if (infos == NULL) { *count = 2; return 7; }
+		for (unsigned int i = 0; i < 2; i++) { infos[i].pid = 100 + i; infos[i].usedGpuMemory = 1 << 20; }
+		*count = 2;
+		return 0;
+	}`,
+	"nvmlDeviceGetComputeRunningProcesses_v2": `int nvmlDeviceGetComputeRunningProcesses_v2(void *d, unsigned int *count, processInfoV2 *infos) {
+		return fillProcesses(count, infos, 2);
+	}`,
+	"nvmlDeviceGetComputeRunningProcesses_v3": `int nvmlDeviceGetComputeRunningProcesses_v3(void *d, unsigned int *count, processInfoV2 *infos) {
+		return fillProcesses(count, infos, 3);
+	}`,
+}
+
+// stubPrelude declares the types and helpers used by stubFunctions.
+var stubPrelude = `
+#include <stdint.h>
+#include <stdio.h>
+#include <string.h>
+
+typedef struct {
+	char busId[16];
+	unsigned int domain, bus, device, pciDeviceId, pciSubSystemId;
+	unsigned int reserved[4];
+} pciInfoLegacy;
+
+typedef struct {
+	char busIdLegacy[16];
+	unsigned int domain, bus, device, pciDeviceId, pciSubSystemId;
+	char busId[32];
+} pciInfo;
+
+typedef struct {
+	unsigned int pid;
+	unsigned long long usedGpuMemory;
+} processInfoV1;
+
+typedef struct {
+	unsigned int pid;
+	unsigned long long usedGpuMemory;
+	unsigned int gpuInstanceId, computeInstanceId;
+} processInfoV2;
+
+static void fillLegacyPci(pciInfoLegacy *pci, unsigned int bus) {
+	snprintf(pci->busId, sizeof(pci->busId), "0000:%02x:00.0", bus);
+	pci->bus = bus;
+	pci->pciDeviceId = 0x1db410de;
+}
+
+static int fillProcesses(unsigned int *count, processInfoV2 *infos, unsigned int gpuInstanceId) {
+	if (infos == NULL) { *count = 2; return 7; }
+	for (unsigned int i = 0; i < 2; i++) {
+		infos[i].pid = 100 + i;
+		infos[i].usedGpuMemory = 1 << 20;
+		infos[i].gpuInstanceId = gpuInstanceId;
+		infos[i].computeInstanceId = 0;
+	}
+	*count = 2;
+	return 0;
+}
+`
+
+// addStubFunctions adds entry points to the stub library, along with the types and helpers they use.
+// It's meant to be called from the init function of the test file exercising them.
+func addStubFunctions(prelude string, functions map[string]string) {
+	stubPrelude += prelude
+
+	for name, body := range functions {
+		if _, ok := stubFunctions[name]; ok {
+			panic("duplicate stub function " + name)
+		}
+
+		stubFunctions[name] = body
+	}
+}
+
+// stubSymbols returns names of all NVML entry points resolved by New.
+func stubSymbols() []string {
+	var names []string
+
+	typ := reflect.TypeOf(API{})
+	for i := 0; i < typ.NumField(); i++ {
+		if name := typ.Field(i).Name; strings.HasPrefix(name, "nvml") {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// newStubLibrary compiles a fake libnvidia-ml.so.1 exporting the given symbols and returns its path.
+// Symbols without an entry in stubFunctions return NVML_ERROR_NOT_SUPPORTED.
+// The library is removed when the test completes.
+func newStubLibrary(t *testing.T, symbols []string) string {
+	cc := os.Getenv("CC")
+	if cc == "" {
+		cc = "cc"
+	}
+
+	if _, err := exec.LookPath(cc); err != nil {
+		t.Skipf("C compiler is not available: %v", err)
+	}
+
+	dir, err := ioutil.TempDir("", "nvml-stub")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	source := &strings.Builder{}
+	fmt.Fprint(source, stubPrelude)
+
+	for _, name := range symbols {
+		if body, ok := stubFunctions[name]; ok {
+			fmt.Fprintln(source, body)
+		} else {
+			fmt.Fprintf(source, "int %s(void) { return 3; }\n", name)
+		}
+	}
+
+	sourcePath := filepath.Join(dir, "stub.c")
+	require.NoError(t, ioutil.WriteFile(sourcePath, []byte(source.String()), 0644))
+
+	libPath := filepath.Join(dir, libraryName)
+	out, err := exec.Command(cc, "-shared", "-fPIC", "-o", libPath, sourcePath).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	return libPath
+}
+
+// newStubAPI loads a stub library exporting all the entry points resolved by New, along with the given extra ones
+// (e.g. versioned entry points). See newStubLibrary. NVML is shut down when the test completes.
+func newStubAPI(t *testing.T, exports ...string) *API {
+	return loadStubLibrary(t, append(stubSymbols(), exports...))
+}
+
+// loadStubLibrary loads a stub library exporting exactly the given symbols, see newStubAPI.
+func loadStubLibrary(t *testing.T, symbols []string) *API {
+	w, err := New(newStubLibrary(t, symbols))
+	require.NoError(t, err)
+	t.Cleanup(func() { w.Shutdown() })
+
+	return w
+}
+
+func TestNewStubLibrary(t *testing.T) {
+	w, err := New(newStubLibrary(t, stubSymbols()))
+	require.NoError(t, err)
+
+	err = w.Init()
+	require.NoError(t, err)
+
+	count, err := w.DeviceGetCount()
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), count)
+
+	device, err := w.DeviceGetHandleByIndex(1)
+	require.NoError(t, err)
+	require.Equal(t, Device(0x1001), device)
+
+	_, err = w.DeviceGetHandleByIndex(2)
+	require.Equal(t, ErrInvalidArgument, err)
+
+	version, err := w.SystemGetDriverVersion()
+	require.NoError(t, err)
+	require.Equal(t, "999.99", version)
+
+	_, err = w.DeviceGetFanSpeed(device)
+	require.Equal(t, ErrNotSupported, err)
+
+	require.Equal(t, "Not Supported", w.ErrorString(3))
+	require.Empty(t, w.MissingSymbols())
+
+	err = w.Shutdown()
+	require.NoError(t, err)
+}
+
+func TestNewSearchesLibraryPath(t *testing.T) {
+	path := newStubLibrary(t, stubSymbols())
+	t.Setenv("LD_LIBRARY_PATH", filepath.Dir(path))
+
+	w, err := New("")
+	require.NoError(t, err)
+
+	err = w.Init()
+	require.NoError(t, err)
+
+	err = w.Shutdown()
+	require.NoError(t, err)
+}
+
+func TestNewMissingSymbols(t *testing.T) {
+	var symbols []string
+	for _, name := range stubSymbols() {
+		if name != "nvmlDeviceGetTotalEnergyConsumption" && name != "nvmlErrorString" {
+			symbols = append(symbols, name)
+		}
+	}
+
+	w := loadStubLibrary(t, symbols)
+
+	require.Equal(t, []string{"nvmlDeviceGetTotalEnergyConsumption", "nvmlErrorString"}, w.MissingSymbols())
+	require.False(t, w.Supports("nvmlDeviceGetTotalEnergyConsumption"))
+	require.True(t, w.Supports("nvmlDeviceGetCount"))
+	require.False(t, w.Supports("nvmlUnknownFunction"))
+
+	err := w.Init()
+	require.NoError(t, err)
+
+	device, err := w.DeviceGetHandleByIndex(0)
+	require.NoError(t, err)
+
+	_, err = w.DeviceGetTotalEnergyConsumption(device)
+	require.Equal(t, ErrFunctionNotFound, err)
+
+	require.Equal(t, ErrInvalidArgument.Error(), w.ErrorString(2))
+}
+
+func TestVersionedSymbols(t *testing.T) {
+	tests := []struct {
+		name              string
+		exports           []string
+		pciVersion        int
+		busID             string
+		busIDLegacy       string
+		processesVersion  int
+		gpuInstanceID     uint32
+		deviceCount       uint32
+		deviceCountSymbol string
+	}{
+		{
+			name:              "unversioned",
+			pciVersion:        1,
+			busID:             "0000:01:00.0",
+			busIDLegacy:       "0000:01:00.0",
+			processesVersion:  1,
+			gpuInstanceID:     math.MaxUint32,
+			deviceCount:       2,
+			deviceCountSymbol: "nvmlDeviceGetCount",
+		},
+		{
+			name:              "v2",
+			exports:           []string{"nvmlInit_v2", "nvmlDeviceGetCount_v2", "nvmlDeviceGetPciInfo_v2", "nvmlDeviceGetComputeRunningProcesses_v2"},
+			pciVersion:        2,
+			busID:             "0000:02:00.0",
+			busIDLegacy:       "0000:02:00.0",
+			processesVersion:  2,
+			gpuInstanceID:     2,
+			deviceCount:       4,
+			deviceCountSymbol: "nvmlDeviceGetCount_v2",
+		},
+		{
+			name:              "v3",
+			exports:           []string{"nvmlDeviceGetPciInfo_v2", "nvmlDeviceGetPciInfo_v3", "nvmlDeviceGetComputeRunningProcesses_v2", "nvmlDeviceGetComputeRunningProcesses_v3"},
+			pciVersion:        3,
+			busID:             "00000000:03:00.0",
+			busIDLegacy:       "0000:03:00.0",
+			processesVersion:  3,
+			gpuInstanceID:     3,
+			deviceCount:       2,
+			deviceCountSymbol: "nvmlDeviceGetCount",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newStubAPI(t, tt.exports...)
+
+			require.NoError(t, w.Init())
+			require.Equal(t, tt.pciVersion, w.SymbolVersion("nvmlDeviceGetPciInfo"))
+			require.Equal(t, tt.processesVersion, w.SymbolVersion("nvmlDeviceGetComputeRunningProcesses"))
+			require.True(t, w.Supports(tt.deviceCountSymbol))
+
+			count, err := w.DeviceGetCount()
+			require.NoError(t, err)
+			require.Equal(t, tt.deviceCount, count)
+
+			pci, err := w.DeviceGetPCIInfo(Device(0x1000))
+			require.NoError(t, err)
+			require.Equal(t, tt.busID, pci.BusID)
+			require.Equal(t, tt.busIDLegacy, pci.BusIDLegacy)
+			require.Equal(t, uint32(0x1db410de), pci.PCIDeviceID)
+
+			list, err := w.DeviceGetComputeRunningProcesses(Device(0x1000))
+			require.NoError(t, err)
+			require.Len(t, list, 2)
+			require.Equal(t, uint32(101), list[1].PID)
+			require.Equal(t, uint64(1<<20), list[1].UsedGPUMemory)
+			require.Equal(t, tt.gpuInstanceID, list[1].GPUInstanceID)
+		})
+	}
+}
+
+func TestNewLibraryNotFound(t *testing.T) {
+	_, err := New("/nonexistent/" + libraryName)
+	require.Error(t, err)
+	require.Equal(t, ErrLibraryNotFound, errors.Cause(err))
+}
@@ -0,0 +1,36 @@
+package nvml
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// dll wraps syscall.DLL to satisfy the library interface.
+type dll struct {
+	*syscall.DLL
+}
+
+func (d dll) FindProc(name string) (proc, error) {
+	p, err := d.DLL.FindProc(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// loadLibrary loads nvml.dll from path or, if path is empty, from the default NVSMI installation directory.
+func loadLibrary(path string) (library, error) {
+	if path == "" {
+		path = os.ExpandEnv("$ProgramW6432\\NVIDIA Corporation\\NVSMI\\nvml.dll")
+	}
+
+	lib, err := syscall.LoadDLL(path)
+	if err != nil {
+		return nil, errors.Wrapf(ErrLibraryNotFound, "failed to load %s: %v", path, err)
+	}
+
+	return dll{lib}, nil
+}
@@ -0,0 +1,85 @@
+package nvml
+
+import (
+	"github.com/pkg/errors"
+)
+
+// MemoryRepairMechanism is the way a device takes faulty memory out of service.
+type MemoryRepairMechanism int32
+
+//noinspection GoUnusedConst
+const (
+	// Faulty pages are retired, pre-Ampere devices (see DeviceGetRetiredPages).
+	MemoryRepairPageRetirement = MemoryRepairMechanism(0)
+	// Faulty rows are remapped to spare ones, Ampere or newer devices (see DeviceGetRemappedRows).
+	MemoryRepairRowRemapping = MemoryRepairMechanism(1)
+)
+
+// MemoryHealthStatus is the memory repair state of a device, see MemoryHealth.
+type MemoryHealthStatus struct {
+	Mechanism MemoryRepairMechanism
+	// Retired pages by cause, set with MemoryRepairPageRetirement
+	RetiredPages map[PageRetirementCause][]uint64
+	// Rows remapped due to correctable and uncorrectable errors, set with MemoryRepairRowRemapping
+	CorrectableRemappedRows   uint32
+	UncorrectableRemappedRows uint32
+	// Remap availability of the memory banks with MemoryRepairRowRemapping, nil when the driver doesn't report it
+	RowRemapperHistogram *RowRemapperHistogram
+	// Whether pages are pending retirement or rows are pending remapping, the device needs a reset to complete it
+	Pending bool
+	// Whether a row remapping ever failed, set with MemoryRepairRowRemapping
+	Failed bool
+}
+
+// MemoryHealth returns the memory repair state of a device, using row remapping on devices supporting it and
+// page retirement otherwise. The error is ErrNotSupported (use errors.Cause to check for it) when the device
+// supports neither.
+func MemoryHealth(lib Interface, device Device) (*MemoryHealthStatus, error) {
+	correctable, uncorrectable, pending, failed, err := lib.DeviceGetRemappedRows(device)
+	if err == nil {
+		status := &MemoryHealthStatus{
+			Mechanism:                 MemoryRepairRowRemapping,
+			CorrectableRemappedRows:   correctable,
+			UncorrectableRemappedRows: uncorrectable,
+			Pending:                   pending,
+			Failed:                    failed,
+		}
+
+		histogram, err := lib.DeviceGetRowRemapperHistogram(device)
+		if err == nil {
+			status.RowRemapperHistogram = &histogram
+		} else if err != ErrNotSupported && err != ErrFunctionNotFound {
+			return nil, errors.Wrap(err, "failed to get row remapper histogram")
+		}
+
+		return status, nil
+	}
+
+	if err != ErrNotSupported && err != ErrFunctionNotFound {
+		return nil, errors.Wrap(err, "failed to get remapped rows")
+	}
+
+	status := &MemoryHealthStatus{
+		Mechanism:    MemoryRepairPageRetirement,
+		RetiredPages: map[PageRetirementCause][]uint64{},
+	}
+
+	for _, cause := range []PageRetirementCause{
+		PageRetirementCauseMultipleSingleBitECCErrors,
+		PageRetirementCauseDoubleBitECCError,
+	} {
+		pages, err := lib.DeviceGetRetiredPages(device, cause)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get pages retired with cause %d", cause)
+		}
+
+		status.RetiredPages[cause] = pages
+	}
+
+	status.Pending, err = lib.DeviceGetRetiredPagesPendingStatus(device)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get retired pages pending status")
+	}
+
+	return status, nil
+}
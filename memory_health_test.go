@@ -0,0 +1,60 @@
+package nvml_test
+
+import (
+	"testing"
+
+	nvml "github.com/mxpv/nvml-go"
+	"github.com/mxpv/nvml-go/fake"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryHealthPageRetirement(t *testing.T) {
+	f := fake.New(1)
+	require.NoError(t, f.Init())
+	defer f.Shutdown()
+
+	f.Devices[0].RetiredPages[nvml.PageRetirementCauseDoubleBitECCError] = []uint64{0x1000}
+	f.Devices[0].RetiredPagesPending = true
+
+	status, err := nvml.MemoryHealth(f, fake.Handle(0))
+	require.NoError(t, err)
+	require.Equal(t, &nvml.MemoryHealthStatus{
+		Mechanism: nvml.MemoryRepairPageRetirement,
+		RetiredPages: map[nvml.PageRetirementCause][]uint64{
+			nvml.PageRetirementCauseMultipleSingleBitECCErrors: {},
+			nvml.PageRetirementCauseDoubleBitECCError:          {0x1000},
+		},
+		Pending: true,
+	}, status)
+}
+
+func TestMemoryHealthRowRemapping(t *testing.T) {
+	f := fake.New(0)
+	f.Devices = []*fake.Device{fake.NewMigDevice(0)}
+	require.NoError(t, f.Init())
+	defer f.Shutdown()
+
+	f.Devices[0].RemappedRows.Uncorrectable = 2
+	f.Devices[0].RemappedRows.FailureOccurred = true
+
+	status, err := nvml.MemoryHealth(f, fake.Handle(0))
+	require.NoError(t, err)
+	require.Equal(t, &nvml.MemoryHealthStatus{
+		Mechanism:                 nvml.MemoryRepairRowRemapping,
+		UncorrectableRemappedRows: 2,
+		RowRemapperHistogram:      &nvml.RowRemapperHistogram{Max: 640},
+		Failed:                    true,
+	}, status)
+}
+
+func TestMemoryHealthNotSupported(t *testing.T) {
+	f := fake.New(1)
+	require.NoError(t, f.Init())
+	defer f.Shutdown()
+
+	f.Devices[0].RetiredPages = nil
+
+	_, err := nvml.MemoryHealth(f, fake.Handle(0))
+	require.Equal(t, nvml.ErrNotSupported, errors.Cause(err))
+}
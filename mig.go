@@ -0,0 +1,280 @@
+package nvml
+
+// maxGpuInstancePlacements is the size of the placement array passed to the legacy (v1)
+// nvmlDeviceGetGpuInstancePossiblePlacements, which can't report the required size.
+// GPU instances can't start past the last memory slice, and MIG capable devices have at most 8 of them.
+const maxGpuInstancePlacements = 8
+
+// DeviceGetMigMode gets MIG mode for the device.
+// Changing MIG modes may require device unbind or reset. The "pending" MIG mode refers to the target mode following
+// the next activation trigger.
+// For Ampere or newer fully supported devices.
+func (a API) DeviceGetMigMode(device Device) (current, pending MigMode, err error) {
+	err = a.call(a.nvmlDeviceGetMigMode, uintptr(device), &current, &pending)
+	return
+}
+
+// DeviceSetMigMode sets MIG mode for the device.
+// Requires root user.
+// This mode determines whether a GPU instance can be created.
+// This API may unbind or reset the device to activate the requested mode. Thus, the attributes associated with the
+// device, such as minor number, might change. The caller of this API is expected to query such attributes again.
+// When the requested mode is set as pending but can't be activated, the activation status is returned as the error.
+// On certain platforms like pass-through virtualization, where reset functionality may not be exposed directly,
+// VM reboot is required and ErrResetRequired is returned. If device unbind fails because the device isn't idle,
+// ErrInUse is returned. The caller of this API is expected to idle the device and retry setting the mode.
+// For Ampere or newer fully supported devices.
+func (a API) DeviceSetMigMode(device Device, mode MigMode) error {
+	var status int32
+	if err := a.call(a.nvmlDeviceSetMigMode, uintptr(device), uintptr(mode), &status); err != nil {
+		return err
+	}
+
+	return returnValueToError(int(status))
+}
+
+// DeviceGetGpuInstanceProfileInfo gets GPU instance profile information.
+// Information provided by this API is immutable throughout the lifetime of a MIG mode.
+// For Ampere or newer fully supported devices. Supported on Linux only.
+func (a API) DeviceGetGpuInstanceProfileInfo(device Device, profile GpuInstanceProfile) (info GpuInstanceProfileInfo, err error) {
+	err = a.call(a.nvmlDeviceGetGpuInstanceProfileInfo, uintptr(device), uintptr(profile), &info)
+	return
+}
+
+// DeviceGetGpuInstancePossiblePlacements gets GPU instance placements.
+// A placement represents the location of a GPU instance within a device. This API only returns all the possible
+// placements for the given profile.
+// A created GPU instance occupies memory slices described by its placement. Creation of new GPU instance will fail
+// if there is overlap with the already occupied memory slices.
+// For Ampere or newer fully supported devices. Supported on Linux only. Requires privileged user.
+func (a API) DeviceGetGpuInstancePossiblePlacements(device Device, profileID uint32) ([]GpuInstancePlacement, error) {
+	var count uint32
+
+	if a.versions["nvmlDeviceGetGpuInstancePossiblePlacements"] < 2 {
+		placements := make([]GpuInstancePlacement, maxGpuInstancePlacements)
+		if err := a.call(a.nvmlDeviceGetGpuInstancePossiblePlacements, uintptr(device), uintptr(profileID), placements, &count); err != nil {
+			return nil, err
+		}
+
+		return placements[:count], nil
+	}
+
+	// Get array size
+	if err := a.call(a.nvmlDeviceGetGpuInstancePossiblePlacements, uintptr(device), uintptr(profileID), 0, &count); err != nil {
+		return nil, err
+	}
+
+	if count == 0 {
+		return []GpuInstancePlacement{}, nil
+	}
+
+	placements := make([]GpuInstancePlacement, count)
+	if err := a.call(a.nvmlDeviceGetGpuInstancePossiblePlacements, uintptr(device), uintptr(profileID), placements, &count); err != nil {
+		return nil, err
+	}
+
+	return placements[:count], nil
+}
+
+// DeviceGetGpuInstanceRemainingCapacity gets GPU instance profile capacity.
+// For Ampere or newer fully supported devices. Supported on Linux only. Requires privileged user.
+func (a API) DeviceGetGpuInstanceRemainingCapacity(device Device, profileID uint32) (count uint32, err error) {
+	err = a.call(a.nvmlDeviceGetGpuInstanceRemainingCapacity, uintptr(device), uintptr(profileID), &count)
+	return
+}
+
+// DeviceCreateGpuInstance creates GPU instance.
+// If the parent device is unbound, reset or the GPU instance is destroyed explicitly, the GPU instance handle would
+// become invalid. The GPU instance must be recreated to acquire a valid handle.
+// For Ampere or newer fully supported devices. Supported on Linux only. Requires privileged user.
+func (a API) DeviceCreateGpuInstance(device Device, profileID uint32) (gpuInstance GpuInstance, err error) {
+	err = a.call(a.nvmlDeviceCreateGpuInstance, uintptr(device), uintptr(profileID), &gpuInstance)
+	return
+}
+
+// DeviceCreateGpuInstanceWithPlacement creates GPU instance with the specified placement.
+// If the parent device is unbound, reset or the GPU instance is destroyed explicitly, the GPU instance handle would
+// become invalid. The GPU instance must be recreated to acquire a valid handle.
+// For Ampere or newer fully supported devices. Supported on Linux only. Requires privileged user.
+func (a API) DeviceCreateGpuInstanceWithPlacement(device Device, profileID uint32, placement GpuInstancePlacement) (gpuInstance GpuInstance, err error) {
+	err = a.call(a.nvmlDeviceCreateGpuInstanceWithPlacement, uintptr(device), uintptr(profileID), &placement, &gpuInstance)
+	return
+}
+
+// GpuInstanceDestroy destroys GPU instance.
+// For Ampere or newer fully supported devices. Supported on Linux only. Requires privileged user.
+func (a API) GpuInstanceDestroy(gpuInstance GpuInstance) error {
+	return a.call(a.nvmlGpuInstanceDestroy, uintptr(gpuInstance))
+}
+
+// DeviceGetGpuInstances gets GPU instances for given profile.
+// The list is sized after info.InstanceCount, as returned by DeviceGetGpuInstanceProfileInfo.
+// For Ampere or newer fully supported devices. Supported on Linux only. Requires privileged user.
+func (a API) DeviceGetGpuInstances(device Device, info GpuInstanceProfileInfo) ([]GpuInstance, error) {
+	if info.InstanceCount == 0 {
+		return []GpuInstance{}, nil
+	}
+
+	count := info.InstanceCount
+	list := make([]GpuInstance, count)
+	if err := a.call(a.nvmlDeviceGetGpuInstances, uintptr(device), uintptr(info.ID), list, &count); err != nil {
+		return nil, err
+	}
+
+	return list[:count], nil
+}
+
+// DeviceGetGpuInstanceByID gets GPU instance for given instance ID.
+// For Ampere or newer fully supported devices. Supported on Linux only. Requires privileged user.
+func (a API) DeviceGetGpuInstanceByID(device Device, id uint32) (gpuInstance GpuInstance, err error) {
+	err = a.call(a.nvmlDeviceGetGpuInstanceById, uintptr(device), uintptr(id), &gpuInstance)
+	return
+}
+
+// GpuInstanceGetInfo gets GPU instance information.
+// For Ampere or newer fully supported devices. Supported on Linux only.
+func (a API) GpuInstanceGetInfo(gpuInstance GpuInstance) (info GpuInstanceInfo, err error) {
+	err = a.call(a.nvmlGpuInstanceGetInfo, uintptr(gpuInstance), &info)
+	return
+}
+
+// GpuInstanceGetComputeInstanceProfileInfo gets compute instance profile information.
+// Information provided by this API is immutable throughout the lifetime of a MIG mode.
+// For Ampere or newer fully supported devices. Supported on Linux only.
+func (a API) GpuInstanceGetComputeInstanceProfileInfo(gpuInstance GpuInstance, profile ComputeInstanceProfile, engProfile ComputeInstanceEngineProfile) (info ComputeInstanceProfileInfo, err error) {
+	err = a.call(a.nvmlGpuInstanceGetComputeInstanceProfileInfo, uintptr(gpuInstance), uintptr(profile), uintptr(engProfile), &info)
+	return
+}
+
+// GpuInstanceGetComputeInstanceRemainingCapacity gets compute instance profile capacity.
+// For Ampere or newer fully supported devices. Supported on Linux only. Requires privileged user.
+func (a API) GpuInstanceGetComputeInstanceRemainingCapacity(gpuInstance GpuInstance, profileID uint32) (count uint32, err error) {
+	err = a.call(a.nvmlGpuInstanceGetComputeInstanceRemainingCapacity, uintptr(gpuInstance), uintptr(profileID), &count)
+	return
+}
+
+// GpuInstanceCreateComputeInstance creates compute instance.
+// If the parent device is unbound, reset or the parent GPU instance is destroyed or the compute instance is
+// destroyed explicitly, the compute instance handle would become invalid. The compute instance must be recreated to
+// acquire a valid handle.
+// For Ampere or newer fully supported devices. Supported on Linux only. Requires privileged user.
+func (a API) GpuInstanceCreateComputeInstance(gpuInstance GpuInstance, profileID uint32) (computeInstance ComputeInstance, err error) {
+	err = a.call(a.nvmlGpuInstanceCreateComputeInstance, uintptr(gpuInstance), uintptr(profileID), &computeInstance)
+	return
+}
+
+// ComputeInstanceDestroy destroys compute instance.
+// For Ampere or newer fully supported devices. Supported on Linux only. Requires privileged user.
+func (a API) ComputeInstanceDestroy(computeInstance ComputeInstance) error {
+	return a.call(a.nvmlComputeInstanceDestroy, uintptr(computeInstance))
+}
+
+// GpuInstanceGetComputeInstances gets compute instances for given profile.
+// The list is sized after info.InstanceCount, as returned by GpuInstanceGetComputeInstanceProfileInfo.
+// For Ampere or newer fully supported devices. Supported on Linux only. Requires privileged user.
+func (a API) GpuInstanceGetComputeInstances(gpuInstance GpuInstance, info ComputeInstanceProfileInfo) ([]ComputeInstance, error) {
+	if info.InstanceCount == 0 {
+		return []ComputeInstance{}, nil
+	}
+
+	count := info.InstanceCount
+	list := make([]ComputeInstance, count)
+	if err := a.call(a.nvmlGpuInstanceGetComputeInstances, uintptr(gpuInstance), uintptr(info.ID), list, &count); err != nil {
+		return nil, err
+	}
+
+	return list[:count], nil
+}
+
+// GpuInstanceGetComputeInstanceByID gets compute instance for given instance ID.
+// For Ampere or newer fully supported devices. Supported on Linux only. Requires privileged user.
+func (a API) GpuInstanceGetComputeInstanceByID(gpuInstance GpuInstance, id uint32) (computeInstance ComputeInstance, err error) {
+	err = a.call(a.nvmlGpuInstanceGetComputeInstanceById, uintptr(gpuInstance), uintptr(id), &computeInstance)
+	return
+}
+
+// computeInstanceInfoV1 mirrors the nvmlComputeInstanceInfo_t layout expected by the legacy (v1)
+// nvmlComputeInstanceGetInfo, which doesn't report the placement.
+type computeInstanceInfoV1 struct {
+	Device      Device
+	GpuInstance GpuInstance
+	ID          uint32
+	ProfileID   uint32
+}
+
+// ComputeInstanceGetInfo gets compute instance information.
+// For Ampere or newer fully supported devices. Supported on Linux only.
+func (a API) ComputeInstanceGetInfo(computeInstance ComputeInstance) (ComputeInstanceInfo, error) {
+	if a.versions["nvmlComputeInstanceGetInfo"] < 2 {
+		var raw computeInstanceInfoV1
+		if err := a.call(a.nvmlComputeInstanceGetInfo, uintptr(computeInstance), &raw); err != nil {
+			return ComputeInstanceInfo{}, err
+		}
+
+		return ComputeInstanceInfo{
+			Device:      raw.Device,
+			GpuInstance: raw.GpuInstance,
+			ID:          raw.ID,
+			ProfileID:   raw.ProfileID,
+		}, nil
+	}
+
+	var info ComputeInstanceInfo
+	err := a.call(a.nvmlComputeInstanceGetInfo, uintptr(computeInstance), &info)
+	return info, err
+}
+
+// DeviceIsMigDeviceHandle tests if the given handle refers to a MIG device.
+// A MIG device handle is an NVML abstraction which maps to a MIG compute instance. These overloaded references can
+// be used (with some restrictions) interchangeably with a GPU device handle to execute queries at a per-compute
+// instance granularity.
+// For Ampere or newer fully supported devices. Supported on Linux only.
+func (a API) DeviceIsMigDeviceHandle(device Device) (bool, error) {
+	var isMigDevice uint32
+	if err := a.call(a.nvmlDeviceIsMigDeviceHandle, uintptr(device), &isMigDevice); err != nil {
+		return false, err
+	}
+
+	return isMigDevice != 0, nil
+}
+
+// DeviceGetGpuInstanceID gets GPU instance ID for the given MIG device handle.
+// GPU instance IDs are unique per device and remain valid until the GPU instance is destroyed.
+// For Ampere or newer fully supported devices. Supported on Linux only.
+func (a API) DeviceGetGpuInstanceID(device Device) (id uint32, err error) {
+	err = a.call(a.nvmlDeviceGetGpuInstanceId, uintptr(device), &id)
+	return
+}
+
+// DeviceGetComputeInstanceID gets compute instance ID for the given MIG device handle.
+// Compute instance IDs are unique per GPU instance and remain valid until the compute instance is destroyed.
+// For Ampere or newer fully supported devices. Supported on Linux only.
+func (a API) DeviceGetComputeInstanceID(device Device) (id uint32, err error) {
+	err = a.call(a.nvmlDeviceGetComputeInstanceId, uintptr(device), &id)
+	return
+}
+
+// DeviceGetMaxMigDeviceCount gets the maximum number of MIG devices that can exist under a given parent NVML device.
+// Returns zero if MIG is not supported or enabled.
+// For Ampere or newer fully supported devices. Supported on Linux only.
+func (a API) DeviceGetMaxMigDeviceCount(device Device) (count uint32, err error) {
+	err = a.call(a.nvmlDeviceGetMaxMigDeviceCount, uintptr(device), &count)
+	return
+}
+
+// DeviceGetMigDeviceHandleByIndex gets MIG device handle for the given index under its parent NVML device.
+// If the compute instance is destroyed either explicitly or by destroying, resetting or unbinding the parent GPU
+// instance or the GPU device itself the MIG device handle would remain invalid and must be requested again using
+// this API. Handles may be reused and their properties can change in the process.
+// For Ampere or newer fully supported devices. Supported on Linux only.
+func (a API) DeviceGetMigDeviceHandleByIndex(device Device, index uint32) (migDevice Device, err error) {
+	err = a.call(a.nvmlDeviceGetMigDeviceHandleByIndex, uintptr(device), uintptr(index), &migDevice)
+	return
+}
+
+// DeviceGetDeviceHandleFromMigDeviceHandle gets parent device handle from a MIG device handle.
+// For Ampere or newer fully supported devices. Supported on Linux only.
+func (a API) DeviceGetDeviceHandleFromMigDeviceHandle(migDevice Device) (device Device, err error) {
+	err = a.call(a.nvmlDeviceGetDeviceHandleFromMigDeviceHandle, uintptr(migDevice), &device)
+	return
+}
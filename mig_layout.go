@@ -0,0 +1,328 @@
+package nvml
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// MigInstance describes a GPU instance of a MigLayout, along with the compute instances it's split into.
+type MigInstance struct {
+	Profile          GpuInstanceProfile
+	ComputeInstances []ComputeInstanceProfile
+}
+
+// MigLayout is the set of GPU instances a device in MIG mode should be partitioned into (see ApplyMigLayout).
+type MigLayout []MigInstance
+
+// equal reports whether both instances have the same profile and the same compute instance profiles, in any order.
+func (m MigInstance) equal(other MigInstance) bool {
+	if m.Profile != other.Profile || len(m.ComputeInstances) != len(other.ComputeInstances) {
+		return false
+	}
+
+	a, b := m.sortedComputeInstances(), other.sortedComputeInstances()
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m MigInstance) sortedComputeInstances() []ComputeInstanceProfile {
+	list := append([]ComputeInstanceProfile{}, m.ComputeInstances...)
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+	return list
+}
+
+// migGpuInstance is a GPU instance found on a device.
+type migGpuInstance struct {
+	handle           GpuInstance
+	placement        GpuInstancePlacement
+	instance         MigInstance
+	computeInstances []ComputeInstance
+}
+
+// ApplyMigLayout partitions a device in MIG mode according to layout.
+// GPU instances matching an entry of the layout (same GPU instance profile and same compute instance profiles) are
+// kept, all the others are destroyed along with their compute instances. The missing GPU instances are then created,
+// largest first, at placements that fit together with the kept ones, and split into the requested compute instances.
+//
+// Nothing is changed when the device or any of its MIG devices runs compute processes (the error is ErrInUse) or
+// when the layout can't fit on the device (the error is ErrInsufficientResources). Use errors.Cause to check for them.
+//
+// Instances aren't rolled back when destroying or creating one fails: the device is left partially repartitioned
+// and the error says "MIG layout partially applied". As matching instances are kept, applying the same layout
+// again resumes from there and converges once the failure is gone, so a failed apply should be retried.
+func ApplyMigLayout(lib Interface, device Device, layout MigLayout) error {
+	mode, _, err := lib.DeviceGetMigMode(device)
+	if err != nil {
+		return errors.Wrap(err, "failed to get MIG mode")
+	}
+
+	if mode != MigModeEnabled {
+		return errors.Wrap(ErrNotSupported, "MIG mode is not enabled")
+	}
+
+	if err := checkMigProcesses(lib, device); err != nil {
+		return err
+	}
+
+	current, err := migGpuInstances(lib, device)
+	if err != nil {
+		return err
+	}
+
+	// Keep the GPU instances already matching the layout
+	kept := make([]bool, len(current))
+	var missing []MigInstance
+	for _, want := range layout {
+		found := false
+		for i, gi := range current {
+			if !kept[i] && gi.instance.equal(want) {
+				kept[i], found = true, true
+				break
+			}
+		}
+
+		if !found {
+			missing = append(missing, want)
+		}
+	}
+
+	var occupied []GpuInstancePlacement
+	for i, gi := range current {
+		if kept[i] {
+			occupied = append(occupied, gi.placement)
+		}
+	}
+
+	plan, err := planMigPlacements(lib, device, missing, occupied)
+	if err != nil {
+		return err
+	}
+
+	if err := applyMigPlan(lib, device, current, kept, plan); err != nil {
+		return errors.Wrap(err, "MIG layout partially applied")
+	}
+
+	return nil
+}
+
+// applyMigPlan destroys the GPU instances that aren't kept, then creates the planned ones.
+func applyMigPlan(lib Interface, device Device, current []migGpuInstance, kept []bool, plan []migPlannedInstance) error {
+	for i, gi := range current {
+		if kept[i] {
+			continue
+		}
+
+		for _, ci := range gi.computeInstances {
+			if err := lib.ComputeInstanceDestroy(ci); err != nil {
+				return errors.Wrapf(err, "failed to destroy compute instance of GPU instance at slice %d", gi.placement.Start)
+			}
+		}
+
+		if err := lib.GpuInstanceDestroy(gi.handle); err != nil {
+			return errors.Wrapf(err, "failed to destroy GPU instance at slice %d", gi.placement.Start)
+		}
+	}
+
+	for _, p := range plan {
+		gi, err := lib.DeviceCreateGpuInstanceWithPlacement(device, p.profile.ID, p.placement)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create GPU instance with profile %d at slice %d", p.profile.ID, p.placement.Start)
+		}
+
+		if err := createComputeInstances(lib, gi, p.instance.ComputeInstances); err != nil {
+			return errors.Wrapf(err, "failed to split GPU instance at slice %d", p.placement.Start)
+		}
+	}
+
+	return nil
+}
+
+// checkMigProcesses fails with ErrInUse when compute processes run on the device or on any of its MIG devices.
+// The processes of all the MIG devices are only reported on the parent device to privileged callers, so each
+// MIG device is checked as well.
+func checkMigProcesses(lib Interface, device Device) error {
+	processes, err := lib.DeviceGetComputeRunningProcesses(device)
+	if err != nil {
+		return errors.Wrap(err, "failed to get running compute processes")
+	}
+
+	if len(processes) > 0 {
+		return errors.Wrapf(ErrInUse, "%d compute processes are running", len(processes))
+	}
+
+	count, err := lib.DeviceGetMaxMigDeviceCount(device)
+	if err != nil {
+		return errors.Wrap(err, "failed to get max MIG device count")
+	}
+
+	for i := uint32(0); i < count; i++ {
+		migDevice, err := lib.DeviceGetMigDeviceHandleByIndex(device, i)
+		if err == ErrNotFound {
+			continue
+		} else if err != nil {
+			return errors.Wrapf(err, "failed to get MIG device %d", i)
+		}
+
+		processes, err := lib.DeviceGetComputeRunningProcesses(migDevice)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get running compute processes of MIG device %d", i)
+		}
+
+		if len(processes) > 0 {
+			return errors.Wrapf(ErrInUse, "%d compute processes are running on MIG device %d", len(processes), i)
+		}
+	}
+
+	return nil
+}
+
+// migGpuInstances lists the GPU instances of a device along with their compute instances.
+func migGpuInstances(lib Interface, device Device) ([]migGpuInstance, error) {
+	var list []migGpuInstance
+
+	for profile := GpuInstanceProfile(0); profile < GpuInstanceProfileCount; profile++ {
+		info, err := lib.DeviceGetGpuInstanceProfileInfo(device, profile)
+		if err == ErrNotSupported || err == ErrInvalidArgument {
+			continue
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "failed to get GPU instance profile %d", profile)
+		}
+
+		handles, err := lib.DeviceGetGpuInstances(device, info)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get GPU instances with profile %d", info.ID)
+		}
+
+		for _, handle := range handles {
+			gi := migGpuInstance{handle: handle, instance: MigInstance{Profile: profile}}
+
+			giInfo, err := lib.GpuInstanceGetInfo(handle)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get info of GPU instance with profile %d", info.ID)
+			}
+
+			gi.placement = giInfo.Placement
+
+			for ciProfile := ComputeInstanceProfile(0); ciProfile < ComputeInstanceProfileCount; ciProfile++ {
+				ciInfo, err := lib.GpuInstanceGetComputeInstanceProfileInfo(handle, ciProfile, ComputeInstanceEngineProfileShared)
+				if err == ErrNotSupported || err == ErrInvalidArgument {
+					continue
+				} else if err != nil {
+					return nil, errors.Wrapf(err, "failed to get compute instance profile %d", ciProfile)
+				}
+
+				cis, err := lib.GpuInstanceGetComputeInstances(handle, ciInfo)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to get compute instances with profile %d", ciInfo.ID)
+				}
+
+				for _, ci := range cis {
+					gi.instance.ComputeInstances = append(gi.instance.ComputeInstances, ciProfile)
+					gi.computeInstances = append(gi.computeInstances, ci)
+				}
+			}
+
+			list = append(list, gi)
+		}
+	}
+
+	return list, nil
+}
+
+// migPlannedInstance is a GPU instance to create.
+type migPlannedInstance struct {
+	instance  MigInstance
+	profile   GpuInstanceProfileInfo
+	placement GpuInstancePlacement
+	// Placements the GPU instance profile allows
+	possible []GpuInstancePlacement
+}
+
+// planMigPlacements picks a placement for each of the instances, not overlapping the occupied ones nor each other.
+// Instances are placed largest first, backtracking when the remaining ones don't fit. The plan is returned in the
+// same order, which is the order to create the instances in.
+func planMigPlacements(lib Interface, device Device, instances []MigInstance, occupied []GpuInstancePlacement) ([]migPlannedInstance, error) {
+	plan := make([]migPlannedInstance, len(instances))
+	for i, instance := range instances {
+		info, err := lib.DeviceGetGpuInstanceProfileInfo(device, instance.Profile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get GPU instance profile %d", instance.Profile)
+		}
+
+		possible, err := lib.DeviceGetGpuInstancePossiblePlacements(device, info.ID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get placements of GPU instance profile %d", info.ID)
+		}
+
+		plan[i] = migPlannedInstance{instance: instance, profile: info, possible: possible}
+	}
+
+	sort.SliceStable(plan, func(i, j int) bool {
+		return plan[i].profile.SliceCount > plan[j].profile.SliceCount
+	})
+
+	var place func(n int, occupied []GpuInstancePlacement) bool
+	place = func(n int, occupied []GpuInstancePlacement) bool {
+		if n == len(plan) {
+			return true
+		}
+
+		for _, p := range plan[n].possible {
+			if placementOverlaps(p, occupied) {
+				continue
+			}
+
+			plan[n].placement = p
+			if place(n+1, append(occupied[:len(occupied):len(occupied)], p)) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if !place(0, occupied) {
+		return nil, errors.Wrap(ErrInsufficientResources, "MIG layout doesn't fit on the device")
+	}
+
+	return plan, nil
+}
+
+func placementOverlaps(p GpuInstancePlacement, occupied []GpuInstancePlacement) bool {
+	for _, o := range occupied {
+		if p.Start < o.Start+o.Size && o.Start < p.Start+p.Size {
+			return true
+		}
+	}
+
+	return false
+}
+
+// createComputeInstances splits a GPU instance into compute instances of the given profiles, largest first.
+func createComputeInstances(lib Interface, gi GpuInstance, profiles []ComputeInstanceProfile) error {
+	infos := make([]ComputeInstanceProfileInfo, len(profiles))
+	for i, profile := range profiles {
+		info, err := lib.GpuInstanceGetComputeInstanceProfileInfo(gi, profile, ComputeInstanceEngineProfileShared)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get compute instance profile %d", profile)
+		}
+
+		infos[i] = info
+	}
+
+	sort.SliceStable(infos, func(i, j int) bool { return infos[i].SliceCount > infos[j].SliceCount })
+
+	for _, info := range infos {
+		if _, err := lib.GpuInstanceCreateComputeInstance(gi, info.ID); err != nil {
+			return errors.Wrapf(err, "failed to create compute instance with profile %d", info.ID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package nvml_test
+
+import (
+	"testing"
+
+	nvml "github.com/mxpv/nvml-go"
+	"github.com/mxpv/nvml-go/fake"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func createMigFake(t *testing.T) *fake.Fake {
+	f := fake.New(0)
+	f.Devices = []*fake.Device{fake.NewMigDevice(0)}
+	f.Devices[0].MigMode = nvml.MigModeEnabled
+	require.NoError(t, f.Init())
+	return f
+}
+
+// migSummary lists the GPU instances of the first device as placement start -> compute instance count.
+func migSummary(f *fake.Fake) map[uint32]int {
+	summary := map[uint32]int{}
+	for _, gi := range f.Devices[0].GpuInstances {
+		summary[gi.Info.Placement.Start] = len(gi.ComputeInstances)
+	}
+
+	return summary
+}
+
+func TestApplyMigLayout(t *testing.T) {
+	f := createMigFake(t)
+	defer f.Shutdown()
+
+	device := fake.Handle(0)
+
+	// 1g instances are listed first, but the 4g one must take slices 0-3
+	layout := nvml.MigLayout{
+		{Profile: nvml.GpuInstanceProfile1Slice, ComputeInstances: []nvml.ComputeInstanceProfile{nvml.ComputeInstanceProfile1Slice}},
+		{Profile: nvml.GpuInstanceProfile2Slice, ComputeInstances: []nvml.ComputeInstanceProfile{
+			nvml.ComputeInstanceProfile1Slice,
+			nvml.ComputeInstanceProfile1Slice,
+		}},
+		{Profile: nvml.GpuInstanceProfile4Slice, ComputeInstances: []nvml.ComputeInstanceProfile{
+			nvml.ComputeInstanceProfile1Slice,
+			nvml.ComputeInstanceProfile2Slice,
+		}},
+	}
+
+	require.NoError(t, nvml.ApplyMigLayout(f, device, layout))
+	require.Equal(t, map[uint32]int{0: 2, 4: 2, 6: 1}, migSummary(f))
+
+	kept := f.Devices[0].GpuInstances[0]
+
+	// Applying the same layout is a no-op
+	require.NoError(t, nvml.ApplyMigLayout(f, device, layout))
+	require.Equal(t, map[uint32]int{0: 2, 4: 2, 6: 1}, migSummary(f))
+
+	// The 4g instance is kept, the others are replaced
+	layout = nvml.MigLayout{
+		layout[2],
+		{Profile: nvml.GpuInstanceProfile3Slice, ComputeInstances: []nvml.ComputeInstanceProfile{nvml.ComputeInstanceProfile3Slice}},
+	}
+
+	require.NoError(t, nvml.ApplyMigLayout(f, device, layout))
+	require.Equal(t, map[uint32]int{0: 2, 4: 1}, migSummary(f))
+	require.Same(t, kept, f.Devices[0].GpuInstances[0])
+
+	require.NoError(t, nvml.ApplyMigLayout(f, device, nil))
+	require.Empty(t, f.Devices[0].GpuInstances)
+}
+
+func TestApplyMigLayoutRefused(t *testing.T) {
+	f := createMigFake(t)
+	defer f.Shutdown()
+
+	device := fake.Handle(0)
+	half := nvml.MigInstance{Profile: nvml.GpuInstanceProfile3Slice}
+	require.NoError(t, nvml.ApplyMigLayout(f, device, nvml.MigLayout{half}))
+
+	// Doesn't fit, nothing is destroyed
+	err := nvml.ApplyMigLayout(f, device, nvml.MigLayout{{Profile: nvml.GpuInstanceProfile7Slice}, half})
+	require.Equal(t, nvml.ErrInsufficientResources, errors.Cause(err))
+	require.Len(t, f.Devices[0].GpuInstances, 1)
+
+	f.Devices[0].ComputeProcesses = []nvml.ProcessInfo{{PID: 1234}}
+	err = nvml.ApplyMigLayout(f, device, nil)
+	require.Equal(t, nvml.ErrInUse, errors.Cause(err))
+	require.Len(t, f.Devices[0].GpuInstances, 1)
+
+	f.Devices[0].ComputeProcesses = nil
+
+	// Processes of MIG devices aren't reported on the parent device to unprivileged callers
+	require.NoError(t, nvml.ApplyMigLayout(f, device, nvml.MigLayout{{
+		Profile:          nvml.GpuInstanceProfile3Slice,
+		ComputeInstances: []nvml.ComputeInstanceProfile{nvml.ComputeInstanceProfile3Slice},
+	}}))
+
+	migDevice, err := f.DeviceGetMigDeviceHandleByIndex(device, 0)
+	require.NoError(t, err)
+	f.Devices[0].GpuInstances[0].ComputeInstances[0].Device.ComputeProcesses = []nvml.ProcessInfo{{PID: 1234}}
+
+	processes, err := f.DeviceGetComputeRunningProcesses(migDevice)
+	require.NoError(t, err)
+	require.Len(t, processes, 1)
+
+	err = nvml.ApplyMigLayout(f, device, nil)
+	require.Equal(t, nvml.ErrInUse, errors.Cause(err))
+	require.Len(t, f.Devices[0].GpuInstances, 1)
+
+	f.Devices[0].MigMode = nvml.MigModeDisabled
+	err = nvml.ApplyMigLayout(f, device, nil)
+	require.Equal(t, nvml.ErrNotSupported, errors.Cause(err))
+}
+
+func TestApplyMigLayoutRetry(t *testing.T) {
+	f := createMigFake(t)
+	defer f.Shutdown()
+
+	device := fake.Handle(0)
+	require.NoError(t, nvml.ApplyMigLayout(f, device, nvml.MigLayout{{Profile: nvml.GpuInstanceProfile7Slice}}))
+
+	layout := nvml.MigLayout{
+		{Profile: nvml.GpuInstanceProfile3Slice, ComputeInstances: []nvml.ComputeInstanceProfile{nvml.ComputeInstanceProfile3Slice}},
+		{Profile: nvml.GpuInstanceProfile3Slice, ComputeInstances: []nvml.ComputeInstanceProfile{nvml.ComputeInstanceProfile3Slice}},
+	}
+
+	// The 7g instance is destroyed and the first 3g one created, but not split
+	f.Errors["GpuInstanceCreateComputeInstance"] = nvml.ErrUnknown
+	err := nvml.ApplyMigLayout(f, device, layout)
+	require.Equal(t, nvml.ErrUnknown, errors.Cause(err))
+	require.Contains(t, err.Error(), "MIG layout partially applied")
+	require.Equal(t, map[uint32]int{0: 0}, migSummary(f))
+
+	delete(f.Errors, "GpuInstanceCreateComputeInstance")
+	require.NoError(t, nvml.ApplyMigLayout(f, device, layout))
+	require.Equal(t, map[uint32]int{0: 1, 4: 1}, migSummary(f))
+}
@@ -0,0 +1,201 @@
+// +build linux,cgo
+
+package nvml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The MIG entry points of the stub library, see newStubLibrary.
+func init() {
+	addStubFunctions(`
+typedef struct {
+	unsigned int id, isP2pSupported, sliceCount, instanceCount, multiprocessorCount;
+	unsigned int copyEngineCount, decoderCount, encoderCount, jpegCount, ofaCount;
+	unsigned long long memorySizeMB;
+} gpuInstanceProfileInfo;
+
+typedef struct {
+	unsigned int start, size;
+} placement;
+
+typedef struct {
+	void *device;
+	unsigned int id, profileId;
+	placement placement;
+} gpuInstanceInfo;
+
+typedef struct {
+	void *device, *gpuInstance;
+	unsigned int id, profileId;
+} computeInstanceInfoV1;
+
+typedef struct {
+	void *device, *gpuInstance;
+	unsigned int id, profileId;
+	placement placement;
+} computeInstanceInfo;
+`, map[string]string{
+		"nvmlDeviceGetMigMode": `int nvmlDeviceGetMigMode(void *d, unsigned int *current, unsigned int *pending) {
+			*current = 0;
+			*pending = 1;
+			return 0;
+		}`,
+		"nvmlDeviceSetMigMode": `int nvmlDeviceSetMigMode(void *d, unsigned int mode, int *activationStatus) {
+			if (mode > 1) return 2;
+			*activationStatus = 19;
+			return 0;
+		}`,
+		"nvmlDeviceGetGpuInstanceProfileInfo": `int nvmlDeviceGetGpuInstanceProfileInfo(void *d, unsigned int profile, gpuInstanceProfileInfo *info) {
+			if (profile > 9) return 2;
+			info->id = 19;
+			info->sliceCount = 1;
+			info->instanceCount = 7;
+			info->multiprocessorCount = 14;
+			info->ofaCount = 1;
+			info->memorySizeMB = 4864;
+			return 0;
+		}`,
+		"nvmlDeviceGetGpuInstancePossiblePlacements": `int nvmlDeviceGetGpuInstancePossiblePlacements(void *d, unsigned int profileId,
+			placement *placements, unsigned int *count) {
+			for (unsigned int i = 0; i < 2; i++) {
+				placements[i].start = i * 4;
+				placements[i].size = 4;
+			}
+			*count = 2;
+			return 0;
+		}`,
+		"nvmlDeviceGetGpuInstancePossiblePlacements_v2": `int nvmlDeviceGetGpuInstancePossiblePlacements_v2(void *d, unsigned int profileId,
+			placement *placements, unsigned int *count) {
+			if (placements == NULL) { *count = 7; return 0; }
+			if (*count < 7) return 7;
+			for (unsigned int i = 0; i < 7; i++) {
+				placements[i].start = i;
+				placements[i].size = 1;
+			}
+			*count = 7;
+			return 0;
+		}`,
+		"nvmlDeviceGetGpuInstances": `int nvmlDeviceGetGpuInstances(void *d, unsigned int profileId, void **instances, unsigned int *count) {
+			if (*count < 2) return 7;
+			instances[0] = (void *)(uintptr_t)0x3001;
+			instances[1] = (void *)(uintptr_t)0x3002;
+			*count = 2;
+			return 0;
+		}`,
+		"nvmlGpuInstanceGetInfo": `int nvmlGpuInstanceGetInfo(void *gi, gpuInstanceInfo *info) {
+			info->device = (void *)(uintptr_t)0x1000;
+			info->id = 1;
+			info->profileId = 9;
+			info->placement.start = 4;
+			info->placement.size = 4;
+			return 0;
+		}`,
+		"nvmlComputeInstanceGetInfo": `int nvmlComputeInstanceGetInfo(void *ci, computeInstanceInfoV1 *info) {
+			info->device = (void *)(uintptr_t)0x1000;
+			info->gpuInstance = (void *)(uintptr_t)0x3001;
+			info->id = 2;
+			info->profileId = 1;
+			return 0;
+		}`,
+		"nvmlComputeInstanceGetInfo_v2": `int nvmlComputeInstanceGetInfo_v2(void *ci, computeInstanceInfo *info) {
+			nvmlComputeInstanceGetInfo(ci, (computeInstanceInfoV1 *)info);
+			info->placement.start = 2;
+			info->placement.size = 2;
+			return 0;
+		}`,
+		"nvmlDeviceIsMigDeviceHandle": `int nvmlDeviceIsMigDeviceHandle(void *d, unsigned int *isMigDevice) {
+			*isMigDevice = (uintptr_t)d >= 0x4000;
+			return 0;
+		}`,
+		"nvmlDeviceGetMigDeviceHandleByIndex": `int nvmlDeviceGetMigDeviceHandleByIndex(void *d, unsigned int index, void **migDevice) {
+			if (index > 6) return 6;
+			*migDevice = (void *)(uintptr_t)(0x4000 + index);
+			return 0;
+		}`,
+	})
+}
+
+func TestMigStub(t *testing.T) {
+	w := newStubAPI(t)
+
+	current, pending, err := w.DeviceGetMigMode(Device(0x1000))
+	require.NoError(t, err)
+	require.Equal(t, MigModeDisabled, current)
+	require.Equal(t, MigModeEnabled, pending)
+
+	err = w.DeviceSetMigMode(Device(0x1000), MigModeEnabled)
+	require.Equal(t, ErrInUse, err)
+
+	err = w.DeviceSetMigMode(Device(0x1000), MigMode(2))
+	require.Equal(t, ErrInvalidArgument, err)
+
+	info, err := w.DeviceGetGpuInstanceProfileInfo(Device(0x1000), GpuInstanceProfile1Slice)
+	require.NoError(t, err)
+	require.Equal(t, GpuInstanceProfileInfo{
+		ID:                  19,
+		SliceCount:          1,
+		InstanceCount:       7,
+		MultiprocessorCount: 14,
+		OFACount:            1,
+		MemorySizeMB:        4864,
+	}, info)
+
+	placements, err := w.DeviceGetGpuInstancePossiblePlacements(Device(0x1000), 9)
+	require.NoError(t, err)
+	require.Equal(t, []GpuInstancePlacement{{Start: 0, Size: 4}, {Start: 4, Size: 4}}, placements)
+
+	instances, err := w.DeviceGetGpuInstances(Device(0x1000), GpuInstanceProfileInfo{ID: 9, InstanceCount: 2})
+	require.NoError(t, err)
+	require.Equal(t, []GpuInstance{0x3001, 0x3002}, instances)
+
+	_, err = w.DeviceGetGpuInstances(Device(0x1000), GpuInstanceProfileInfo{ID: 9, InstanceCount: 1})
+	require.Equal(t, ErrInsufficientSize, err)
+
+	giInfo, err := w.GpuInstanceGetInfo(instances[1])
+	require.NoError(t, err)
+	require.Equal(t, GpuInstanceInfo{Device: 0x1000, ID: 1, ProfileID: 9, Placement: GpuInstancePlacement{Start: 4, Size: 4}}, giInfo)
+
+	ciInfo, err := w.ComputeInstanceGetInfo(ComputeInstance(0x3100))
+	require.NoError(t, err)
+	require.Equal(t, ComputeInstanceInfo{Device: 0x1000, GpuInstance: 0x3001, ID: 2, ProfileID: 1}, ciInfo)
+
+	migDevice, err := w.DeviceGetMigDeviceHandleByIndex(Device(0x1000), 2)
+	require.NoError(t, err)
+	require.Equal(t, Device(0x4002), migDevice)
+
+	_, err = w.DeviceGetMigDeviceHandleByIndex(Device(0x1000), 7)
+	require.Equal(t, ErrNotFound, err)
+
+	isMig, err := w.DeviceIsMigDeviceHandle(migDevice)
+	require.NoError(t, err)
+	require.True(t, isMig)
+
+	isMig, err = w.DeviceIsMigDeviceHandle(Device(0x1000))
+	require.NoError(t, err)
+	require.False(t, isMig)
+}
+
+func TestMigV2Stub(t *testing.T) {
+	w := newStubAPI(t, "nvmlDeviceGetGpuInstancePossiblePlacements_v2", "nvmlComputeInstanceGetInfo_v2")
+
+	require.Equal(t, 2, w.SymbolVersion("nvmlDeviceGetGpuInstancePossiblePlacements"))
+	require.Equal(t, 2, w.SymbolVersion("nvmlComputeInstanceGetInfo"))
+
+	placements, err := w.DeviceGetGpuInstancePossiblePlacements(Device(0x1000), 19)
+	require.NoError(t, err)
+	require.Len(t, placements, 7)
+	require.Equal(t, GpuInstancePlacement{Start: 6, Size: 1}, placements[6])
+
+	info, err := w.ComputeInstanceGetInfo(ComputeInstance(0x3100))
+	require.NoError(t, err)
+	require.Equal(t, ComputeInstanceInfo{
+		Device:      0x1000,
+		GpuInstance: 0x3001,
+		ID:          2,
+		ProfileID:   1,
+		Placement:   ComputeInstancePlacement{Start: 2, Size: 2},
+	}, info)
+}
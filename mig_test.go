@@ -0,0 +1,58 @@
+package nvml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// createMig returns the first device of the system, skipping the test when it isn't in MIG mode.
+func createMig(t *testing.T) (*API, Device) {
+	w, device := create(t)
+
+	current, _, err := w.DeviceGetMigMode(device)
+	if err == ErrNotSupported || err == ErrFunctionNotFound || current != MigModeEnabled {
+		w.Shutdown()
+		t.Skip("MIG is not enabled")
+	}
+
+	require.NoError(t, err)
+	return w, device
+}
+
+func TestDeviceGetGpuInstanceProfileInfo(t *testing.T) {
+	w, device := createMig(t)
+	defer w.Shutdown()
+
+	info, err := w.DeviceGetGpuInstanceProfileInfo(device, GpuInstanceProfile1Slice)
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), info.SliceCount)
+
+	placements, err := w.DeviceGetGpuInstancePossiblePlacements(device, info.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, placements)
+
+	_, err = w.DeviceGetGpuInstanceRemainingCapacity(device, info.ID)
+	require.NoError(t, err)
+
+	_, err = w.DeviceGetGpuInstances(device, info)
+	require.NoError(t, err)
+}
+
+func TestDeviceGetMaxMigDeviceCount(t *testing.T) {
+	w, device := createMig(t)
+	defer w.Shutdown()
+
+	count, err := w.DeviceGetMaxMigDeviceCount(device)
+	require.NoError(t, err)
+	require.NotZero(t, count)
+}
+
+func TestDeviceIsMigDeviceHandle(t *testing.T) {
+	w, device := createMig(t)
+	defer w.Shutdown()
+
+	isMig, err := w.DeviceIsMigDeviceHandle(device)
+	require.NoError(t, err)
+	require.False(t, isMig)
+}
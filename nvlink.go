@@ -0,0 +1,101 @@
+package nvml
+
+// DeviceGetNvLinkCapability retrieves the requested capability from the device's NvLink for the link specified.
+// Please refer to the NvLinkCapability structure for the specific caps that can be queried.
+// For Pascal or newer fully supported devices.
+func (a API) DeviceGetNvLinkCapability(device Device, link uint32, capability NvLinkCapability) (bool, error) {
+	var capResult uint32
+	if err := a.call(a.nvmlDeviceGetNvLinkCapability, uintptr(device), uintptr(link), uintptr(capability), &capResult); err != nil {
+		return false, err
+	}
+
+	return capResult != 0, nil
+}
+
+// DeviceGetNvLinkErrorCounter retrieves the specified error counter value.
+// Please refer to NvLinkErrorCounter for error counters that are available.
+// For Pascal or newer fully supported devices.
+func (a API) DeviceGetNvLinkErrorCounter(device Device, link uint32, counter NvLinkErrorCounter) (counterValue uint64, err error) {
+	err = a.call(a.nvmlDeviceGetNvLinkErrorCounter, uintptr(device), uintptr(link), uintptr(counter), &counterValue)
+	return
+}
+
+// DeviceGetNvLinkRemotePciInfo retrieves the PCI information for the remote node on a NvLink link.
+// Note: pciSubSystemId is not filled in this function and is indeterminate.
+// For Pascal or newer fully supported devices.
+func (a API) DeviceGetNvLinkRemotePciInfo(device Device, link uint32) (*PCIInfo, error) {
+	legacy := a.versions["nvmlDeviceGetNvLinkRemotePciInfo"] < 2
+	return a.pciInfo(a.nvmlDeviceGetNvLinkRemotePciInfo, legacy, uintptr(device), uintptr(link))
+}
+
+// DeviceGetNvLinkState retrieves the state of the device's NvLink for the link specified.
+// For Pascal or newer fully supported devices.
+func (a API) DeviceGetNvLinkState(device Device, link uint32) (isActive bool, err error) {
+	var state int32
+	err = a.call(a.nvmlDeviceGetNvLinkState, uintptr(device), uintptr(link), &state)
+	isActive = state != 0
+	return
+}
+
+// DeviceGetNvLinkVersion retrieves the version of the device's NvLink for the link specified.
+// For Pascal or newer fully supported devices.
+func (a API) DeviceGetNvLinkVersion(device Device, link uint32) (version uint32, err error) {
+	err = a.call(a.nvmlDeviceGetNvLinkVersion, uintptr(device), uintptr(link), &version)
+	return
+}
+
+// DeviceResetNvLinkErrorCounters resets all error counters to zero.
+// Please refer to NvLinkErrorCounter for the list of error counters that are reset.
+// For Pascal or newer fully supported devices.
+func (a API) DeviceResetNvLinkErrorCounters(device Device, link uint32) error {
+	return a.call(a.nvmlDeviceResetNvLinkErrorCounters, uintptr(device), uintptr(link))
+}
+
+// DeviceSetNvLinkUtilizationControl sets the NVLINK utilization counter control information for the specified
+// counter, 0 or 1. Please refer to NvLinkUtilizationControl for the structure definition. Performs a reset of the
+// counters if the reset parameter is true.
+// For Pascal or newer fully supported devices.
+func (a API) DeviceSetNvLinkUtilizationControl(device Device, link, counter uint32, control NvLinkUtilizationControl, reset bool) error {
+	var resetInt uint32
+	if reset {
+		resetInt = 1
+	}
+
+	return a.call(a.nvmlDeviceSetNvLinkUtilizationControl, uintptr(device), uintptr(link), uintptr(counter), &control, uintptr(resetInt))
+}
+
+// DeviceGetNvLinkUtilizationControl gets the NVLINK utilization counter control information for the specified
+// counter, 0 or 1. Please refer to NvLinkUtilizationControl for the structure definition.
+// For Pascal or newer fully supported devices.
+func (a API) DeviceGetNvLinkUtilizationControl(device Device, link, counter uint32) (control NvLinkUtilizationControl, err error) {
+	err = a.call(a.nvmlDeviceGetNvLinkUtilizationControl, uintptr(device), uintptr(link), uintptr(counter), &control)
+	return
+}
+
+// DeviceGetNvLinkUtilizationCounter retrieves the NVLINK utilization counter based on the current control for a
+// specified counter. In general it is good practice to use DeviceSetNvLinkUtilizationControl before reading the
+// utilization counters as they have no default state.
+// For Pascal or newer fully supported devices.
+func (a API) DeviceGetNvLinkUtilizationCounter(device Device, link, counter uint32) (rxCounter, txCounter uint64, err error) {
+	err = a.call(a.nvmlDeviceGetNvLinkUtilizationCounter, uintptr(device), uintptr(link), uintptr(counter), &rxCounter, &txCounter)
+	return
+}
+
+// DeviceFreezeNvLinkUtilizationCounter freezes the NVLINK utilization counters. Both the receive and transmit
+// counters are operated on by this function.
+// For Pascal or newer fully supported devices.
+func (a API) DeviceFreezeNvLinkUtilizationCounter(device Device, link, counter uint32, freeze bool) error {
+	var freezeInt int32
+	if freeze {
+		freezeInt = 1
+	}
+
+	return a.call(a.nvmlDeviceFreezeNvLinkUtilizationCounter, uintptr(device), uintptr(link), uintptr(counter), uintptr(freezeInt))
+}
+
+// DeviceResetNvLinkUtilizationCounter resets the NVLINK utilization counters. Both the receive and transmit
+// counters are operated on by this function.
+// For Pascal or newer fully supported devices.
+func (a API) DeviceResetNvLinkUtilizationCounter(device Device, link, counter uint32) error {
+	return a.call(a.nvmlDeviceResetNvLinkUtilizationCounter, uintptr(device), uintptr(link), uintptr(counter))
+}
@@ -0,0 +1,107 @@
+package nvml
+
+import (
+	"github.com/pkg/errors"
+)
+
+// NvLinkConnection is an active NvLink of a device.
+type NvLinkConnection struct {
+	Link    uint32
+	Version uint32
+	// PCI attributes of the remote end of the link
+	Remote PCIInfo
+	// Index of the remote device, -1 when the link is connected to something else (e.g. a NVSwitch or a CPU)
+	RemoteDevice int
+}
+
+// NvLinkGraph describes the NvLink connections of all the devices in the system, like nvidia-smi nvlink -s.
+type NvLinkGraph struct {
+	// Links[i] lists the active links of device i
+	Links [][]NvLinkConnection
+}
+
+// NewNvLinkGraph walks all the links of all the devices in the system.
+// Links that are inactive or not supported are skipped, devices without NvLink have no links.
+func NewNvLinkGraph(lib Interface) (*NvLinkGraph, error) {
+	count, err := lib.DeviceGetCount()
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make([]Device, count)
+	buses := make([]*PCIInfo, count)
+	for i := range devices {
+		devices[i], err = lib.DeviceGetHandleByIndex(uint32(i))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get handle of device %d", i)
+		}
+
+		buses[i], err = lib.DeviceGetPCIInfo(devices[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get PCI info of device %d", i)
+		}
+	}
+
+	return newNvLinkGraph(lib, devices, buses)
+}
+
+// newNvLinkGraph walks all the links of the given devices, buses[i] being the PCI info of devices[i].
+// See NewNvLinkGraph.
+func newNvLinkGraph(lib Interface, devices []Device, buses []*PCIInfo) (*NvLinkGraph, error) {
+	g := &NvLinkGraph{Links: make([][]NvLinkConnection, len(devices))}
+	for i, device := range devices {
+		g.Links[i] = []NvLinkConnection{}
+
+		for link := uint32(0); link < NvLinkMaxLinks; link++ {
+			active, err := lib.DeviceGetNvLinkState(device, link)
+			if err == ErrInvalidArgument {
+				// No more links
+				break
+			}
+
+			if err == ErrNotSupported || (err == nil && !active) {
+				continue
+			}
+
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get state of device %d link %d", i, link)
+			}
+
+			conn := NvLinkConnection{Link: link, RemoteDevice: -1}
+
+			conn.Version, err = lib.DeviceGetNvLinkVersion(device, link)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get version of device %d link %d", i, link)
+			}
+
+			remote, err := lib.DeviceGetNvLinkRemotePciInfo(device, link)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get remote PCI info of device %d link %d", i, link)
+			}
+
+			conn.Remote = *remote
+			for j, pci := range buses {
+				if pci.Domain == remote.Domain && pci.Bus == remote.Bus && pci.Device == remote.Device {
+					conn.RemoteDevice = j
+					break
+				}
+			}
+
+			g.Links[i] = append(g.Links[i], conn)
+		}
+	}
+
+	return g, nil
+}
+
+// LinkCount returns the number of active links from device i to device j.
+func (g *NvLinkGraph) LinkCount(i, j int) int {
+	count := 0
+	for _, conn := range g.Links[i] {
+		if conn.RemoteDevice == j {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,53 @@
+package nvml_test
+
+import (
+	"testing"
+
+	nvml "github.com/mxpv/nvml-go"
+	"github.com/mxpv/nvml-go/fake"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNvLinkGraph(t *testing.T) {
+	f := fake.New(4)
+	require.NoError(t, f.Init())
+	defer f.Shutdown()
+
+	f.ConnectNvLinks(0, 1, 2)
+	f.ConnectNvLinks(0, 2, 1)
+	f.ConnectNvLinks(1, 2, 1)
+
+	// Link to a NVSwitch, and an inactive link
+	switchPCI := nvml.PCIInfo{BusID: "00000000:C0:00.0", Bus: 0xc0}
+	f.Devices[2].NvLinks = append(f.Devices[2].NvLinks, fake.NewNvLink(switchPCI), fake.NewNvLink(switchPCI))
+	f.Devices[2].NvLinks[3].Active = false
+
+	graph, err := nvml.NewNvLinkGraph(f)
+	require.NoError(t, err)
+
+	require.Len(t, graph.Links, 4)
+	require.Equal(t, 2, graph.LinkCount(0, 1))
+	require.Equal(t, 2, graph.LinkCount(1, 0))
+	require.Equal(t, 1, graph.LinkCount(2, 0))
+	require.Zero(t, graph.LinkCount(0, 3))
+
+	require.Len(t, graph.Links[2], 3)
+	require.Equal(t, -1, graph.Links[2][2].RemoteDevice)
+	require.Equal(t, uint32(2), graph.Links[2][2].Link)
+	require.Equal(t, "00000000:C0:00.0", graph.Links[2][2].Remote.BusID)
+
+	// Device 3 doesn't support NvLink
+	require.Empty(t, graph.Links[3])
+}
+
+func TestNvLinkGraphError(t *testing.T) {
+	f := fake.New(2)
+	require.NoError(t, f.Init())
+	defer f.Shutdown()
+
+	f.ConnectNvLinks(0, 1, 1)
+	f.Devices[1].Errors["DeviceGetNvLinkRemotePciInfo"] = nvml.ErrGPULost
+
+	_, err := nvml.NewNvLinkGraph(f)
+	require.Error(t, err)
+}
@@ -0,0 +1,139 @@
+// +build linux,cgo
+
+package nvml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The NvLink entry points of the stub library, see newStubLibrary.
+func init() {
+	addStubFunctions(`
+static int utilizationControl[2];
+static unsigned int utilizationReset;
+`, map[string]string{
+		"nvmlDeviceGetNvLinkState": `int nvmlDeviceGetNvLinkState(void *d, unsigned int link, int *isActive) {
+			if (link >= 6) return 2;
+			*isActive = link != 3;
+			return 0;
+		}`,
+		"nvmlDeviceGetNvLinkVersion": `int nvmlDeviceGetNvLinkVersion(void *d, unsigned int link, unsigned int *version) {
+			*version = 2;
+			return 0;
+		}`,
+		"nvmlDeviceGetNvLinkCapability": `int nvmlDeviceGetNvLinkCapability(void *d, unsigned int link, int cap, unsigned int *result) {
+			*result = cap == 0 || cap == 5;
+			return 0;
+		}`,
+		"nvmlDeviceGetNvLinkErrorCounter": `int nvmlDeviceGetNvLinkErrorCounter(void *d, unsigned int link, int counter,
+			unsigned long long *value) {
+			*value = link * 100 + counter;
+			return 0;
+		}`,
+		"nvmlDeviceGetNvLinkRemotePciInfo": `int nvmlDeviceGetNvLinkRemotePciInfo(void *d, unsigned int link, pciInfoLegacy *pci) {
+			fillLegacyPci(pci, 0x10 + link);
+			return 0;
+		}`,
+		"nvmlDeviceGetNvLinkRemotePciInfo_v2": `int nvmlDeviceGetNvLinkRemotePciInfo_v2(void *d, unsigned int link, pciInfo *pci) {
+			snprintf(pci->busIdLegacy, sizeof(pci->busIdLegacy), "0000:%02x:00.0", 0x10 + link);
+			snprintf(pci->busId, sizeof(pci->busId), "00000000:%02x:00.0", 0x10 + link);
+			pci->bus = 0x10 + link;
+			return 0;
+		}`,
+		"nvmlDeviceResetNvLinkErrorCounters": `int nvmlDeviceResetNvLinkErrorCounters(void *d, unsigned int link) {
+			return link < 6 ? 0 : 2;
+		}`,
+		"nvmlDeviceSetNvLinkUtilizationControl": `int nvmlDeviceSetNvLinkUtilizationControl(void *d, unsigned int link,
+			unsigned int counter, int *control, unsigned int reset) {
+			if (counter > 1) return 2;
+			utilizationControl[0] = control[0];
+			utilizationControl[1] = control[1];
+			utilizationReset = reset;
+			return 0;
+		}`,
+		"nvmlDeviceGetNvLinkUtilizationControl": `int nvmlDeviceGetNvLinkUtilizationControl(void *d, unsigned int link,
+			unsigned int counter, int *control) {
+			control[0] = utilizationControl[0];
+			control[1] = utilizationControl[1];
+			return 0;
+		}`,
+		"nvmlDeviceGetNvLinkUtilizationCounter": `int nvmlDeviceGetNvLinkUtilizationCounter(void *d, unsigned int link,
+			unsigned int counter, unsigned long long *rx, unsigned long long *tx) {
+			*rx = utilizationReset ? 0 : 1ULL << 40;
+			*tx = link * 10 + counter;
+			return 0;
+		}`,
+	})
+}
+
+func TestNvLinkStub(t *testing.T) {
+	w := newStubAPI(t)
+
+	active, err := w.DeviceGetNvLinkState(Device(0x1000), 3)
+	require.NoError(t, err)
+	require.False(t, active)
+
+	_, err = w.DeviceGetNvLinkState(Device(0x1000), 6)
+	require.Equal(t, ErrInvalidArgument, err)
+
+	version, err := w.DeviceGetNvLinkVersion(Device(0x1000), 0)
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), version)
+
+	supported, err := w.DeviceGetNvLinkCapability(Device(0x1000), 0, NvLinkCapabilityP2PSupported)
+	require.NoError(t, err)
+	require.True(t, supported)
+
+	supported, err = w.DeviceGetNvLinkCapability(Device(0x1000), 0, NvLinkCapabilitySLIBridge)
+	require.NoError(t, err)
+	require.False(t, supported)
+
+	value, err := w.DeviceGetNvLinkErrorCounter(Device(0x1000), 2, NvLinkErrorDLCRCFlit)
+	require.NoError(t, err)
+	require.Equal(t, uint64(202), value)
+
+	require.NoError(t, w.DeviceResetNvLinkErrorCounters(Device(0x1000), 2))
+
+	pci, err := w.DeviceGetNvLinkRemotePciInfo(Device(0x1000), 1)
+	require.NoError(t, err)
+	require.Equal(t, "0000:11:00.0", pci.BusID)
+	require.Equal(t, uint32(0x11), pci.Bus)
+}
+
+func TestNvLinkRemotePciInfoV2Stub(t *testing.T) {
+	w := newStubAPI(t, "nvmlDeviceGetNvLinkRemotePciInfo_v2")
+
+	require.Equal(t, 2, w.SymbolVersion("nvmlDeviceGetNvLinkRemotePciInfo"))
+
+	pci, err := w.DeviceGetNvLinkRemotePciInfo(Device(0x1000), 1)
+	require.NoError(t, err)
+	require.Equal(t, "00000000:11:00.0", pci.BusID)
+	require.Equal(t, "0000:11:00.0", pci.BusIDLegacy)
+}
+
+func TestNvLinkUtilizationStub(t *testing.T) {
+	w := newStubAPI(t)
+
+	control := NvLinkUtilizationControl{Units: NvLinkCounterUnitPackets, PacketFilter: NvLinkCounterPktTypeWrite}
+	require.NoError(t, w.DeviceSetNvLinkUtilizationControl(Device(0x1000), 1, 0, control, false))
+
+	actual, err := w.DeviceGetNvLinkUtilizationControl(Device(0x1000), 1, 0)
+	require.NoError(t, err)
+	require.Equal(t, control, actual)
+
+	rx, tx, err := w.DeviceGetNvLinkUtilizationCounter(Device(0x1000), 2, 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1)<<40, rx)
+	require.Equal(t, uint64(21), tx)
+
+	require.NoError(t, w.DeviceSetNvLinkUtilizationControl(Device(0x1000), 1, 0, control, true))
+
+	rx, _, err = w.DeviceGetNvLinkUtilizationCounter(Device(0x1000), 2, 1)
+	require.NoError(t, err)
+	require.Zero(t, rx)
+
+	err = w.DeviceSetNvLinkUtilizationControl(Device(0x1000), 1, 2, control, false)
+	require.Equal(t, ErrInvalidArgument, err)
+}
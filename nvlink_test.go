@@ -0,0 +1,83 @@
+package nvml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// createNvLink returns the first device of the system, skipping the test when link 0 isn't supported.
+func createNvLink(t *testing.T) (*API, Device) {
+	w, device := create(t)
+
+	_, err := w.DeviceGetNvLinkState(device, 0)
+	if err == ErrNotSupported {
+		w.Shutdown()
+		t.Skip("NvLink is not supported")
+	}
+
+	require.NoError(t, err)
+	return w, device
+}
+
+func TestDeviceGetNvLinkCapability(t *testing.T) {
+	w, device := createNvLink(t)
+	defer w.Shutdown()
+
+	_, err := w.DeviceGetNvLinkCapability(device, 0, NvLinkCapabilityP2PSupported)
+	require.NoError(t, err)
+}
+
+func TestDeviceGetNvLinkErrorCounter(t *testing.T) {
+	w, device := createNvLink(t)
+	defer w.Shutdown()
+
+	_, err := w.DeviceGetNvLinkErrorCounter(device, 0, NvLinkErrorDLReplay)
+	require.NoError(t, err)
+}
+
+func TestDeviceGetNvLinkRemotePciInfo(t *testing.T) {
+	w, device := createNvLink(t)
+	defer w.Shutdown()
+
+	pci, err := w.DeviceGetNvLinkRemotePciInfo(device, 0)
+	require.NoError(t, err)
+	require.NotEmpty(t, pci.BusID)
+}
+
+func TestDeviceGetNvLinkVersion(t *testing.T) {
+	w, device := createNvLink(t)
+	defer w.Shutdown()
+
+	version, err := w.DeviceGetNvLinkVersion(device, 0)
+	require.NoError(t, err)
+	require.NotZero(t, version)
+}
+
+func TestDeviceResetNvLinkErrorCounters(t *testing.T) {
+	w, device := createNvLink(t)
+	defer w.Shutdown()
+
+	err := w.DeviceResetNvLinkErrorCounters(device, 0)
+	require.NoError(t, err)
+}
+
+func TestDeviceNvLinkUtilizationCounter(t *testing.T) {
+	w, device := createNvLink(t)
+	defer w.Shutdown()
+
+	control := NvLinkUtilizationControl{Units: NvLinkCounterUnitBytes, PacketFilter: NvLinkCounterPktTypeAll}
+	err := w.DeviceSetNvLinkUtilizationControl(device, 0, 0, control, true)
+	require.NoError(t, err)
+
+	actual, err := w.DeviceGetNvLinkUtilizationControl(device, 0, 0)
+	require.NoError(t, err)
+	require.Equal(t, control, actual)
+
+	_, _, err = w.DeviceGetNvLinkUtilizationCounter(device, 0, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, w.DeviceFreezeNvLinkUtilizationCounter(device, 0, 0, true))
+	require.NoError(t, w.DeviceFreezeNvLinkUtilizationCounter(device, 0, 0, false))
+	require.NoError(t, w.DeviceResetNvLinkUtilizationCounter(device, 0, 0))
+}
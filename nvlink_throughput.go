@@ -0,0 +1,47 @@
+package nvml
+
+import (
+	"time"
+)
+
+// NvLinkCounterReading is a reading of a NvLink utilization counter, see ReadNvLinkUtilizationCounter.
+type NvLinkCounterReading struct {
+	RX   uint64
+	TX   uint64
+	Time time.Time
+}
+
+// ReadNvLinkUtilizationCounter reads a NvLink utilization counter and records when it was read.
+func ReadNvLinkUtilizationCounter(lib Interface, device Device, link, counter uint32) (NvLinkCounterReading, error) {
+	rx, tx, err := lib.DeviceGetNvLinkUtilizationCounter(device, link, counter)
+	if err != nil {
+		return NvLinkCounterReading{}, err
+	}
+
+	return NvLinkCounterReading{RX: rx, TX: tx, Time: time.Now()}, nil
+}
+
+// NvLinkThroughput returns the receive and transmit throughput of a link in bytes/s between two readings of a counter.
+// The counter is expected to count bytes of all packet types, which is set up with:
+//
+//	control := NvLinkUtilizationControl{Units: NvLinkCounterUnitBytes, PacketFilter: NvLinkCounterPktTypeAll}
+//	err := lib.DeviceSetNvLinkUtilizationControl(device, link, counter, control, true)
+//
+// Zero is returned for a direction whose counter went backwards (it was reset between the readings) and when current
+// isn't more recent than previous.
+func NvLinkThroughput(previous, current NvLinkCounterReading) (rx, tx float64) {
+	elapsed := current.Time.Sub(previous.Time).Seconds()
+	if elapsed <= 0 {
+		return 0, 0
+	}
+
+	if current.RX >= previous.RX {
+		rx = float64(current.RX-previous.RX) / elapsed
+	}
+
+	if current.TX >= previous.TX {
+		tx = float64(current.TX-previous.TX) / elapsed
+	}
+
+	return
+}
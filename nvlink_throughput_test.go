@@ -0,0 +1,53 @@
+package nvml_test
+
+import (
+	"testing"
+	"time"
+
+	nvml "github.com/mxpv/nvml-go"
+	"github.com/mxpv/nvml-go/fake"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNvLinkThroughput(t *testing.T) {
+	start := time.Now()
+	previous := nvml.NvLinkCounterReading{RX: 1000, TX: 5000, Time: start}
+
+	rx, tx := nvml.NvLinkThroughput(previous, nvml.NvLinkCounterReading{RX: 3000, TX: 5500, Time: start.Add(500 * time.Millisecond)})
+	require.Equal(t, 4000.0, rx)
+	require.Equal(t, 1000.0, tx)
+
+	// TX counter was reset
+	rx, tx = nvml.NvLinkThroughput(previous, nvml.NvLinkCounterReading{RX: 2000, TX: 10, Time: start.Add(time.Second)})
+	require.Equal(t, 1000.0, rx)
+	require.Zero(t, tx)
+
+	rx, tx = nvml.NvLinkThroughput(previous, previous)
+	require.Zero(t, rx)
+	require.Zero(t, tx)
+}
+
+func TestReadNvLinkUtilizationCounter(t *testing.T) {
+	f := fake.New(2)
+	require.NoError(t, f.Init())
+	defer f.Shutdown()
+
+	f.ConnectNvLinks(0, 1, 1)
+
+	control := nvml.NvLinkUtilizationControl{Units: nvml.NvLinkCounterUnitBytes, PacketFilter: nvml.NvLinkCounterPktTypeAll}
+	require.NoError(t, f.DeviceSetNvLinkUtilizationControl(fake.Handle(0), 0, 1, control, true))
+
+	previous, err := nvml.ReadNvLinkUtilizationCounter(f, fake.Handle(0), 0, 1)
+	require.NoError(t, err)
+
+	f.AddNvLinkTraffic(0, 0, 1<<20, 1<<10)
+	time.Sleep(time.Millisecond)
+
+	current, err := nvml.ReadNvLinkUtilizationCounter(f, fake.Handle(0), 0, 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1<<20), current.RX)
+	require.Equal(t, uint64(1<<10), current.TX)
+
+	rx, tx := nvml.NvLinkThroughput(previous, current)
+	require.True(t, rx > tx)
+}
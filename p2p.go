@@ -0,0 +1,48 @@
+package nvml
+
+import (
+	"github.com/pkg/errors"
+)
+
+// P2PMatrix builds the peer-to-peer status matrix of all the devices in the system for the given capability.
+// The result is indexed by device index: matrix[i][j] is the status reported by DeviceGetP2PStatus for devices i and j.
+// The diagonal is not queried, a device is always reported as able to talk to itself.
+func P2PMatrix(lib Interface, p2pIndex P2PCapsIndex) ([][]P2PStatus, error) {
+	count, err := lib.DeviceGetCount()
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make([]Device, count)
+	for i := range devices {
+		devices[i], err = lib.DeviceGetHandleByIndex(uint32(i))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get handle of device %d", i)
+		}
+	}
+
+	return p2pMatrix(lib, devices, p2pIndex)
+}
+
+// p2pMatrix builds the peer-to-peer status matrix of the given devices, see P2PMatrix.
+func p2pMatrix(lib Interface, devices []Device, p2pIndex P2PCapsIndex) ([][]P2PStatus, error) {
+	var err error
+
+	matrix := make([][]P2PStatus, len(devices))
+	for i := range matrix {
+		matrix[i] = make([]P2PStatus, len(devices))
+		for j := range matrix[i] {
+			if i == j {
+				matrix[i][j] = P2PStatusOK
+				continue
+			}
+
+			matrix[i][j], err = lib.DeviceGetP2PStatus(devices[i], devices[j], p2pIndex)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get P2P status of devices %d and %d", i, j)
+			}
+		}
+	}
+
+	return matrix, nil
+}
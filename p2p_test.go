@@ -0,0 +1,45 @@
+package nvml_test
+
+import (
+	"testing"
+
+	nvml "github.com/mxpv/nvml-go"
+	"github.com/mxpv/nvml-go/fake"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestP2PMatrix(t *testing.T) {
+	f := fake.New(3)
+	require.NoError(t, f.Init())
+	defer f.Shutdown()
+
+	ok, ns := nvml.P2PStatusOK, nvml.P2PStatusNotSupported
+	f.P2P = map[nvml.P2PCapsIndex][][]nvml.P2PStatus{
+		nvml.P2PCapsIndexNVLink: {
+			{ok, ok, ns},
+			{ok, ok, ns},
+			{ns, ns, ok},
+		},
+	}
+
+	matrix, err := nvml.P2PMatrix(f, nvml.P2PCapsIndexNVLink)
+	require.NoError(t, err)
+	require.Equal(t, f.P2P[nvml.P2PCapsIndexNVLink], matrix)
+
+	matrix, err = nvml.P2PMatrix(f, nvml.P2PCapsIndexRead)
+	require.NoError(t, err)
+	require.Equal(t, [][]nvml.P2PStatus{{ok, ns, ns}, {ns, ok, ns}, {ns, ns, ok}}, matrix)
+}
+
+func TestP2PMatrixError(t *testing.T) {
+	f := fake.New(2)
+	require.NoError(t, f.Init())
+	defer f.Shutdown()
+
+	f.Devices[1].Errors["DeviceGetP2PStatus"] = nvml.ErrGPULost
+
+	_, err := nvml.P2PMatrix(f, nvml.P2PCapsIndexWrite)
+	require.Error(t, err)
+	require.Equal(t, nvml.ErrGPULost, errors.Cause(err))
+}
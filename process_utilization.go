@@ -0,0 +1,87 @@
+package nvml
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// ProcessUtilization is the utilization of a process averaged over the samples returned by
+// DeviceGetProcessUtilization, see TopProcesses.
+type ProcessUtilization struct {
+	PID uint32
+	// Process name, empty when the process has exited
+	Name    string
+	SMUtil  uint32
+	MemUtil uint32
+	EncUtil uint32
+	DecUtil uint32
+	// Timestamp of the most recent sample of the process, in microseconds
+	LastSeenTimeStamp uint64
+}
+
+// TopProcesses returns the utilization of the processes running on a device, like top does for CPUs.
+// The samples more recent than lastSeenTimeStamp (0 for all the samples kept by the driver) are averaged by PID
+// and joined with the process names from SystemGetProcessName. Processes are ranked by SM utilization, then by
+// memory utilization, the busiest first.
+func TopProcesses(lib Interface, device Device, lastSeenTimeStamp uint64) ([]ProcessUtilization, error) {
+	samples, err := lib.DeviceGetProcessUtilization(device, lastSeenTimeStamp)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get process utilization")
+	}
+
+	type total struct {
+		ProcessUtilization
+		count uint32
+	}
+
+	var order []uint32
+	totals := map[uint32]*total{}
+	for _, sample := range samples {
+		t, ok := totals[sample.PID]
+		if !ok {
+			t = &total{ProcessUtilization: ProcessUtilization{PID: sample.PID}}
+			totals[sample.PID] = t
+			order = append(order, sample.PID)
+		}
+
+		t.SMUtil += sample.SMUtil
+		t.MemUtil += sample.MemUtil
+		t.EncUtil += sample.EncUtil
+		t.DecUtil += sample.DecUtil
+		t.count++
+		if sample.TimeStamp > t.LastSeenTimeStamp {
+			t.LastSeenTimeStamp = sample.TimeStamp
+		}
+	}
+
+	list := make([]ProcessUtilization, len(order))
+	for i, pid := range order {
+		t := totals[pid]
+
+		name, err := lib.SystemGetProcessName(uint(pid))
+		if err != nil && err != ErrNotFound {
+			return nil, errors.Wrapf(err, "failed to get name of process %d", pid)
+		}
+
+		list[i] = ProcessUtilization{
+			PID:               pid,
+			Name:              name,
+			SMUtil:            t.SMUtil / t.count,
+			MemUtil:           t.MemUtil / t.count,
+			EncUtil:           t.EncUtil / t.count,
+			DecUtil:           t.DecUtil / t.count,
+			LastSeenTimeStamp: t.LastSeenTimeStamp,
+		}
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].SMUtil != list[j].SMUtil {
+			return list[i].SMUtil > list[j].SMUtil
+		}
+
+		return list[i].MemUtil > list[j].MemUtil
+	})
+
+	return list, nil
+}
@@ -0,0 +1,47 @@
+package nvml_test
+
+import (
+	"testing"
+
+	nvml "github.com/mxpv/nvml-go"
+	"github.com/mxpv/nvml-go/fake"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopProcesses(t *testing.T) {
+	f := fake.New(1)
+	require.NoError(t, f.Init())
+	defer f.Shutdown()
+
+	f.ProcessNames[100] = "python"
+	f.ProcessNames[200] = "ffmpeg"
+	f.Devices[0].ProcessUtilization = []nvml.ProcessUtilizationSample{
+		{PID: 100, TimeStamp: 10, SMUtil: 90, MemUtil: 40},
+		{PID: 200, TimeStamp: 10, SMUtil: 10, MemUtil: 5, EncUtil: 60},
+		{PID: 300, TimeStamp: 10, SMUtil: 10, MemUtil: 20},
+		{PID: 100, TimeStamp: 20, SMUtil: 70, MemUtil: 20},
+		{PID: 200, TimeStamp: 20, SMUtil: 10, MemUtil: 5, EncUtil: 80},
+	}
+
+	top, err := nvml.TopProcesses(f, fake.Handle(0), 0)
+	require.NoError(t, err)
+	require.Equal(t, []nvml.ProcessUtilization{
+		{PID: 100, Name: "python", SMUtil: 80, MemUtil: 30, LastSeenTimeStamp: 20},
+		{PID: 300, SMUtil: 10, MemUtil: 20, LastSeenTimeStamp: 10},
+		{PID: 200, Name: "ffmpeg", SMUtil: 10, MemUtil: 5, EncUtil: 70, LastSeenTimeStamp: 20},
+	}, top)
+
+	top, err = nvml.TopProcesses(f, fake.Handle(0), 10)
+	require.NoError(t, err)
+	require.Len(t, top, 2)
+	require.Equal(t, uint32(70), top[0].SMUtil)
+
+	top, err = nvml.TopProcesses(f, fake.Handle(0), 20)
+	require.NoError(t, err)
+	require.Empty(t, top)
+
+	f.Errors["SystemGetProcessName"] = nvml.ErrNoPermission
+	_, err = nvml.TopProcesses(f, fake.Handle(0), 0)
+	require.Equal(t, nvml.ErrNoPermission, errors.Cause(err))
+}
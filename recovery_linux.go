@@ -0,0 +1,64 @@
+// +build linux,cgo
+
+package nvml
+
+import (
+	"github.com/pkg/errors"
+)
+
+// NeedsRecovery reports whether err (or its cause) means that the GPU is no longer usable until it's recovered,
+// see RecoverGPU.
+func NeedsRecovery(err error) bool {
+	cause := errors.Cause(err)
+	return cause == ErrGPULost || cause == ErrResetRequired
+}
+
+// RecoverGPU brings back a GPU that fell off the bus or requires a reset, and returns its new handle.
+// The GPU is identified by its PCI info and UUID, as returned by DeviceGetPCIInfo and DeviceGetUUID while it was
+// healthy: queries on a lost GPU fail, so both should be recorded beforehand.
+//
+// The recovery sequence is:
+//   - DeviceModifyDrainState, so no new process attaches to the GPU
+//   - DeviceRemoveGPU, which detaches the GPU from NVML and the kernel driver
+//   - DeviceDiscoverGPUs, which rescans its PCI slot and initializes it again
+//   - DeviceModifyDrainState, to make the GPU available again in case it's still drained
+//   - DeviceGetHandleByUUID, as the handles and the enumeration order may change
+//
+// Processes using the GPU must be stopped and persistence mode must be disabled beforehand, otherwise removing
+// the GPU fails with ErrInUse and it's made available again. Requires root/admin permissions.
+func RecoverGPU(lib Interface, pci *PCIInfo, uuid string) (Device, error) {
+	if err := lib.DeviceModifyDrainState(pci, true); err != nil {
+		return 0, errors.Wrapf(err, "failed to drain GPU %s", pci.BusID)
+	}
+
+	if err := lib.DeviceRemoveGPU(pci); err != nil {
+		// The GPU is still attached, don't leave it drained
+		if undrainErr := lib.DeviceModifyDrainState(pci, false); undrainErr != nil {
+			return 0, errors.Wrapf(err, "failed to remove GPU %s (and to undrain it: %v)", pci.BusID, undrainErr)
+		}
+
+		return 0, errors.Wrapf(err, "failed to remove GPU %s", pci.BusID)
+	}
+
+	if err := lib.DeviceDiscoverGPUs(pci); err != nil {
+		return 0, errors.Wrapf(err, "failed to discover GPU %s", pci.BusID)
+	}
+
+	drained, err := lib.DeviceQueryDrainState(pci)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to query drain state of GPU %s", pci.BusID)
+	}
+
+	if drained {
+		if err := lib.DeviceModifyDrainState(pci, false); err != nil {
+			return 0, errors.Wrapf(err, "failed to undrain GPU %s", pci.BusID)
+		}
+	}
+
+	device, err := lib.DeviceGetHandleByUUID(uuid)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get handle of GPU %s", uuid)
+	}
+
+	return device, nil
+}
@@ -0,0 +1,54 @@
+// +build linux,cgo
+
+package nvml_test
+
+import (
+	"testing"
+
+	nvml "github.com/mxpv/nvml-go"
+	"github.com/mxpv/nvml-go/fake"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoverGPU(t *testing.T) {
+	f := fake.New(2)
+	require.NoError(t, f.Init())
+	defer f.Shutdown()
+
+	pci, err := f.DeviceGetPCIInfo(fake.Handle(1))
+	require.NoError(t, err)
+
+	uuid, err := f.DeviceGetUUID(fake.Handle(1))
+	require.NoError(t, err)
+
+	f.Devices[1].Errors["DeviceGetTemperature"] = nvml.ErrGPULost
+
+	_, err = f.DeviceGetTemperature(fake.Handle(1), nvml.TemperatureGPU)
+	require.True(t, nvml.NeedsRecovery(errors.Wrap(err, "failed to get temperature")))
+
+	device, err := nvml.RecoverGPU(f, pci, uuid)
+	require.NoError(t, err)
+	require.Equal(t, fake.Handle(1), device)
+	require.False(t, f.Devices[1].Drained)
+	require.False(t, f.Devices[1].Removed)
+}
+
+func TestRecoverGPUInUse(t *testing.T) {
+	f := fake.New(1)
+	require.NoError(t, f.Init())
+	defer f.Shutdown()
+
+	f.Devices[0].ComputeProcesses = []nvml.ProcessInfo{{PID: 1234}}
+
+	_, err := nvml.RecoverGPU(f, &f.Devices[0].PCI, f.Devices[0].UUID)
+	require.Equal(t, nvml.ErrInUse, errors.Cause(err))
+	require.False(t, f.Devices[0].Drained)
+	require.False(t, f.Devices[0].Removed)
+}
+
+func TestNeedsRecovery(t *testing.T) {
+	require.True(t, nvml.NeedsRecovery(nvml.ErrResetRequired))
+	require.False(t, nvml.NeedsRecovery(nvml.ErrNotSupported))
+	require.False(t, nvml.NeedsRecovery(nil))
+}
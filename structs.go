@@ -9,7 +9,7 @@ const (
 	systemDriverVersionBufferSize  = 80
 	deviceNameBufferSize           = 64
 	deviceSerialBufferSize         = 30
-	deviceUUIDBufferSize           = 80
+	deviceUUIDBufferSize           = 96 // NVML_DEVICE_UUID_V2_BUFFER_SIZE, large enough for MIG device UUIDs
 	deviceVBIOSVersionBufferSize   = 32
 	deviceInfoROMVersionBufferSize = 16
 )
@@ -69,6 +69,10 @@ type ProcessInfo struct {
 	// Amount of used GPU memory in bytes. Under WDDM, NVML_VALUE_NOT_AVAILABLE is always reported because Windows KMD
 	// manages all the memory and not the NVIDIA driver.
 	UsedGPUMemory uint64
+	// GPU instance ID for MIG devices. Set to math.MaxUint32 when not applicable or not reported by the driver.
+	GPUInstanceID uint32
+	// Compute instance ID for MIG devices. Set to math.MaxUint32 when not applicable or not reported by the driver.
+	ComputeInstanceID uint32
 }
 
 func (i ProcessInfo) MemoryInfoAvailable() bool {
@@ -257,6 +261,65 @@ const (
 	EncoderTypeQueryHEVC = EncoderType(1)
 )
 
+// EncoderSessionInfo holds information about an encoder session, as returned by DeviceGetEncoderSessions.
+type EncoderSessionInfo struct {
+	SessionID      uint32      // Unique session ID
+	PID            uint32      // Owning process ID
+	VGPUInstance   uint32      // Owning vGPU instance ID (only valid on vGPU hosts, otherwise zero)
+	CodecType      EncoderType // Video encoder type
+	HResolution    uint32      // Current encode horizontal resolution
+	VResolution    uint32      // Current encode vertical resolution
+	AverageFPS     uint32      // Moving average encode frames per second
+	AverageLatency uint32      // Moving average encode latency in microseconds
+}
+
+// FBCStats holds the frame buffer capture session statistics, as returned by DeviceGetFBCStats.
+type FBCStats struct {
+	SessionsCount  uint32 // Total no of sessions
+	AverageFPS     uint32 // Moving average new frames captured per second
+	AverageLatency uint32 // Moving average new frame capture latency in microseconds
+}
+
+// FBCSessionType represents the type of a frame buffer capture session.
+type FBCSessionType int32
+
+//noinspection GoUnusedConst
+const (
+	FBCSessionTypeUnknown = FBCSessionType(0) // Unknown
+	FBCSessionTypeToSys   = FBCSessionType(1) // ToSys
+	FBCSessionTypeCUDA    = FBCSessionType(2) // Cuda
+	FBCSessionTypeVid     = FBCSessionType(3) // Vid
+	FBCSessionTypeHWEnc   = FBCSessionType(4) // HEnc
+)
+
+// FBCSessionFlags is a bit mask of frame buffer capture session flags.
+type FBCSessionFlags uint32
+
+//noinspection GoUnusedConst
+const (
+	FBCSessionFlagDiffMapEnabled           = FBCSessionFlags(0x1)  // Diffmap enabled
+	FBCSessionFlagClassificationMapEnabled = FBCSessionFlags(0x2)  // Classification map enabled
+	FBCSessionFlagCaptureWithWaitNoWait    = FBCSessionFlags(0x4)  // Capture is non-blocking, no wait for new frames
+	FBCSessionFlagCaptureWithWaitInfinite  = FBCSessionFlags(0x8)  // Capture is blocking, wait for new frames or mouse move
+	FBCSessionFlagCaptureWithWaitTimeout   = FBCSessionFlags(0x10) // Capture is blocking, wait with timeout for new frames
+)
+
+// FBCSessionInfo holds information about a frame buffer capture session, as returned by DeviceGetFBCSessions.
+type FBCSessionInfo struct {
+	SessionID      uint32          // Unique session ID
+	PID            uint32          // Owning process ID
+	VGPUInstance   uint32          // Owning vGPU instance ID (only valid on vGPU hosts, otherwise zero)
+	DisplayOrdinal uint32          // Display identifier
+	SessionType    FBCSessionType  // Type of frame buffer capture session
+	SessionFlags   FBCSessionFlags // Session flags
+	HMaxResolution uint32          // Max horizontal resolution supported by the capture session
+	VMaxResolution uint32          // Max vertical resolution supported by the capture session
+	HResolution    uint32          // Horizontal resolution requested by caller in capture call
+	VResolution    uint32          // Vertical resolution requested by caller in capture call
+	AverageFPS     uint32          // Moving average new frames captured per second
+	AverageLatency uint32          // Moving average new frame capture latency in microseconds
+}
+
 // Memory error types
 type MemoryErrorType int32
 
@@ -349,6 +412,22 @@ const (
 	PageRetirementCauseDoubleBitECCError = PageRetirementCause(1)
 )
 
+// RetiredPage is a page retired by a device, see DeviceGetAllRetiredPages.
+type RetiredPage struct {
+	Address uint64 // Hardware address of the page
+	Time    uint64 // Timestamp of the retirement
+	Cause   PageRetirementCause
+}
+
+// RowRemapperHistogram holds the remap availability of the memory banks of a device.
+type RowRemapperHistogram struct {
+	Max     uint32 // Number of banks with max available remapping resources
+	High    uint32 // Number of banks with high available remapping resources
+	Partial uint32 // Number of banks with partial available remapping resources
+	Low     uint32 // Number of banks with low available remapping resources
+	None    uint32 // Number of banks with no available remapping resources
+}
+
 // Represents level relationships within a system between two GPUs.
 // The enums are spaced to allow for future relationships.
 type GPUTopologyLevel int32
@@ -363,6 +442,26 @@ const (
 	TopologySystem     = GPUTopologyLevel(50)
 )
 
+// String returns the abbreviation used by nvidia-smi topo for the level (X, PIX, PXB, PHB, NODE, SYS).
+func (l GPUTopologyLevel) String() string {
+	switch l {
+	case TopologyInternal:
+		return "X"
+	case TopologySingle:
+		return "PIX"
+	case TopologyMultiple:
+		return "PXB"
+	case TopologyHostbridge:
+		return "PHB"
+	case TopologyNode:
+		return "NODE"
+	case TopologySystem:
+		return "SYS"
+	default:
+		return "Unknown"
+	}
+}
+
 // Detailed ECC error counts for a device.
 // Different GPU families can have different memory error counters.
 type ECCErrorCounts struct {
@@ -400,3 +499,594 @@ type ViolationTime struct {
 	ReferenceTime uint64 // ReferenceTime represents CPU timestamp in microseconds
 	ViolationTime uint64 // ViolationTime in Nanoseconds
 }
+
+// EventType is a bit mask of NVML event types.
+type EventType uint64
+
+//noinspection GoUnusedConst
+const (
+	// Event about single bit ECC errors.
+	// A corrected texture memory error is not an ECC error, so it does not generate a single bit event.
+	EventTypeSingleBitECCError = EventType(0x0000000000000001)
+	// Event about double bit ECC errors.
+	// An uncorrected texture memory error is not an ECC error, so it does not generate a double bit event.
+	EventTypeDoubleBitECCError = EventType(0x0000000000000002)
+	// Event about PState changes.
+	// On Fermi architecture PState changes are also an indicator that GPU is throttling down due to no work being
+	// executed on the GPU, power capping or thermal capping. In a typical situation, Fermi-based GPU should stay in
+	// P0 for the duration of the execution of the compute process.
+	EventTypePState = EventType(0x0000000000000004)
+	// Event that Xid critical error occurred.
+	EventTypeXIDCriticalError = EventType(0x0000000000000008)
+	// Event about clock changes. Kepler only.
+	EventTypeClock = EventType(0x0000000000000010)
+	// Event about AC/Battery power source changes.
+	EventTypePowerSourceChange = EventType(0x0000000000000080)
+	// Event about MIG configuration changes.
+	EventTypeMIGConfigChange = EventType(0x0000000000000100)
+	// Mask with no events.
+	EventTypeNone = EventType(0x0000000000000000)
+	// Mask of all events.
+	EventTypeAll = EventTypeNone |
+		EventTypeSingleBitECCError |
+		EventTypeDoubleBitECCError |
+		EventTypePState |
+		EventTypeClock |
+		EventTypeXIDCriticalError |
+		EventTypePowerSourceChange |
+		EventTypeMIGConfigChange
+)
+
+// EventData holds information about an event that has occurred.
+type EventData struct {
+	// Specific device where the event occurred.
+	Device Device
+	// Information about what specific event occurred.
+	Type EventType
+	// Xid error for the device in the event of EventTypeXIDCriticalError, 0 otherwise.
+	XID uint64
+	// GPU instance ID of the MIG device where the event occurred.
+	// Set to math.MaxUint32 when not applicable or not reported by the driver.
+	GPUInstanceID uint32
+	// Compute instance ID of the MIG device where the event occurred.
+	// Set to math.MaxUint32 when not applicable or not reported by the driver.
+	ComputeInstanceID uint32
+}
+
+// Unit represents native NVML unit handle. Units are S-class enclosures, containing one or more GPUs.
+type Unit uintptr
+
+// UnitInfo holds static S-class unit info.
+type UnitInfo struct {
+	Name            string // Product name
+	ID              string // Product identifier
+	Serial          string // Product serial number
+	FirmwareVersion string // Firmware version
+}
+
+// LEDColor represents the color of the LED on a unit.
+type LEDColor int32
+
+//noinspection GoUnusedConst
+const (
+	LEDColorGreen = LEDColor(0) // GREEN, indicates good health
+	LEDColorAmber = LEDColor(1) // AMBER, indicates problem
+)
+
+// LEDState holds the LED states for an S-class unit.
+type LEDState struct {
+	Cause string   // If amber, a text description of the cause
+	Color LEDColor // GREEN or AMBER
+}
+
+// PSUInfo holds power supply information for an S-class unit.
+type PSUInfo struct {
+	State   string // The power supply state
+	Current uint32 // PSU current (A)
+	Voltage uint32 // PSU voltage (V)
+	Power   uint32 // PSU power draw (W)
+}
+
+// UnitTemperatureSensor represents the temperature readings available on an S-class unit.
+type UnitTemperatureSensor uint32
+
+//noinspection GoUnusedConst
+const (
+	UnitTemperatureIntake  = UnitTemperatureSensor(0) // Intake temperature
+	UnitTemperatureExhaust = UnitTemperatureSensor(1) // Exhaust temperature
+	UnitTemperatureBoard   = UnitTemperatureSensor(2) // Board temperature
+)
+
+// FanState represents the state of a fan on an S-class unit.
+type FanState int32
+
+//noinspection GoUnusedConst
+const (
+	FanNormal = FanState(0) // Fan is working properly
+	FanFailed = FanState(1) // Fan has failed
+)
+
+// UnitFanInfo holds fan speed reading for a single fan in an S-class unit.
+type UnitFanInfo struct {
+	Speed uint32   // Fan speed (RPM)
+	State FanState // Flag that indicates whether fan is working properly
+}
+
+// P2PCapsIndex represents the peer-to-peer capability to query with DeviceGetP2PStatus.
+type P2PCapsIndex int32
+
+//noinspection GoUnusedConst
+const (
+	P2PCapsIndexRead    = P2PCapsIndex(0)
+	P2PCapsIndexWrite   = P2PCapsIndex(1)
+	P2PCapsIndexNVLink  = P2PCapsIndex(2)
+	P2PCapsIndexAtomics = P2PCapsIndex(3)
+	P2PCapsIndexProp    = P2PCapsIndex(4)
+	P2PCapsIndexUnknown = P2PCapsIndex(5)
+)
+
+// P2PStatus represents the peer-to-peer status between two devices.
+type P2PStatus int32
+
+//noinspection GoUnusedConst
+const (
+	P2PStatusOK                      = P2PStatus(0)
+	P2PStatusChipsetNotSupported     = P2PStatus(1)
+	P2PStatusGPUNotSupported         = P2PStatus(2)
+	P2PStatusIOHTopologyNotSupported = P2PStatus(3)
+	P2PStatusDisabledByRegkey        = P2PStatus(4)
+	P2PStatusNotSupported            = P2PStatus(5)
+	P2PStatusUnknown                 = P2PStatus(6)
+)
+
+func (s P2PStatus) String() string {
+	switch s {
+	case P2PStatusOK:
+		return "OK"
+	case P2PStatusChipsetNotSupported:
+		return "ChipsetNotSupported"
+	case P2PStatusGPUNotSupported:
+		return "GPUNotSupported"
+	case P2PStatusIOHTopologyNotSupported:
+		return "IOHTopologyNotSupported"
+	case P2PStatusDisabledByRegkey:
+		return "DisabledByRegkey"
+	case P2PStatusNotSupported:
+		return "NotSupported"
+	default:
+		return "Unknown"
+	}
+}
+
+// SamplingType represents the type of sampling event queried with DeviceGetSamples.
+type SamplingType int32
+
+//noinspection GoUnusedConst
+const (
+	SamplingTypeTotalPower         = SamplingType(0) // To represent total power drawn by GPU
+	SamplingTypeGPUUtilization     = SamplingType(1) // To represent percent of time during which one or more kernels was executing on the GPU
+	SamplingTypeMemoryUtilization  = SamplingType(2) // To represent percent of time during which global (device) memory was being read or written
+	SamplingTypeEncoderUtilization = SamplingType(3) // To represent percent of time during which NVENC remains busy
+	SamplingTypeDecoderUtilization = SamplingType(4) // To represent percent of time during which NVDEC remains busy
+	SamplingTypeProcessorClock     = SamplingType(5) // To represent processor clock samples
+	SamplingTypeMemoryClock        = SamplingType(6) // To represent memory clock samples
+)
+
+// ValueType represents the type of the values returned by DeviceGetSamples and DeviceGetFieldValues.
+type ValueType int32
+
+//noinspection GoUnusedConst
+const (
+	ValueTypeDouble           = ValueType(0) // Values are float64
+	ValueTypeUnsignedInt      = ValueType(1) // Values are uint32
+	ValueTypeUnsignedLong     = ValueType(2) // Values are uint64
+	ValueTypeUnsignedLongLong = ValueType(3) // Values are uint64
+	ValueTypeSignedLongLong   = ValueType(4) // Values are int64
+)
+
+// Sample holds information about the GPU sample.
+type Sample struct {
+	TimeStamp uint64 // CPU Timestamp in microseconds
+	// Sample value, a float64, uint32, uint64 or int64 depending on the ValueType
+	Value interface{}
+}
+
+// BridgeChipType represents the type of a bridge chip.
+type BridgeChipType int32
+
+//noinspection GoUnusedConst
+const (
+	BridgeChipPLX  = BridgeChipType(0)
+	BridgeChipBRO4 = BridgeChipType(1)
+)
+
+func (b BridgeChipType) String() string {
+	switch b {
+	case BridgeChipPLX:
+		return "PLX"
+	case BridgeChipBRO4:
+		return "BRO4"
+	default:
+		return "Unknown"
+	}
+}
+
+// BridgeChipInfo holds information about a bridge chip.
+type BridgeChipInfo struct {
+	Type            BridgeChipType // Type of the bridge chip
+	FirmwareVersion uint32         // Firmware version (0 on boards without a bridge chip firmware)
+}
+
+// BridgeChipHierarchy holds the information about all the bridge chips of a board, from the one nearest to the
+// device to the one nearest to the root complex.
+type BridgeChipHierarchy struct {
+	BridgeChips []BridgeChipInfo
+}
+
+// NvLinkMaxLinks is the maximum number of NvLinks a device can have.
+const NvLinkMaxLinks = 18
+
+// NvLinkCapability represents the NvLink capabilities that can be queried with DeviceGetNvLinkCapability.
+type NvLinkCapability int32
+
+//noinspection GoUnusedConst
+const (
+	NvLinkCapabilityP2PSupported  = NvLinkCapability(0) // P2P over NVLink is supported
+	NvLinkCapabilitySysmemAccess  = NvLinkCapability(1) // Access to system memory is supported
+	NvLinkCapabilityP2PAtomics    = NvLinkCapability(2) // P2P atomics are supported
+	NvLinkCapabilitySysmemAtomics = NvLinkCapability(3) // System memory atomics are supported
+	NvLinkCapabilitySLIBridge     = NvLinkCapability(4) // SLI is supported over this link
+	NvLinkCapabilityValid         = NvLinkCapability(5) // Link is supported on this device
+)
+
+// NvLinkErrorCounter represents the NvLink error counters that can be queried with DeviceGetNvLinkErrorCounter.
+type NvLinkErrorCounter int32
+
+//noinspection GoUnusedConst
+const (
+	NvLinkErrorDLReplay   = NvLinkErrorCounter(0) // Data link transmit replay error counter
+	NvLinkErrorDLRecovery = NvLinkErrorCounter(1) // Data link transmit recovery error counter
+	NvLinkErrorDLCRCFlit  = NvLinkErrorCounter(2) // Data link receive flow control digit CRC error counter
+	NvLinkErrorDLCRCData  = NvLinkErrorCounter(3) // Data link receive data CRC error counter
+	NvLinkErrorDLECCData  = NvLinkErrorCounter(4) // Data link receive data ECC error counter
+)
+
+// NvLinkUtilizationCountUnits represents the units of a NvLink utilization counter.
+type NvLinkUtilizationCountUnits int32
+
+//noinspection GoUnusedConst
+const (
+	NvLinkCounterUnitCycles   = NvLinkUtilizationCountUnits(0) // count by cycles
+	NvLinkCounterUnitPackets  = NvLinkUtilizationCountUnits(1) // count by packets
+	NvLinkCounterUnitBytes    = NvLinkUtilizationCountUnits(2) // count by bytes
+	NvLinkCounterUnitReserved = NvLinkUtilizationCountUnits(3) // count reserved for internal use
+)
+
+// NvLinkUtilizationCountPktTypes is a bit mask of the packet types counted by a NvLink utilization counter.
+type NvLinkUtilizationCountPktTypes uint32
+
+//noinspection GoUnusedConst
+const (
+	NvLinkCounterPktTypeNop        = NvLinkUtilizationCountPktTypes(0x1)  // no operation packets
+	NvLinkCounterPktTypeRead       = NvLinkUtilizationCountPktTypes(0x2)  // read packets
+	NvLinkCounterPktTypeWrite      = NvLinkUtilizationCountPktTypes(0x4)  // write packets
+	NvLinkCounterPktTypeRatom      = NvLinkUtilizationCountPktTypes(0x8)  // reduction atomic requests
+	NvLinkCounterPktTypeNratom     = NvLinkUtilizationCountPktTypes(0x10) // non-reduction atomic requests
+	NvLinkCounterPktTypeFlush      = NvLinkUtilizationCountPktTypes(0x20) // flush requests
+	NvLinkCounterPktTypeRespData   = NvLinkUtilizationCountPktTypes(0x40) // responses with data
+	NvLinkCounterPktTypeRespNoData = NvLinkUtilizationCountPktTypes(0x80) // responses without data
+	NvLinkCounterPktTypeAll        = NvLinkUtilizationCountPktTypes(0xFF) // all packets
+)
+
+// NvLinkUtilizationControl configures what a NvLink utilization counter counts.
+type NvLinkUtilizationControl struct {
+	Units        NvLinkUtilizationCountUnits
+	PacketFilter NvLinkUtilizationCountPktTypes
+}
+
+// MigMode represents the Multi-Instance GPU mode of a device.
+type MigMode uint32
+
+//noinspection GoUnusedConst
+const (
+	MigModeDisabled = MigMode(0)
+	MigModeEnabled  = MigMode(1)
+)
+
+// GpuInstance represents a native NVML GPU instance handle.
+type GpuInstance uintptr
+
+// ComputeInstance represents a native NVML compute instance handle.
+type ComputeInstance uintptr
+
+// GpuInstanceProfile represents a GPU instance profile, queried with DeviceGetGpuInstanceProfileInfo.
+type GpuInstanceProfile uint32
+
+//noinspection GoUnusedConst
+const (
+	GpuInstanceProfile1Slice     = GpuInstanceProfile(0)
+	GpuInstanceProfile2Slice     = GpuInstanceProfile(1)
+	GpuInstanceProfile3Slice     = GpuInstanceProfile(2)
+	GpuInstanceProfile4Slice     = GpuInstanceProfile(3)
+	GpuInstanceProfile7Slice     = GpuInstanceProfile(4)
+	GpuInstanceProfile8Slice     = GpuInstanceProfile(5)
+	GpuInstanceProfile6Slice     = GpuInstanceProfile(6)
+	GpuInstanceProfile1SliceRev1 = GpuInstanceProfile(7)
+	GpuInstanceProfile2SliceRev1 = GpuInstanceProfile(8)
+	GpuInstanceProfile1SliceRev2 = GpuInstanceProfile(9)
+	GpuInstanceProfileCount      = GpuInstanceProfile(10)
+)
+
+// GpuInstancePlacement describes the memory slices occupied by a GPU instance.
+type GpuInstancePlacement struct {
+	Start uint32 // Index of first occupied memory slice
+	Size  uint32 // Number of memory slices occupied
+}
+
+// GpuInstanceProfileInfo holds the properties of a GPU instance profile.
+type GpuInstanceProfileInfo struct {
+	ID                  uint32 // Unique profile ID within the device
+	IsP2PSupported      uint32 // Peer-to-Peer support
+	SliceCount          uint32 // GPU Slice count
+	InstanceCount       uint32 // GPU instance count
+	MultiprocessorCount uint32 // Streaming Multiprocessor count
+	CopyEngineCount     uint32 // Copy Engine count
+	DecoderCount        uint32 // Decoder Engine count
+	EncoderCount        uint32 // Encoder Engine count
+	JPEGCount           uint32 // JPEG Engine count
+	OFACount            uint32 // OFA Engine count
+	MemorySizeMB        uint64 // Memory size in MBytes
+}
+
+// GpuInstanceInfo holds the properties of a GPU instance.
+type GpuInstanceInfo struct {
+	Device    Device               // Parent device
+	ID        uint32               // Unique instance ID within the device
+	ProfileID uint32               // Unique profile ID within the device
+	Placement GpuInstancePlacement // Placement for this instance
+}
+
+// ComputeInstanceProfile represents a compute instance profile, queried with GpuInstanceGetComputeInstanceProfileInfo.
+type ComputeInstanceProfile uint32
+
+//noinspection GoUnusedConst
+const (
+	ComputeInstanceProfile1Slice     = ComputeInstanceProfile(0)
+	ComputeInstanceProfile2Slice     = ComputeInstanceProfile(1)
+	ComputeInstanceProfile3Slice     = ComputeInstanceProfile(2)
+	ComputeInstanceProfile4Slice     = ComputeInstanceProfile(3)
+	ComputeInstanceProfile7Slice     = ComputeInstanceProfile(4)
+	ComputeInstanceProfile8Slice     = ComputeInstanceProfile(5)
+	ComputeInstanceProfile6Slice     = ComputeInstanceProfile(6)
+	ComputeInstanceProfile1SliceRev1 = ComputeInstanceProfile(7)
+	ComputeInstanceProfileCount      = ComputeInstanceProfile(8)
+)
+
+// ComputeInstanceEngineProfile represents how the engines of a GPU instance are split between its compute instances.
+type ComputeInstanceEngineProfile uint32
+
+//noinspection GoUnusedConst
+const (
+	ComputeInstanceEngineProfileShared = ComputeInstanceEngineProfile(0) // All the engines are shared
+)
+
+// ComputeInstanceProfileInfo holds the properties of a compute instance profile.
+type ComputeInstanceProfileInfo struct {
+	ID                    uint32 // Unique profile ID within the GPU instance
+	SliceCount            uint32 // GPU Slice count
+	InstanceCount         uint32 // Compute instance count
+	MultiprocessorCount   uint32 // Streaming Multiprocessor count
+	SharedCopyEngineCount uint32 // Shared Copy Engine count
+	SharedDecoderCount    uint32 // Shared Decoder Engine count
+	SharedEncoderCount    uint32 // Shared Encoder Engine count
+	SharedJPEGCount       uint32 // Shared JPEG Engine count
+	SharedOFACount        uint32 // Shared OFA Engine count
+}
+
+// ComputeInstancePlacement describes the GPU slices occupied by a compute instance.
+type ComputeInstancePlacement struct {
+	Start uint32 // Index of first occupied compute slice
+	Size  uint32 // Number of compute slices occupied
+}
+
+// ComputeInstanceInfo holds the properties of a compute instance.
+type ComputeInstanceInfo struct {
+	Device      Device                   // Parent device
+	GpuInstance GpuInstance              // Parent GPU instance
+	ID          uint32                   // Unique instance ID within the GPU instance
+	ProfileID   uint32                   // Unique profile ID within the GPU instance
+	Placement   ComputeInstancePlacement // Placement for this instance within the GPU instance's slice range
+}
+
+// AccountingStats describes the accounting statistics of a process.
+type AccountingStats struct {
+	GPUUtilization    uint32 // Percent of time over the process's lifetime during which one or more kernels was executing on the GPU
+	MemoryUtilization uint32 // Percent of time over the process's lifetime during which global (device) memory was being read or written
+	MaxMemoryUsage    uint64 // Maximum total memory in bytes that was ever allocated by the process
+	Time              uint64 // Amount of time in ms during which the compute context was active, 0 while the process is running
+	StartTime         uint64 // CPU Timestamp in usec representing start time for the process
+	IsRunning         bool   // Flag to represent if the process is running
+}
+
+// ProcessUtilizationSample holds the utilization of a process sampled by the driver.
+type ProcessUtilizationSample struct {
+	PID       uint32
+	TimeStamp uint64 // CPU Timestamp in microseconds
+	SMUtil    uint32 // SM (3D/Compute) Util Value
+	MemUtil   uint32 // Frame Buffer Memory Util Value
+	EncUtil   uint32 // Encoder Util Value
+	DecUtil   uint32 // Decoder Util Value
+}
+
+// FieldID identifies a field queried with DeviceGetFieldValues.
+type FieldID uint32
+
+//noinspection GoUnusedConst
+const (
+	FieldECCCurrent                    = FieldID(1)   // Current ECC mode, 1 if enabled
+	FieldECCPending                    = FieldID(2)   // Pending ECC mode, 1 if enabled
+	FieldECCSBEVolTotal                = FieldID(3)   // Total single bit volatile ECC errors
+	FieldECCDBEVolTotal                = FieldID(4)   // Total double bit volatile ECC errors
+	FieldECCSBEAggTotal                = FieldID(5)   // Total single bit aggregate (persistent) ECC errors
+	FieldECCDBEAggTotal                = FieldID(6)   // Total double bit aggregate (persistent) ECC errors
+	FieldECCSBEVolL1                   = FieldID(7)   // Single bit volatile ECC errors in L1 cache
+	FieldECCDBEVolL1                   = FieldID(8)   // Double bit volatile ECC errors in L1 cache
+	FieldECCSBEVolL2                   = FieldID(9)   // Single bit volatile ECC errors in L2 cache
+	FieldECCDBEVolL2                   = FieldID(10)  // Double bit volatile ECC errors in L2 cache
+	FieldECCSBEVolDev                  = FieldID(11)  // Single bit volatile ECC errors in device memory
+	FieldECCDBEVolDev                  = FieldID(12)  // Double bit volatile ECC errors in device memory
+	FieldECCSBEVolReg                  = FieldID(13)  // Single bit volatile ECC errors in register file
+	FieldECCDBEVolReg                  = FieldID(14)  // Double bit volatile ECC errors in register file
+	FieldECCSBEVolTex                  = FieldID(15)  // Single bit volatile ECC errors in texture memory
+	FieldECCDBEVolTex                  = FieldID(16)  // Double bit volatile ECC errors in texture memory
+	FieldECCDBEVolCBU                  = FieldID(17)  // Double bit volatile ECC errors in CBU
+	FieldECCSBEAggL1                   = FieldID(18)  // Single bit aggregate (persistent) ECC errors in L1 cache
+	FieldECCDBEAggL1                   = FieldID(19)  // Double bit aggregate (persistent) ECC errors in L1 cache
+	FieldECCSBEAggL2                   = FieldID(20)  // Single bit aggregate (persistent) ECC errors in L2 cache
+	FieldECCDBEAggL2                   = FieldID(21)  // Double bit aggregate (persistent) ECC errors in L2 cache
+	FieldECCSBEAggDev                  = FieldID(22)  // Single bit aggregate (persistent) ECC errors in device memory
+	FieldECCDBEAggDev                  = FieldID(23)  // Double bit aggregate (persistent) ECC errors in device memory
+	FieldECCSBEAggReg                  = FieldID(24)  // Single bit aggregate (persistent) ECC errors in register file
+	FieldECCDBEAggReg                  = FieldID(25)  // Double bit aggregate (persistent) ECC errors in register file
+	FieldECCSBEAggTex                  = FieldID(26)  // Single bit aggregate (persistent) ECC errors in texture memory
+	FieldECCDBEAggTex                  = FieldID(27)  // Double bit aggregate (persistent) ECC errors in texture memory
+	FieldECCDBEAggCBU                  = FieldID(28)  // Double bit aggregate (persistent) ECC errors in CBU
+	FieldRetiredSBE                    = FieldID(29)  // Number of retired pages because of single bit errors
+	FieldRetiredDBE                    = FieldID(30)  // Number of retired pages because of double bit errors
+	FieldRetiredPending                = FieldID(31)  // 1 if any pages are pending retirement due to a reboot
+	FieldNvLinkCRCFlitErrorCountL0     = FieldID(32)  // NvLink flow control CRC error counter for lane 0
+	FieldNvLinkCRCFlitErrorCountL1     = FieldID(33)  // NvLink flow control CRC error counter for lane 1
+	FieldNvLinkCRCFlitErrorCountL2     = FieldID(34)  // NvLink flow control CRC error counter for lane 2
+	FieldNvLinkCRCFlitErrorCountL3     = FieldID(35)  // NvLink flow control CRC error counter for lane 3
+	FieldNvLinkCRCFlitErrorCountL4     = FieldID(36)  // NvLink flow control CRC error counter for lane 4
+	FieldNvLinkCRCFlitErrorCountL5     = FieldID(37)  // NvLink flow control CRC error counter for lane 5
+	FieldNvLinkCRCFlitErrorCountTotal  = FieldID(38)  // NvLink flow control CRC error counter total for all lanes
+	FieldNvLinkCRCDataErrorCountL0     = FieldID(39)  // NvLink data CRC error counter for lane 0
+	FieldNvLinkCRCDataErrorCountL1     = FieldID(40)  // NvLink data CRC error counter for lane 1
+	FieldNvLinkCRCDataErrorCountL2     = FieldID(41)  // NvLink data CRC error counter for lane 2
+	FieldNvLinkCRCDataErrorCountL3     = FieldID(42)  // NvLink data CRC error counter for lane 3
+	FieldNvLinkCRCDataErrorCountL4     = FieldID(43)  // NvLink data CRC error counter for lane 4
+	FieldNvLinkCRCDataErrorCountL5     = FieldID(44)  // NvLink data CRC error counter for lane 5
+	FieldNvLinkCRCDataErrorCountTotal  = FieldID(45)  // NvLink data CRC error counter total for all lanes
+	FieldNvLinkReplayErrorCountL0      = FieldID(46)  // NvLink replay error counter for lane 0
+	FieldNvLinkReplayErrorCountL1      = FieldID(47)  // NvLink replay error counter for lane 1
+	FieldNvLinkReplayErrorCountL2      = FieldID(48)  // NvLink replay error counter for lane 2
+	FieldNvLinkReplayErrorCountL3      = FieldID(49)  // NvLink replay error counter for lane 3
+	FieldNvLinkReplayErrorCountL4      = FieldID(50)  // NvLink replay error counter for lane 4
+	FieldNvLinkReplayErrorCountL5      = FieldID(51)  // NvLink replay error counter for lane 5
+	FieldNvLinkReplayErrorCountTotal   = FieldID(52)  // NvLink replay error counter total for all lanes
+	FieldNvLinkRecoveryErrorCountL0    = FieldID(53)  // NvLink recovery error counter for lane 0
+	FieldNvLinkRecoveryErrorCountL1    = FieldID(54)  // NvLink recovery error counter for lane 1
+	FieldNvLinkRecoveryErrorCountL2    = FieldID(55)  // NvLink recovery error counter for lane 2
+	FieldNvLinkRecoveryErrorCountL3    = FieldID(56)  // NvLink recovery error counter for lane 3
+	FieldNvLinkRecoveryErrorCountL4    = FieldID(57)  // NvLink recovery error counter for lane 4
+	FieldNvLinkRecoveryErrorCountL5    = FieldID(58)  // NvLink recovery error counter for lane 5
+	FieldNvLinkRecoveryErrorCountTotal = FieldID(59)  // NvLink recovery error counter total for all lanes
+	FieldNvLinkBandwidthC0L0           = FieldID(60)  // NvLink bandwidth counter 0 for lane 0
+	FieldNvLinkBandwidthC0L1           = FieldID(61)  // NvLink bandwidth counter 0 for lane 1
+	FieldNvLinkBandwidthC0L2           = FieldID(62)  // NvLink bandwidth counter 0 for lane 2
+	FieldNvLinkBandwidthC0L3           = FieldID(63)  // NvLink bandwidth counter 0 for lane 3
+	FieldNvLinkBandwidthC0L4           = FieldID(64)  // NvLink bandwidth counter 0 for lane 4
+	FieldNvLinkBandwidthC0L5           = FieldID(65)  // NvLink bandwidth counter 0 for lane 5
+	FieldNvLinkBandwidthC0Total        = FieldID(66)  // NvLink bandwidth counter 0 total for all lanes
+	FieldNvLinkBandwidthC1L0           = FieldID(67)  // NvLink bandwidth counter 1 for lane 0
+	FieldNvLinkBandwidthC1L1           = FieldID(68)  // NvLink bandwidth counter 1 for lane 1
+	FieldNvLinkBandwidthC1L2           = FieldID(69)  // NvLink bandwidth counter 1 for lane 2
+	FieldNvLinkBandwidthC1L3           = FieldID(70)  // NvLink bandwidth counter 1 for lane 3
+	FieldNvLinkBandwidthC1L4           = FieldID(71)  // NvLink bandwidth counter 1 for lane 4
+	FieldNvLinkBandwidthC1L5           = FieldID(72)  // NvLink bandwidth counter 1 for lane 5
+	FieldNvLinkBandwidthC1Total        = FieldID(73)  // NvLink bandwidth counter 1 total for all lanes
+	FieldPerfPolicyPower               = FieldID(74)  // Perf policy violation time in microseconds due to power
+	FieldPerfPolicyThermal             = FieldID(75)  // Perf policy violation time in microseconds due to thermal
+	FieldPerfPolicySyncBoost           = FieldID(76)  // Perf policy violation time in microseconds due to sync boost
+	FieldPerfPolicyBoardLimit          = FieldID(77)  // Perf policy violation time in microseconds due to board limit
+	FieldPerfPolicyLowUtilization      = FieldID(78)  // Perf policy violation time in microseconds due to low utilization
+	FieldPerfPolicyReliability         = FieldID(79)  // Perf policy violation time in microseconds due to reliability
+	FieldPerfPolicyTotalAppClocks      = FieldID(80)  // Perf policy violation time in microseconds due to app clocks
+	FieldPerfPolicyTotalBaseClocks     = FieldID(81)  // Perf policy violation time in microseconds due to base clocks
+	FieldMemoryTemp                    = FieldID(82)  // Memory temperature for the device
+	FieldTotalEnergyConsumption        = FieldID(83)  // Total energy consumption for the GPU in mJ since the driver was last reloaded
+	FieldNvLinkSpeedMbpsL0             = FieldID(84)  // NvLink speed in MB/s for link 0
+	FieldNvLinkSpeedMbpsL1             = FieldID(85)  // NvLink speed in MB/s for link 1
+	FieldNvLinkSpeedMbpsL2             = FieldID(86)  // NvLink speed in MB/s for link 2
+	FieldNvLinkSpeedMbpsL3             = FieldID(87)  // NvLink speed in MB/s for link 3
+	FieldNvLinkSpeedMbpsL4             = FieldID(88)  // NvLink speed in MB/s for link 4
+	FieldNvLinkSpeedMbpsL5             = FieldID(89)  // NvLink speed in MB/s for link 5
+	FieldNvLinkSpeedMbpsCommon         = FieldID(90)  // Common NvLink speed in MB/s for all active links
+	FieldNvLinkLinkCount               = FieldID(91)  // Number of NvLinks present on the device
+	FieldRetiredPendingSBE             = FieldID(92)  // 1 if any pages are pending retirement due to a single bit error
+	FieldRetiredPendingDBE             = FieldID(93)  // 1 if any pages are pending retirement due to a double bit error
+	FieldPCIeReplayCounter             = FieldID(94)  // PCIe replay counter
+	FieldPCIeReplayRolloverCounter     = FieldID(95)  // PCIe replay rollover counter
+	FieldNvLinkCRCFlitErrorCountL6     = FieldID(96)  // NvLink flow control CRC error counter for lane 6
+	FieldNvLinkCRCFlitErrorCountL7     = FieldID(97)  // NvLink flow control CRC error counter for lane 7
+	FieldNvLinkCRCFlitErrorCountL8     = FieldID(98)  // NvLink flow control CRC error counter for lane 8
+	FieldNvLinkCRCFlitErrorCountL9     = FieldID(99)  // NvLink flow control CRC error counter for lane 9
+	FieldNvLinkCRCFlitErrorCountL10    = FieldID(100) // NvLink flow control CRC error counter for lane 10
+	FieldNvLinkCRCFlitErrorCountL11    = FieldID(101) // NvLink flow control CRC error counter for lane 11
+	FieldNvLinkCRCDataErrorCountL6     = FieldID(102) // NvLink data CRC error counter for lane 6
+	FieldNvLinkCRCDataErrorCountL7     = FieldID(103) // NvLink data CRC error counter for lane 7
+	FieldNvLinkCRCDataErrorCountL8     = FieldID(104) // NvLink data CRC error counter for lane 8
+	FieldNvLinkCRCDataErrorCountL9     = FieldID(105) // NvLink data CRC error counter for lane 9
+	FieldNvLinkCRCDataErrorCountL10    = FieldID(106) // NvLink data CRC error counter for lane 10
+	FieldNvLinkCRCDataErrorCountL11    = FieldID(107) // NvLink data CRC error counter for lane 11
+	FieldNvLinkReplayErrorCountL6      = FieldID(108) // NvLink replay error counter for lane 6
+	FieldNvLinkReplayErrorCountL7      = FieldID(109) // NvLink replay error counter for lane 7
+	FieldNvLinkReplayErrorCountL8      = FieldID(110) // NvLink replay error counter for lane 8
+	FieldNvLinkReplayErrorCountL9      = FieldID(111) // NvLink replay error counter for lane 9
+	FieldNvLinkReplayErrorCountL10     = FieldID(112) // NvLink replay error counter for lane 10
+	FieldNvLinkReplayErrorCountL11     = FieldID(113) // NvLink replay error counter for lane 11
+	FieldNvLinkRecoveryErrorCountL6    = FieldID(114) // NvLink recovery error counter for lane 6
+	FieldNvLinkRecoveryErrorCountL7    = FieldID(115) // NvLink recovery error counter for lane 7
+	FieldNvLinkRecoveryErrorCountL8    = FieldID(116) // NvLink recovery error counter for lane 8
+	FieldNvLinkRecoveryErrorCountL9    = FieldID(117) // NvLink recovery error counter for lane 9
+	FieldNvLinkRecoveryErrorCountL10   = FieldID(118) // NvLink recovery error counter for lane 10
+	FieldNvLinkRecoveryErrorCountL11   = FieldID(119) // NvLink recovery error counter for lane 11
+	FieldNvLinkBandwidthC0L6           = FieldID(120) // NvLink bandwidth counter 0 for lane 6
+	FieldNvLinkBandwidthC0L7           = FieldID(121) // NvLink bandwidth counter 0 for lane 7
+	FieldNvLinkBandwidthC0L8           = FieldID(122) // NvLink bandwidth counter 0 for lane 8
+	FieldNvLinkBandwidthC0L9           = FieldID(123) // NvLink bandwidth counter 0 for lane 9
+	FieldNvLinkBandwidthC0L10          = FieldID(124) // NvLink bandwidth counter 0 for lane 10
+	FieldNvLinkBandwidthC0L11          = FieldID(125) // NvLink bandwidth counter 0 for lane 11
+	FieldNvLinkBandwidthC1L6           = FieldID(126) // NvLink bandwidth counter 1 for lane 6
+	FieldNvLinkBandwidthC1L7           = FieldID(127) // NvLink bandwidth counter 1 for lane 7
+	FieldNvLinkBandwidthC1L8           = FieldID(128) // NvLink bandwidth counter 1 for lane 8
+	FieldNvLinkBandwidthC1L9           = FieldID(129) // NvLink bandwidth counter 1 for lane 9
+	FieldNvLinkBandwidthC1L10          = FieldID(130) // NvLink bandwidth counter 1 for lane 10
+	FieldNvLinkBandwidthC1L11          = FieldID(131) // NvLink bandwidth counter 1 for lane 11
+	FieldNvLinkSpeedMbpsL6             = FieldID(132) // NvLink speed in MB/s for link 6
+	FieldNvLinkSpeedMbpsL7             = FieldID(133) // NvLink speed in MB/s for link 7
+	FieldNvLinkSpeedMbpsL8             = FieldID(134) // NvLink speed in MB/s for link 8
+	FieldNvLinkSpeedMbpsL9             = FieldID(135) // NvLink speed in MB/s for link 9
+	FieldNvLinkSpeedMbpsL10            = FieldID(136) // NvLink speed in MB/s for link 10
+	FieldNvLinkSpeedMbpsL11            = FieldID(137) // NvLink speed in MB/s for link 11
+	FieldNvLinkThroughputDataTX        = FieldID(138) // NvLink TX data throughput in KiB
+	FieldNvLinkThroughputDataRX        = FieldID(139) // NvLink RX data throughput in KiB
+	FieldNvLinkThroughputRawTX         = FieldID(140) // NvLink TX data + protocol overhead throughput in KiB
+	FieldNvLinkThroughputRawRX         = FieldID(141) // NvLink RX data + protocol overhead throughput in KiB
+	FieldRemappedCor                   = FieldID(142) // Number of remapped rows due to correctable errors
+	FieldRemappedUnc                   = FieldID(143) // Number of remapped rows due to uncorrectable errors
+	FieldRemappedPending               = FieldID(144) // 1 if any rows are pending remapping, 0 otherwise
+	FieldRemappedFailure               = FieldID(145) // 1 if any rows failed to be remapped, 0 otherwise
+)
+
+// FieldRequest identifies a field to query with DeviceGetFieldValues or to clear with DeviceClearFieldValues.
+type FieldRequest struct {
+	FieldID FieldID
+	// Scope of the field, for instance the NvLink for per-link fields queried without a lane specific FieldID.
+	// Ignored by the fields that don't have a scope.
+	ScopeID uint32
+}
+
+// FieldValue holds the value of a field returned by DeviceGetFieldValues.
+type FieldValue struct {
+	FieldID     FieldID
+	ScopeID     uint32
+	TimeStamp   int64 // CPU Timestamp of this value in microseconds since 1970
+	LatencyUsec int64 // How long this field value took to update (in usec) within NVML
+	ValueType   ValueType
+	// Field value, a float64, uint32, uint64 or int64 depending on the ValueType. Nil when Err is set.
+	Value interface{}
+	// Error returned by NVML for this field, nil on success
+	Err error
+}
@@ -1,21 +1,18 @@
 package nvml
 
-import (
-	"C"
-	"unsafe"
-)
+import "C"
 
 // SystemGetCudaDriverVersion retrieves the version of the CUDA driver.
 // The returned CUDA driver version is the same as the CUDA API cuDriverGetVersion() would return on the system.
 func (a API) SystemGetCudaDriverVersion() (cudaDriverVersion int32, err error) {
-	err = a.call(a.nvmlSystemGetCudaDriverVersion, uintptr(unsafe.Pointer(&cudaDriverVersion)))
+	err = a.call(a.nvmlSystemGetCudaDriverVersion, &cudaDriverVersion)
 	return
 }
 
 // SystemGetDriverVersion retrieves the version of the system's graphics driver.
 func (a API) SystemGetDriverVersion() (string, error) {
 	buffer := [systemDriverVersionBufferSize]C.char{}
-	if err := a.call(a.nvmlSystemGetDriverVersion, uintptr(unsafe.Pointer(&buffer[0])), systemDriverVersionBufferSize); err != nil {
+	if err := a.call(a.nvmlSystemGetDriverVersion, &buffer, systemDriverVersionBufferSize); err != nil {
 		return "", err
 	}
 
@@ -25,7 +22,7 @@ func (a API) SystemGetDriverVersion() (string, error) {
 // SystemGetNVMLVersion retrieves the version of the NVML library.
 func (a API) SystemGetNVMLVersion() (string, error) {
 	buffer := [systemDriverVersionBufferSize]C.char{}
-	if err := a.call(a.nvmlSystemGetNVMLVersion, uintptr(unsafe.Pointer(&buffer[0])), systemDriverVersionBufferSize); err != nil {
+	if err := a.call(a.nvmlSystemGetNVMLVersion, &buffer, systemDriverVersionBufferSize); err != nil {
 		return "", err
 	}
 
@@ -37,9 +34,15 @@ func (a API) SystemGetProcessName(pid uint) (string, error) {
 	const maxLength = 256
 
 	buffer := [maxLength]C.char{}
-	if err := a.call(a.nvmlSystemGetProcessName, uintptr(pid), uintptr(unsafe.Pointer(&buffer[0])), maxLength); err != nil {
+	if err := a.call(a.nvmlSystemGetProcessName, uintptr(pid), &buffer, maxLength); err != nil {
 		return "", err
 	}
 
 	return C.GoString(&buffer[0]), nil
 }
+
+// SystemGetTopologyGpuSet retrieves the set of GPUs that have a CPU affinity with the given CPU number.
+// Supported on Linux only.
+func (a API) SystemGetTopologyGpuSet(cpuNumber uint32) ([]Device, error) {
+	return a.deviceList(a.nvmlSystemGetTopologyGpuSet, uintptr(cpuNumber))
+}
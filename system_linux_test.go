@@ -0,0 +1,33 @@
+// +build linux,cgo
+
+package nvml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The system entry points of the stub library, see newStubLibrary.
+func init() {
+	addStubFunctions("", map[string]string{
+		"nvmlSystemGetTopologyGpuSet": `int nvmlSystemGetTopologyGpuSet(unsigned int cpu, unsigned int *count, void **devices) {
+			if (cpu > 63) return 2;
+			if (*count < 1) { *count = 1; return 7; }
+			devices[0] = (void *)(uintptr_t)(0x1000 + cpu / 32);
+			*count = 1;
+			return 0;
+		}`,
+	})
+}
+
+func TestSystemGetTopologyGpuSetStub(t *testing.T) {
+	w := newStubAPI(t)
+
+	devices, err := w.SystemGetTopologyGpuSet(33)
+	require.NoError(t, err)
+	require.Equal(t, []Device{0x1001}, devices)
+
+	_, err = w.SystemGetTopologyGpuSet(64)
+	require.Equal(t, ErrInvalidArgument, err)
+}
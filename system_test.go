@@ -41,3 +41,11 @@ func TestSystemGetProcessName(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, name)
 }
+
+func TestSystemGetTopologyGpuSet(t *testing.T) {
+	w, _ := create(t)
+	defer w.Shutdown()
+
+	_, err := w.SystemGetTopologyGpuSet(0)
+	require.NoError(t, err)
+}
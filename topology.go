@@ -0,0 +1,278 @@
+package nvml
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+)
+
+// TopologyDevice describes a device of a Topology.
+type TopologyDevice struct {
+	Handle Device `json:"-"`
+	UUID   string `json:"uuid"`
+	BusID  string `json:"busId"`
+	// CPUs with an ideal affinity for the device, nil when unknown
+	CPUAffinity []int `json:"cpuAffinity"`
+}
+
+// Topology describes how the devices of the system are connected, like nvidia-smi topo -m.
+// Devices are listed by index, the matrices are indexed by device index.
+type Topology struct {
+	Devices []TopologyDevice
+	// Levels[i][j] is the common ancestor of devices i and j
+	Levels [][]GPUTopologyLevel
+	// NVLink[i][j] is true when devices i and j can talk peer-to-peer over NVLink, nil when unknown
+	NVLink [][]bool
+	// NVLinkCount[i][j] is the number of active NVLinks between devices i and j, nil when unknown
+	NVLinkCount [][]int
+}
+
+// NewTopology discovers all the devices of the system and how they are connected.
+// CPU affinity is only available on Linux. NVLink connectivity and link counts are left unknown when the driver
+// doesn't report them.
+func NewTopology(lib Interface) (*Topology, error) {
+	count, err := lib.DeviceGetCount()
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Topology{
+		Devices: make([]TopologyDevice, count),
+		Levels:  make([][]GPUTopologyLevel, count),
+	}
+
+	handles := make([]Device, count)
+	buses := make([]*PCIInfo, count)
+
+	for i := range t.Devices {
+		d := &t.Devices[i]
+
+		d.Handle, err = lib.DeviceGetHandleByIndex(uint32(i))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get handle of device %d", i)
+		}
+
+		d.UUID, err = lib.DeviceGetUUID(d.Handle)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get UUID of device %d", i)
+		}
+
+		pci, err := lib.DeviceGetPCIInfo(d.Handle)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get PCI info of device %d", i)
+		}
+
+		d.BusID = pci.BusID
+		handles[i], buses[i] = d.Handle, pci
+
+		d.CPUAffinity, err = cpuAffinity(lib, d.Handle)
+		if err != nil && err != ErrNotSupported {
+			return nil, errors.Wrapf(err, "failed to get CPU affinity of device %d", i)
+		}
+	}
+
+	for i := range t.Levels {
+		t.Levels[i] = make([]GPUTopologyLevel, count)
+		for j := range t.Levels[i] {
+			if i == j {
+				t.Levels[i][j] = TopologyInternal
+				continue
+			}
+
+			t.Levels[i][j], err = lib.DeviceGetTopologyCommonAncestor(t.Devices[i].Handle, t.Devices[j].Handle)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to get common ancestor of devices %d and %d", i, j)
+			}
+		}
+	}
+
+	nvlink, err := p2pMatrix(lib, handles, P2PCapsIndexNVLink)
+	if err != nil {
+		if cause := errors.Cause(err); cause != ErrNotSupported && cause != ErrFunctionNotFound {
+			return nil, err
+		}
+
+		return t, nil
+	}
+
+	t.NVLink = make([][]bool, count)
+	for i := range nvlink {
+		t.NVLink[i] = make([]bool, count)
+		for j := range nvlink[i] {
+			t.NVLink[i][j] = i != j && nvlink[i][j] == P2PStatusOK
+		}
+	}
+
+	graph, err := newNvLinkGraph(lib, handles, buses)
+	if err != nil {
+		if cause := errors.Cause(err); cause != ErrNotSupported && cause != ErrFunctionNotFound {
+			return nil, err
+		}
+
+		return t, nil
+	}
+
+	t.NVLinkCount = make([][]int, count)
+	for i := range t.NVLinkCount {
+		t.NVLinkCount[i] = make([]int, count)
+		for j := range t.NVLinkCount[i] {
+			if i != j {
+				t.NVLinkCount[i][j] = graph.LinkCount(i, j)
+			}
+		}
+	}
+
+	return t, nil
+}
+
+// Label returns the nvidia-smi topo label of the connection between devices i and j:
+// X for the device itself, NV# for NVLink connections with # the number of links (NV when the links don't connect
+// the devices directly, e.g. through a NVSwitch), the abbreviation of the common ancestor otherwise.
+func (t *Topology) Label(i, j int) string {
+	if i == j {
+		return "X"
+	}
+
+	if t.NVLink != nil && t.NVLink[i][j] {
+		if t.NVLinkCount != nil && t.NVLinkCount[i][j] > 0 {
+			return fmt.Sprintf("NV%d", t.NVLinkCount[i][j])
+		}
+
+		return "NV"
+	}
+
+	return t.Levels[i][j].String()
+}
+
+// distance ranks the connection between devices i and j, lower is tighter.
+// NVLink connections rank before any PCIe path.
+func (t *Topology) distance(i, j int) int {
+	if t.NVLink != nil && t.NVLink[i][j] {
+		return 0
+	}
+
+	return int(t.Levels[i][j]) + 1
+}
+
+// BestGroup returns the indices of the k devices with the tightest interconnect: the group with the closest
+// furthest connection between two of its devices, then with the closest connections overall.
+// Ties are broken in favor of the lowest indices. Every group is evaluated, which is fine for a single machine.
+func (t *Topology) BestGroup(k int) ([]int, error) {
+	n := len(t.Devices)
+	if k <= 0 || k > n {
+		return nil, errors.Errorf("can't pick %d devices out of %d", k, n)
+	}
+
+	var (
+		best             []int
+		bestMax, bestSum int
+		group            = make([]int, 0, k)
+	)
+
+	var visit func(start, max, sum int)
+	visit = func(start, max, sum int) {
+		// Adding devices never makes the furthest connection closer
+		if best != nil && max > bestMax {
+			return
+		}
+
+		if len(group) == k {
+			if best == nil || max < bestMax || (max == bestMax && sum < bestSum) {
+				best = append(best[:0], group...)
+				bestMax, bestSum = max, sum
+			}
+
+			return
+		}
+
+		for i := start; i <= n-(k-len(group)); i++ {
+			m, s := max, sum
+			for _, j := range group {
+				d := t.distance(i, j)
+				if d > m {
+					m = d
+				}
+
+				s += d
+			}
+
+			group = append(group, i)
+			visit(i+1, m, s)
+			group = group[:len(group)-1]
+		}
+	}
+
+	visit(0, 0, 0)
+	return best, nil
+}
+
+// String renders the topology as a table, like nvidia-smi topo -m.
+func (t *Topology) String() string {
+	buf := &bytes.Buffer{}
+	w := tabwriter.NewWriter(buf, 0, 0, 2, ' ', 0)
+
+	for i := range t.Devices {
+		fmt.Fprintf(w, "\tGPU%d", i)
+	}
+
+	fmt.Fprint(w, "\tCPU Affinity\n")
+
+	for i, d := range t.Devices {
+		fmt.Fprintf(w, "GPU%d", i)
+		for j := range t.Devices {
+			fmt.Fprintf(w, "\t%s", t.Label(i, j))
+		}
+
+		fmt.Fprintf(w, "\t%s\n", formatCPUs(d.CPUAffinity))
+	}
+
+	w.Flush()
+	return buf.String()
+}
+
+// MarshalJSON encodes the topology with levels as nvidia-smi topo abbreviations.
+func (t *Topology) MarshalJSON() ([]byte, error) {
+	levels := make([][]string, len(t.Levels))
+	for i := range t.Levels {
+		levels[i] = make([]string, len(t.Levels[i]))
+		for j, level := range t.Levels[i] {
+			levels[i][j] = level.String()
+		}
+	}
+
+	return json.Marshal(struct {
+		Devices     []TopologyDevice `json:"devices"`
+		Levels      [][]string       `json:"levels"`
+		NVLink      [][]bool         `json:"nvlink,omitempty"`
+		NVLinkCount [][]int          `json:"nvlinkCount,omitempty"`
+	}{t.Devices, levels, t.NVLink, t.NVLinkCount})
+}
+
+// formatCPUs formats a sorted list of CPUs as ranges (e.g. 0-15,32-47), N/A when empty.
+func formatCPUs(cpus []int) string {
+	if len(cpus) == 0 {
+		return "N/A"
+	}
+
+	var ranges []string
+	for start := 0; start < len(cpus); {
+		end := start
+		for end+1 < len(cpus) && cpus[end+1] == cpus[end]+1 {
+			end++
+		}
+
+		if start == end {
+			ranges = append(ranges, fmt.Sprintf("%d", cpus[start]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", cpus[start], cpus[end]))
+		}
+
+		start = end + 1
+	}
+
+	return strings.Join(ranges, ",")
+}
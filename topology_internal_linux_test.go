@@ -0,0 +1,36 @@
+// +build linux,cgo
+
+package nvml
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCPUSetSize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nvml-cpus")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "possible")
+	for _, tt := range []struct {
+		possible string
+		size     uint32
+	}{
+		{"0\n", 1},
+		{"0-63\n", 1},
+		{"0-64\n", 2},
+		{"0-127,192-255\n", 4},
+		{"0,2,1023\n", 16},
+		{"\n", defaultCPUSetSize},
+	} {
+		require.NoError(t, ioutil.WriteFile(path, []byte(tt.possible), 0644))
+		require.Equal(t, tt.size, cpuSetSize(path), tt.possible)
+	}
+
+	require.Equal(t, uint32(defaultCPUSetSize), cpuSetSize(filepath.Join(dir, "missing")))
+}
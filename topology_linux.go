@@ -0,0 +1,58 @@
+// +build linux,cgo
+
+package nvml
+
+import (
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// possibleCPUs lists the CPUs the system can ever have online (e.g. 0-255), regardless of the affinity mask or
+// cgroup of this process.
+const possibleCPUs = "/sys/devices/system/cpu/possible"
+
+// defaultCPUSetSize is the size of the CPU set, in 64-bit words, when possibleCPUs can't be read: 1024 CPUs,
+// like glibc's cpu_set_t.
+const defaultCPUSetSize = 1024 / 64
+
+// cpuSetSize returns the number of 64-bit words needed to hold a bit for every CPU listed in the given file,
+// formatted like possibleCPUs.
+func cpuSetSize(path string) uint32 {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return defaultCPUSetSize
+	}
+
+	// The list is sorted, the highest CPU number comes last
+	ranges := strings.FieldsFunc(strings.TrimSpace(string(data)), func(r rune) bool { return r == ',' || r == '-' })
+	if len(ranges) == 0 {
+		return defaultCPUSetSize
+	}
+
+	last, err := strconv.Atoi(ranges[len(ranges)-1])
+	if err != nil || last < 0 {
+		return defaultCPUSetSize
+	}
+
+	return uint32(last/64 + 1)
+}
+
+// cpuAffinity returns the CPUs with an ideal affinity for the device.
+func cpuAffinity(lib Interface, device Device) ([]int, error) {
+	cpuSet, err := lib.DeviceGetCPUAffinity(device, cpuSetSize(possibleCPUs))
+	if err != nil {
+		return nil, err
+	}
+
+	cpus := []int{}
+	for word, bits := range cpuSet {
+		for i := 0; i < 64; i++ {
+			if bits&(1<<uint(i)) != 0 {
+				cpus = append(cpus, word*64+i)
+			}
+		}
+	}
+
+	return cpus, nil
+}
@@ -0,0 +1,26 @@
+// +build linux,cgo
+
+package nvml_test
+
+import (
+	"testing"
+
+	nvml "github.com/mxpv/nvml-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopologyCPUAffinity(t *testing.T) {
+	f := createTopology(t)
+	defer f.Shutdown()
+
+	// Second socket of a dual-socket board
+	f.Devices[4].CPUAffinity = []uint64{0xffffffff00000000}
+
+	topology, err := nvml.NewTopology(f)
+	require.NoError(t, err)
+
+	require.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}, topology.Devices[0].CPUAffinity)
+	require.Len(t, topology.Devices[4].CPUAffinity, 32)
+	require.Equal(t, 32, topology.Devices[4].CPUAffinity[0])
+	require.Equal(t, 63, topology.Devices[4].CPUAffinity[31])
+}
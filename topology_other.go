@@ -0,0 +1,8 @@
+// +build !linux !cgo
+
+package nvml
+
+// cpuAffinity reports ErrNotSupported, as CPU affinity is only available on Linux.
+func cpuAffinity(lib Interface, device Device) ([]int, error) {
+	return nil, ErrNotSupported
+}
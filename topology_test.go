@@ -0,0 +1,144 @@
+package nvml_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	nvml "github.com/mxpv/nvml-go"
+	"github.com/mxpv/nvml-go/fake"
+	"github.com/stretchr/testify/require"
+)
+
+func createTopology(t *testing.T) *fake.Fake {
+	f, err := fake.Load("fake/testdata/dgx1.yaml")
+	require.NoError(t, err)
+	require.NoError(t, f.Init())
+
+	return f
+}
+
+func TestTopology(t *testing.T) {
+	f := createTopology(t)
+	defer f.Shutdown()
+
+	topology, err := nvml.NewTopology(f)
+	require.NoError(t, err)
+
+	require.Len(t, topology.Devices, 8)
+	require.Equal(t, "00000000:0A:00.0", topology.Devices[2].BusID)
+	require.Equal(t, fake.Handle(2), topology.Devices[2].Handle)
+	require.Equal(t, nvml.TopologyHostbridge, topology.Levels[1][3])
+	require.Equal(t, "SYS", topology.Label(0, 7))
+	require.Equal(t, "X", topology.Label(5, 5))
+
+	lines := strings.Split(strings.TrimSpace(topology.String()), "\n")
+	require.Len(t, lines, 9)
+	require.Equal(t, []string{"GPU0", "GPU1", "GPU2", "GPU3", "GPU4", "GPU5", "GPU6", "GPU7", "CPU", "Affinity"},
+		strings.Fields(lines[0]))
+	require.Equal(t, []string{"GPU4", "SYS", "SYS", "SYS", "SYS", "X", "PIX", "PHB", "PHB"},
+		strings.Fields(lines[5])[:9])
+
+	data, err := json.Marshal(topology)
+	require.NoError(t, err)
+
+	var decoded struct {
+		Devices []struct {
+			UUID string `json:"uuid"`
+		} `json:"devices"`
+		Levels [][]string `json:"levels"`
+	}
+
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, "GPU-6e2ea7ac-a0f7-7c30-b9a0-4a5a1b5dfa8c", decoded.Devices[0].UUID)
+	require.Equal(t, "PIX", decoded.Levels[2][3])
+}
+
+func TestTopologyNVLink(t *testing.T) {
+	f := createTopology(t)
+	defer f.Shutdown()
+
+	// Devices 0 to 3 are fully connected through NVLink, like the first half of a DGX-1 hybrid cube-mesh
+	nvlink := make([][]nvml.P2PStatus, 8)
+	for i := range nvlink {
+		nvlink[i] = make([]nvml.P2PStatus, 8)
+		for j := range nvlink[i] {
+			if i != j && (i >= 4 || j >= 4) {
+				nvlink[i][j] = nvml.P2PStatusNotSupported
+			}
+		}
+	}
+
+	f.P2P = map[nvml.P2PCapsIndex][][]nvml.P2PStatus{nvml.P2PCapsIndexNVLink: nvlink}
+
+	f.ConnectNvLinks(0, 1, 1)
+	f.ConnectNvLinks(0, 2, 1)
+	f.ConnectNvLinks(1, 2, 2)
+
+	topology, err := nvml.NewTopology(f)
+	require.NoError(t, err)
+
+	require.Equal(t, "NV2", topology.Label(1, 2))
+	require.Equal(t, "NV1", topology.Label(2, 0))
+	require.Equal(t, "PIX", topology.Label(4, 5))
+
+	// Devices 0 and 3 aren't linked directly
+	require.Equal(t, 0, topology.NVLinkCount[0][3])
+	require.Equal(t, "NV", topology.Label(0, 3))
+
+	group, err := topology.BestGroup(4)
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 1, 2, 3}, group)
+
+	group, err = topology.BestGroup(2)
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 1}, group)
+}
+
+func TestTopologyBestGroup(t *testing.T) {
+	f := createTopology(t)
+	defer f.Shutdown()
+
+	topology, err := nvml.NewTopology(f)
+	require.NoError(t, err)
+	require.False(t, topology.NVLink[0][1])
+
+	// PIX pairs are the tightest
+	group, err := topology.BestGroup(2)
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 1}, group)
+
+	// Stays on a single CPU socket
+	group, err = topology.BestGroup(4)
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 1, 2, 3}, group)
+
+	f.Topology[0][2], f.Topology[2][0] = nvml.TopologySystem, nvml.TopologySystem
+
+	topology, err = nvml.NewTopology(f)
+	require.NoError(t, err)
+
+	group, err = topology.BestGroup(4)
+	require.NoError(t, err)
+	require.Equal(t, []int{4, 5, 6, 7}, group)
+
+	group, err = topology.BestGroup(8)
+	require.NoError(t, err)
+	require.Len(t, group, 8)
+
+	_, err = topology.BestGroup(9)
+	require.Error(t, err)
+
+	_, err = topology.BestGroup(0)
+	require.Error(t, err)
+}
+
+func TestTopologyError(t *testing.T) {
+	f := createTopology(t)
+	defer f.Shutdown()
+
+	f.Devices[3].Errors["DeviceGetTopologyCommonAncestor"] = nvml.ErrGPULost
+
+	_, err := nvml.NewTopology(f)
+	require.Error(t, err)
+}
@@ -0,0 +1,283 @@
+package nvml
+
+import (
+	"C"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"sync"
+	"unsafe"
+
+	"github.com/pkg/errors"
+)
+
+// ErrNotRecorded is returned by a replayed API when a call doesn't match any of the recorded ones.
+var ErrNotRecorded = errors.New("No matching call was recorded")
+
+// maxArgumentSize bounds the memory an argument can refer to.
+const maxArgumentSize = 1 << 30
+
+// argument is a single argument of a NVML call.
+type argument struct {
+	// Value passed to NVML
+	value uintptr
+	// Memory the argument points to, nil for scalar arguments.
+	// It also keeps that memory alive while value is in use.
+	data []byte
+}
+
+// newArgument converts an argument passed to API.call.
+// Integers are passed by value. Pointers refer to the memory of the value they point to, slices to their elements and
+// C strings to the string including the terminating NUL. A nil pointer or an empty slice is passed as NULL.
+func newArgument(arg interface{}) argument {
+	switch v := arg.(type) {
+	case uintptr:
+		return argument{value: v}
+	case *C.char:
+		if v == nil {
+			return argument{}
+		}
+
+		p := unsafe.Pointer(v)
+		n := bytes.IndexByte((*[maxArgumentSize]byte)(p)[:maxArgumentSize:maxArgumentSize], 0) + 1
+		return argument{value: uintptr(p), data: (*[maxArgumentSize]byte)(p)[:n:n]}
+	}
+
+	rv := reflect.ValueOf(arg)
+
+	var size uintptr
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return argument{value: uintptr(rv.Int())}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return argument{value: uintptr(rv.Uint())}
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return argument{}
+		}
+
+		size = rv.Type().Elem().Size()
+	case reflect.Slice:
+		if rv.Len() == 0 {
+			return argument{}
+		}
+
+		size = uintptr(rv.Len()) * rv.Type().Elem().Size()
+	default:
+		panic(fmt.Sprintf("nvml: unsupported argument type %T", arg))
+	}
+
+	p := unsafe.Pointer(rv.Pointer())
+	return argument{value: uintptr(p), data: (*[maxArgumentSize]byte)(p)[:size:size]}
+}
+
+// invoke calls p with the given arguments and returns its nvmlReturn_t.
+func invoke(p proc, args []argument) uintptr {
+	values := make([]uintptr, len(args))
+	for i, arg := range args {
+		values[i] = arg.value
+	}
+
+	ret, _, _ := p.Call(values...)
+	runtime.KeepAlive(args)
+	return ret
+}
+
+// transport intercepts the calls made through API.call.
+type transport interface {
+	invoke(name string, p proc, args []argument) error
+}
+
+// recordingHeader is the first entry of a recording.
+type recordingHeader struct {
+	// Entry points of the recorded library, mapped to whether it exports them
+	Symbols map[string]bool `json:"symbols"`
+}
+
+// recordedCall is a single call captured by Record.
+type recordedCall struct {
+	// Name of the entry point exported by the library (e.g. nvmlDeviceGetPciInfo_v3)
+	Symbol string             `json:"symbol"`
+	Args   []recordedArgument `json:"args"`
+	// nvmlReturn_t
+	Return int `json:"return"`
+}
+
+// recordedArgument is an argument of a recordedCall. Pointer arguments are recorded by the memory they point to.
+type recordedArgument struct {
+	Value uint64 `json:"value,omitempty"`
+	// Memory before the call
+	In []byte `json:"in,omitempty"`
+	// Memory after the call, omitted when NVML didn't change it
+	Out []byte `json:"out,omitempty"`
+}
+
+// recorder forwards calls to the library and writes them to a recording.
+type recorder struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func (r *recorder) invoke(name string, p proc, args []argument) error {
+	call := recordedCall{Symbol: name, Args: make([]recordedArgument, len(args))}
+	for i, arg := range args {
+		if arg.data == nil {
+			call.Args[i].Value = uint64(arg.value)
+		} else {
+			call.Args[i].In = append([]byte{}, arg.data...)
+		}
+	}
+
+	call.Return = int(invoke(p, args))
+
+	for i, arg := range args {
+		if arg.data != nil && !bytes.Equal(arg.data, call.Args[i].In) {
+			call.Args[i].Out = append([]byte{}, arg.data...)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.enc.Encode(call); err != nil {
+		return errors.Wrapf(err, "failed to record %s call", name)
+	}
+
+	return returnValueToError(call.Return)
+}
+
+// Record writes every subsequent call made through a to w, so it can be played back later with Replay.
+// The recording holds the entry points exported by the library, followed by a JSON object per call with the
+// arguments, the memory NVML read and wrote and the returned nvmlReturn_t.
+// If a call can't be written, it returns the write error instead of the NVML one.
+func (a *API) Record(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(recordingHeader{Symbols: a.symbols}); err != nil {
+		return errors.Wrap(err, "failed to write recording header")
+	}
+
+	a.transport = &recorder{enc: enc}
+	return nil
+}
+
+// replayProc is an entry point of a recorded library. Calls are served by replayer.
+type replayProc string
+
+func (p replayProc) Call(args ...uintptr) (uintptr, uintptr, error) {
+	return 999, 0, ErrNotRecorded // NVML_ERROR_UNKNOWN
+}
+
+// replayLibrary exports the entry points listed in a recording header.
+type replayLibrary map[string]bool
+
+func (l replayLibrary) FindProc(name string) (proc, error) {
+	// nvmlErrorString returns a pointer to memory owned by the library, leave it to ErrorString fallback
+	if !l[name] || name == "nvmlErrorString" {
+		return nil, errors.Errorf("%s is not exported by the recorded library", name)
+	}
+
+	return replayProc(name), nil
+}
+
+func (l replayLibrary) Release() error {
+	return nil
+}
+
+// replayer serves calls from a recording.
+type replayer struct {
+	mu    sync.Mutex
+	calls []recordedCall
+	used  []bool
+}
+
+func (r *replayer) invoke(name string, p proc, args []argument) error {
+	if _, ok := p.(missingProc); ok {
+		return returnValueToError(int(invoke(p, args)))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	last := -1
+	for i, call := range r.calls {
+		if !r.matches(call, name, args) {
+			continue
+		}
+
+		if !r.used[i] {
+			last = i
+			break
+		}
+
+		last = i
+	}
+
+	if last == -1 {
+		return errors.Wrapf(ErrNotRecorded, "%s", name)
+	}
+
+	r.used[last] = true
+	call := r.calls[last]
+
+	for i, arg := range args {
+		if out := call.Args[i].Out; out != nil {
+			copy(arg.data, out)
+		}
+	}
+
+	return returnValueToError(call.Return)
+}
+
+func (r *replayer) matches(call recordedCall, name string, args []argument) bool {
+	if call.Symbol != name || len(call.Args) != len(args) {
+		return false
+	}
+
+	for i, arg := range args {
+		recorded := call.Args[i]
+		if arg.data == nil {
+			if recorded.In != nil || recorded.Value != uint64(arg.value) {
+				return false
+			}
+		} else if !bytes.Equal(recorded.In, arg.data) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Replay creates an API serving calls from a recording made with Record, without loading NVML.
+// The API exports the same entry points as the recorded library (except nvmlErrorString).
+// Each call is matched against the recorded calls of the same entry point with the same arguments (and memory passed
+// to NVML) in recording order. Once all matching calls are used, the last one is served again, so polling loops keep
+// seeing the last recorded state. Calls without a match fail with ErrNotRecorded.
+func Replay(r io.Reader) (*API, error) {
+	dec := json.NewDecoder(r)
+
+	header := recordingHeader{}
+	if err := dec.Decode(&header); err != nil {
+		return nil, errors.Wrap(err, "failed to read recording header")
+	}
+
+	rep := &replayer{}
+	for {
+		call := recordedCall{}
+		if err := dec.Decode(&call); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "failed to read recorded call %d", len(rep.calls))
+		}
+
+		rep.calls = append(rep.calls, call)
+	}
+
+	rep.used = make([]bool, len(rep.calls))
+
+	api := bind(replayLibrary(header.Symbols))
+	api.transport = rep
+	return api, nil
+}
@@ -0,0 +1,78 @@
+// +build linux,cgo
+
+package nvml
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordReplay(t *testing.T) {
+	w, err := New(newStubLibrary(t, stubSymbols()))
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	err = w.Record(buf)
+	require.NoError(t, err)
+
+	require.NoError(t, w.Init())
+
+	device, err := w.DeviceGetHandleByIndex(1)
+	require.NoError(t, err)
+
+	pci, err := w.DeviceGetPCIInfo(device)
+	require.NoError(t, err)
+
+	processes, err := w.DeviceGetComputeRunningProcesses(device)
+	require.NoError(t, err)
+
+	cpuSet, err := w.DeviceGetCPUAffinity(device, 2)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{0, 0xff00000000000003}, cpuSet)
+
+	version, err := w.SystemGetDriverVersion()
+	require.NoError(t, err)
+
+	_, err = w.DeviceGetHandleByUUID("GPU-1")
+	require.Equal(t, ErrNotSupported, err)
+
+	require.NoError(t, w.Shutdown())
+
+	r, err := Replay(buf)
+	require.NoError(t, err)
+
+	require.Equal(t, w.SymbolVersion("nvmlDeviceGetPciInfo"), r.SymbolVersion("nvmlDeviceGetPciInfo"))
+	require.NoError(t, r.Init())
+
+	result, err := r.DeviceGetHandleByIndex(1)
+	require.NoError(t, err)
+	require.Equal(t, device, result)
+
+	info, err := r.DeviceGetPCIInfo(result)
+	require.NoError(t, err)
+	require.Equal(t, pci, info)
+
+	list, err := r.DeviceGetComputeRunningProcesses(result)
+	require.NoError(t, err)
+	require.Equal(t, processes, list)
+
+	words, err := r.DeviceGetCPUAffinity(result, 2)
+	require.NoError(t, err)
+	require.Equal(t, cpuSet, words)
+
+	str, err := r.SystemGetDriverVersion()
+	require.NoError(t, err)
+	require.Equal(t, version, str)
+
+	_, err = r.DeviceGetHandleByUUID("GPU-1")
+	require.Equal(t, ErrNotSupported, err)
+
+	// Lookups are matched by the string passed to NVML
+	_, err = r.DeviceGetHandleByUUID("GPU-2")
+	require.Equal(t, ErrNotRecorded, errors.Cause(err))
+
+	require.NoError(t, r.Shutdown())
+}
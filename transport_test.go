@@ -0,0 +1,106 @@
+package nvml
+
+import (
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewArgument(t *testing.T) {
+	var value uint32
+	var memory Memory
+	buffer := [deviceNameBufferSize]byte{}
+	list := make([]uint64, 3)
+
+	tests := []struct {
+		name string
+		arg  interface{}
+		size int
+	}{
+		{"uintptr", uintptr(42), 0},
+		{"constant", deviceNameBufferSize, 0},
+		{"value", &value, 4},
+		{"struct", &memory, 24},
+		{"array", &buffer, deviceNameBufferSize},
+		{"slice", list, 24},
+		{"empty slice", []uint32{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			arg := newArgument(tt.arg)
+			require.Len(t, arg.data, tt.size)
+
+			if tt.size == 0 {
+				require.Nil(t, arg.data)
+			}
+		})
+	}
+
+	require.Equal(t, uintptr(42), newArgument(uintptr(42)).value)
+	require.Equal(t, uintptr(unsafe.Pointer(&value)), newArgument(&value).value)
+	require.Zero(t, newArgument([]uint32{}).value)
+}
+
+// recording simulates a GPU reading 65C once, then falling off the bus.
+const recording = `{"symbols":{"nvmlInit":true,"nvmlInit_v2":true,"nvmlShutdown":true,"nvmlErrorString":true,"nvmlDeviceGetCount":true,"nvmlDeviceGetCount_v2":true,"nvmlDeviceGetHandleByIndex":true,"nvmlDeviceGetTemperature":true,"nvmlDeviceGetFanSpeed":false}}
+{"symbol":"nvmlInit_v2","args":[],"return":0}
+{"symbol":"nvmlDeviceGetCount_v2","args":[{"in":"AAAAAA==","out":"AQAAAA=="}],"return":0}
+{"symbol":"nvmlDeviceGetHandleByIndex","args":[{},{"in":"AAAAAAAAAAA=","out":"ABAAAAAAAAA="}],"return":0}
+{"symbol":"nvmlDeviceGetTemperature","args":[{"value":4096},{},{"in":"AAAAAA==","out":"QQAAAA=="}],"return":0}
+{"symbol":"nvmlDeviceGetTemperature","args":[{"value":4096},{},{"in":"AAAAAA=="}],"return":15}
+{"symbol":"nvmlShutdown","args":[],"return":0}
+`
+
+func TestReplay(t *testing.T) {
+	w, err := Replay(strings.NewReader(recording))
+	require.NoError(t, err)
+
+	require.Equal(t, 2, w.SymbolVersion("nvmlInit"))
+	require.True(t, w.Supports("nvmlDeviceGetTemperature"))
+	require.False(t, w.Supports("nvmlDeviceGetFanSpeed"))
+	require.Contains(t, w.MissingSymbols(), "nvmlErrorString")
+
+	err = w.Init()
+	require.NoError(t, err)
+
+	count, err := w.DeviceGetCount()
+	require.NoError(t, err)
+	require.Equal(t, uint32(1), count)
+
+	device, err := w.DeviceGetHandleByIndex(0)
+	require.NoError(t, err)
+	require.Equal(t, Device(0x1000), device)
+
+	_, err = w.DeviceGetHandleByIndex(1)
+	require.Equal(t, ErrNotRecorded, errors.Cause(err))
+
+	temp, err := w.DeviceGetTemperature(device, TemperatureGPU)
+	require.NoError(t, err)
+	require.Equal(t, uint32(65), temp)
+
+	// The last recorded call is served again once all the others were used
+	for i := 0; i < 2; i++ {
+		_, err = w.DeviceGetTemperature(device, TemperatureGPU)
+		require.Equal(t, ErrGPULost, err)
+	}
+
+	_, err = w.DeviceGetFanSpeed(device)
+	require.Equal(t, ErrFunctionNotFound, err)
+
+	require.Equal(t, ErrGPULost.Error(), w.ErrorString(15))
+
+	err = w.Shutdown()
+	require.NoError(t, err)
+}
+
+func TestReplayInvalidRecording(t *testing.T) {
+	_, err := Replay(strings.NewReader(""))
+	require.Error(t, err)
+
+	_, err = Replay(strings.NewReader(`{"symbols":{}}` + "\n" + `{"symbol":`))
+	require.Error(t, err)
+}
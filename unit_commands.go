@@ -0,0 +1,10 @@
+package nvml
+
+// UnitSetLedState sets the LED state for the unit. The LED can be either green (0) or amber (1).
+// For S-class products. Requires root/admin permissions.
+// This operation takes effect immediately.
+// Current S-Class products don't provide unique LEDs for each unit. As such, both front and back LEDs will be toggled
+// in unison regardless of which unit is specified with this command.
+func (a API) UnitSetLedState(unit Unit, color LEDColor) error {
+	return a.call(a.nvmlUnitSetLedState, uintptr(unit), uintptr(color))
+}
@@ -0,0 +1,18 @@
+package nvml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitSetLedState(t *testing.T) {
+	w, unit := createUnit(t)
+	defer w.Shutdown()
+
+	state, err := w.UnitGetLedState(unit)
+	require.NoError(t, err)
+
+	err = w.UnitSetLedState(unit, state.Color)
+	require.NoError(t, err)
+}
@@ -0,0 +1,136 @@
+package nvml
+
+// #define NVML_UNIT_INFO_BUFFER_SIZE 96
+// #define NVML_UNIT_STRING_BUFFER_SIZE 256
+// #define NVML_UNIT_MAX_FANS 24
+//
+// typedef struct nvmlUnitInfo_st {
+//     char name[NVML_UNIT_INFO_BUFFER_SIZE]; //!< Product name
+//     char id[NVML_UNIT_INFO_BUFFER_SIZE]; //!< Product identifier
+//     char serial[NVML_UNIT_INFO_BUFFER_SIZE]; //!< Product serial number
+//     char firmwareVersion[NVML_UNIT_INFO_BUFFER_SIZE]; //!< Firmware version
+// } nvmlUnitInfo_t;
+//
+// typedef struct nvmlLedState_st {
+//     char cause[NVML_UNIT_STRING_BUFFER_SIZE]; //!< If amber, a text description of the cause
+//     int color; //!< GREEN or AMBER
+// } nvmlLedState_t;
+//
+// typedef struct nvmlPSUInfo_st {
+//     char state[NVML_UNIT_STRING_BUFFER_SIZE]; //!< The power supply state
+//     unsigned int current; //!< PSU current (A)
+//     unsigned int voltage; //!< PSU voltage (V)
+//     unsigned int power; //!< PSU power draw (W)
+// } nvmlPSUInfo_t;
+//
+// typedef struct nvmlUnitFanInfo_st {
+//     unsigned int speed; //!< Fan speed (RPM)
+//     int state; //!< Flag that indicates whether fan is working properly
+// } nvmlUnitFanInfo_t;
+//
+// typedef struct nvmlUnitFanSpeeds_st {
+//     nvmlUnitFanInfo_t fans[NVML_UNIT_MAX_FANS]; //!< Fan speed data for each fan
+//     unsigned int count; //!< Number of fans in unit
+// } nvmlUnitFanSpeeds_t;
+import "C"
+
+// UnitGetCount retrieves the number of units in the system.
+// For S-class products.
+func (a API) UnitGetCount() (unitCount uint32, err error) {
+	err = a.call(a.nvmlUnitGetCount, &unitCount)
+	return
+}
+
+// UnitGetHandleByIndex acquires the handle for a particular unit, based on its index.
+// For S-class products.
+// Valid indices are derived from the unitCount returned by UnitGetCount().
+// For example, if unitCount is 2 the valid indices are 0 and 1, corresponding to UNIT 0 and UNIT 1.
+// The order in which NVML enumerates units has no guarantees of consistency between reboots.
+func (a API) UnitGetHandleByIndex(index uint32) (unit Unit, err error) {
+	err = a.call(a.nvmlUnitGetHandleByIndex, uintptr(index), &unit)
+	return
+}
+
+// UnitGetUnitInfo retrieves the static information associated with a unit.
+// For S-class products.
+func (a API) UnitGetUnitInfo(unit Unit) (UnitInfo, error) {
+	info := C.nvmlUnitInfo_t{}
+	if err := a.call(a.nvmlUnitGetUnitInfo, uintptr(unit), &info); err != nil {
+		return UnitInfo{}, err
+	}
+
+	return UnitInfo{
+		Name:            C.GoString(&info.name[0]),
+		ID:              C.GoString(&info.id[0]),
+		Serial:          C.GoString(&info.serial[0]),
+		FirmwareVersion: C.GoString(&info.firmwareVersion[0]),
+	}, nil
+}
+
+// UnitGetLedState retrieves the LED state associated with this unit.
+// For S-class products.
+func (a API) UnitGetLedState(unit Unit) (LEDState, error) {
+	state := C.nvmlLedState_t{}
+	if err := a.call(a.nvmlUnitGetLedState, uintptr(unit), &state); err != nil {
+		return LEDState{}, err
+	}
+
+	return LEDState{
+		Cause: C.GoString(&state.cause[0]),
+		Color: LEDColor(state.color),
+	}, nil
+}
+
+// UnitGetPsuInfo retrieves the PSU stats for the unit.
+// For S-class products.
+func (a API) UnitGetPsuInfo(unit Unit) (PSUInfo, error) {
+	psu := C.nvmlPSUInfo_t{}
+	if err := a.call(a.nvmlUnitGetPsuInfo, uintptr(unit), &psu); err != nil {
+		return PSUInfo{}, err
+	}
+
+	return PSUInfo{
+		State:   C.GoString(&psu.state[0]),
+		Current: uint32(psu.current),
+		Voltage: uint32(psu.voltage),
+		Power:   uint32(psu.power),
+	}, nil
+}
+
+// UnitGetTemperature retrieves the temperature readings for the unit, in degrees C.
+// For S-class products.
+// Depending on the product, readings may be available for intake, exhaust and board.
+func (a API) UnitGetTemperature(unit Unit, sensorType UnitTemperatureSensor) (temp uint32, err error) {
+	err = a.call(a.nvmlUnitGetTemperature, uintptr(unit), uintptr(sensorType), &temp)
+	return
+}
+
+// UnitGetFanSpeedInfo retrieves the fan speed readings for the unit.
+// For S-class products.
+func (a API) UnitGetFanSpeedInfo(unit Unit) ([]UnitFanInfo, error) {
+	speeds := C.nvmlUnitFanSpeeds_t{}
+	if err := a.call(a.nvmlUnitGetFanSpeedInfo, uintptr(unit), &speeds); err != nil {
+		return nil, err
+	}
+
+	count := int(speeds.count)
+	if count > len(speeds.fans) {
+		count = len(speeds.fans)
+	}
+
+	list := make([]UnitFanInfo, count)
+	for i := range list {
+		list[i] = UnitFanInfo{
+			Speed: uint32(speeds.fans[i].speed),
+			State: FanState(speeds.fans[i].state),
+		}
+	}
+
+	return list, nil
+}
+
+// UnitGetDevices retrieves the set of GPU devices that are attached to the specified unit.
+// For S-class products.
+func (a API) UnitGetDevices(unit Unit) ([]Device, error) {
+	return a.deviceList(a.nvmlUnitGetDevices, uintptr(unit))
+}
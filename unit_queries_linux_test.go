@@ -0,0 +1,59 @@
+// +build linux,cgo
+
+package nvml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The unit entry points of the stub library, see newStubLibrary.
+func init() {
+	addStubFunctions(`
+typedef struct {
+	char name[96], id[96], serial[96], firmwareVersion[96];
+} unitInfo;
+
+typedef struct {
+	struct { unsigned int speed; int state; } fans[24];
+	unsigned int count;
+} unitFanSpeeds;
+`, map[string]string{
+		"nvmlUnitGetUnitInfo": `int nvmlUnitGetUnitInfo(void *unit, unitInfo *info) {
+			snprintf(info->name, sizeof(info->name), "S2050");
+			snprintf(info->firmwareVersion, sizeof(info->firmwareVersion), "6.2");
+			return 0;
+		}`,
+		"nvmlUnitGetFanSpeedInfo": `int nvmlUnitGetFanSpeedInfo(void *unit, unitFanSpeeds *speeds) {
+			speeds->fans[0].speed = 6200;
+			speeds->fans[1].speed = 0;
+			speeds->fans[1].state = 1;
+			speeds->count = 2;
+			return 0;
+		}`,
+		"nvmlUnitGetDevices": `int nvmlUnitGetDevices(void *unit, unsigned int *count, void **devices) {
+			if (*count < 2) { *count = 2; return 7; }
+			devices[0] = (void *)(uintptr_t)0x1000;
+			devices[1] = (void *)(uintptr_t)0x1001;
+			*count = 2;
+			return 0;
+		}`,
+	})
+}
+
+func TestUnitStub(t *testing.T) {
+	w := newStubAPI(t)
+
+	info, err := w.UnitGetUnitInfo(Unit(0x3000))
+	require.NoError(t, err)
+	require.Equal(t, UnitInfo{Name: "S2050", FirmwareVersion: "6.2"}, info)
+
+	fans, err := w.UnitGetFanSpeedInfo(Unit(0x3000))
+	require.NoError(t, err)
+	require.Equal(t, []UnitFanInfo{{Speed: 6200, State: FanNormal}, {Speed: 0, State: FanFailed}}, fans)
+
+	devices, err := w.UnitGetDevices(Unit(0x3000))
+	require.NoError(t, err)
+	require.Equal(t, []Device{0x1000, 0x1001}, devices)
+}
@@ -0,0 +1,83 @@
+package nvml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// createUnit returns the first unit of the system, skipping the test when there is none (not a S-class system).
+func createUnit(t *testing.T) (*API, Unit) {
+	w, _ := create(t)
+
+	count, err := w.UnitGetCount()
+	require.NoError(t, err)
+
+	if count == 0 {
+		w.Shutdown()
+		t.Skip("no S-class units found")
+	}
+
+	unit, err := w.UnitGetHandleByIndex(0)
+	require.NoError(t, err)
+
+	return w, unit
+}
+
+func TestUnitGetCount(t *testing.T) {
+	w, _ := create(t)
+	defer w.Shutdown()
+
+	_, err := w.UnitGetCount()
+	require.NoError(t, err)
+}
+
+func TestUnitGetUnitInfo(t *testing.T) {
+	w, unit := createUnit(t)
+	defer w.Shutdown()
+
+	info, err := w.UnitGetUnitInfo(unit)
+	require.NoError(t, err)
+	require.NotEmpty(t, info.Name)
+}
+
+func TestUnitGetLedState(t *testing.T) {
+	w, unit := createUnit(t)
+	defer w.Shutdown()
+
+	_, err := w.UnitGetLedState(unit)
+	require.NoError(t, err)
+}
+
+func TestUnitGetPsuInfo(t *testing.T) {
+	w, unit := createUnit(t)
+	defer w.Shutdown()
+
+	_, err := w.UnitGetPsuInfo(unit)
+	require.NoError(t, err)
+}
+
+func TestUnitGetTemperature(t *testing.T) {
+	w, unit := createUnit(t)
+	defer w.Shutdown()
+
+	_, err := w.UnitGetTemperature(unit, UnitTemperatureIntake)
+	require.NoError(t, err)
+}
+
+func TestUnitGetFanSpeedInfo(t *testing.T) {
+	w, unit := createUnit(t)
+	defer w.Shutdown()
+
+	_, err := w.UnitGetFanSpeedInfo(unit)
+	require.NoError(t, err)
+}
+
+func TestUnitGetDevices(t *testing.T) {
+	w, unit := createUnit(t)
+	defer w.Shutdown()
+
+	devices, err := w.UnitGetDevices(unit)
+	require.NoError(t, err)
+	require.NotEmpty(t, devices)
+}
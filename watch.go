@@ -0,0 +1,85 @@
+package nvml
+
+import (
+	"context"
+	"time"
+)
+
+// watchTimeout is how long Watch waits for an event before checking whether its context is done.
+const watchTimeout = 250 * time.Millisecond
+
+// Event is an event delivered by Watch. Err is set when waiting for events failed, it's the last value sent before
+// the channel is closed.
+type Event struct {
+	EventData
+	Err error
+}
+
+// Watch registers the devices for the events in mask and delivers them on the returned channel.
+// Event types a device doesn't support (see DeviceGetSupportedEventTypes) are skipped for that device, ErrNotSupported
+// is returned if none of the devices support any of them.
+// Events are waited for on a dedicated goroutine, which stops when ctx is done or waiting fails with an error other
+// than ErrTimeout (delivered as the last Event). The event set is then freed and the channel closed.
+// ctx should be canceled and the channel drained before shutting down NVML.
+func Watch(ctx context.Context, lib Interface, devices []Device, mask EventType) (<-chan Event, error) {
+	set, err := lib.EventSetCreate()
+	if err != nil {
+		return nil, err
+	}
+
+	registered := 0
+	for _, device := range devices {
+		supported, err := lib.DeviceGetSupportedEventTypes(device)
+		if err != nil {
+			set.Free()
+			return nil, err
+		}
+
+		if supported&mask == 0 {
+			continue
+		}
+
+		if err := set.Register(device, supported&mask); err != nil {
+			set.Free()
+			return nil, err
+		}
+
+		registered++
+	}
+
+	if registered == 0 {
+		set.Free()
+		return nil, ErrNotSupported
+	}
+
+	ch := make(chan Event)
+	go func() {
+		defer close(ch)
+		defer set.Free()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			data, err := set.Wait(watchTimeout)
+			if err == ErrTimeout {
+				continue
+			}
+
+			select {
+			case ch <- Event{EventData: data, Err: err}:
+			case <-ctx.Done():
+				return
+			}
+
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
@@ -0,0 +1,83 @@
+package nvml_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	nvml "github.com/mxpv/nvml-go"
+	"github.com/mxpv/nvml-go/fake"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch(t *testing.T) {
+	f := fake.New(2)
+	require.NoError(t, f.Init())
+	defer f.Shutdown()
+
+	f.Devices[1].SupportedEventTypes = nvml.EventTypePState
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mask := nvml.EventTypeXIDCriticalError | nvml.EventTypeDoubleBitECCError
+	ch, err := nvml.Watch(ctx, f, []nvml.Device{fake.Handle(0), fake.Handle(1)}, mask)
+	require.NoError(t, err)
+
+	xid := nvml.EventData{Device: fake.Handle(0), Type: nvml.EventTypeXIDCriticalError, XID: 48}
+	require.Equal(t, 1, f.SendEvent(xid))
+
+	// Device 1 doesn't support any of the events in the mask, so it's not registered
+	require.Zero(t, f.SendEvent(nvml.EventData{Device: fake.Handle(1), Type: nvml.EventTypeXIDCriticalError}))
+
+	select {
+	case event := <-ch:
+		require.NoError(t, event.Err)
+		require.Equal(t, xid, event.EventData)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+
+	cancel()
+
+	select {
+	case _, ok := <-ch:
+		require.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the watcher to stop")
+	}
+
+	// The event set was freed
+	require.Zero(t, f.SendEvent(xid))
+}
+
+func TestWatchError(t *testing.T) {
+	f := fake.New(1)
+	require.NoError(t, f.Init())
+	defer f.Shutdown()
+
+	f.Errors["EventSetWait"] = nvml.ErrGPULost
+
+	ch, err := nvml.Watch(context.Background(), f, []nvml.Device{fake.Handle(0)}, nvml.EventTypeAll)
+	require.NoError(t, err)
+
+	event, ok := <-ch
+	require.True(t, ok)
+	require.Equal(t, nvml.ErrGPULost, event.Err)
+
+	_, ok = <-ch
+	require.False(t, ok)
+}
+
+func TestWatchNotSupported(t *testing.T) {
+	f := fake.New(1)
+	require.NoError(t, f.Init())
+	defer f.Shutdown()
+
+	_, err := nvml.Watch(context.Background(), f, []nvml.Device{fake.Handle(0)}, nvml.EventTypePowerSourceChange)
+	require.Equal(t, nvml.ErrNotSupported, err)
+
+	f.Devices[0].Errors["DeviceGetSupportedEventTypes"] = nvml.ErrGPULost
+	_, err = nvml.Watch(context.Background(), f, []nvml.Device{fake.Handle(0)}, nvml.EventTypeAll)
+	require.Equal(t, nvml.ErrGPULost, err)
+}